@@ -0,0 +1,84 @@
+// Kafka 接入示例：从 topic 读取推送任务，失败的消息写入死信 topic
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/printfcoder/getui"
+	"github.com/printfcoder/getui/consumer"
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaSource 以 consumer group 方式读取，Ack 时提交 offset
+type kafkaSource struct {
+	reader *kafka.Reader
+	dlq    *kafka.Writer
+}
+
+type kafkaMessage struct {
+	src *kafkaSource
+	msg kafka.Message
+}
+
+func (s *kafkaSource) Receive(ctx context.Context) (consumer.Message, error) {
+	msg, err := s.reader.FetchMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaMessage{src: s, msg: msg}, nil
+}
+
+func (m *kafkaMessage) Body() []byte {
+	return m.msg.Value
+}
+
+func (m *kafkaMessage) Ack() error {
+	return m.src.reader.CommitMessages(context.Background(), m.msg)
+}
+
+// Nack Kafka 没有单条重投，写入死信 topic 后提交 offset
+func (m *kafkaMessage) Nack() error {
+	err := m.src.dlq.WriteMessages(context.Background(), kafka.Message{Key: m.msg.Key, Value: m.msg.Value})
+	if err != nil {
+		return err
+	}
+	return m.Ack()
+}
+
+func main() {
+	client, err := getui.Init(getui.InitParams{
+		AppID:        os.Getenv("GETUI_APP_ID"),
+		AppKey:       os.Getenv("GETUI_APP_KEY"),
+		MasterSecret: os.Getenv("GETUI_MASTER_SECRET"),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src := &kafkaSource{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: []string{"localhost:9092"},
+			GroupID: "getui-pusher",
+			Topic:   "push-jobs",
+		}),
+		dlq: &kafka.Writer{Addr: kafka.TCP("localhost:9092"), Topic: "push-jobs-dlq"},
+	}
+	defer src.reader.Close()
+	defer src.dlq.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := consumer.New(client, src, consumer.Options{
+		OnError: func(body []byte, err error) {
+			log.Printf("推送失败: %s, body: %s", err, body)
+		},
+	})
+	if err := c.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
+	client.Shutdown(context.Background())
+}
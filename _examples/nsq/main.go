@@ -0,0 +1,86 @@
+// NSQ 接入示例：go-nsq 为推模式，通过 channel 转为 consumer.Source
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/nsqio/go-nsq"
+	"github.com/printfcoder/getui"
+	"github.com/printfcoder/getui/consumer"
+)
+
+// nsqSource 关闭自动响应，由 Ack/Nack 决定 Finish 或 Requeue
+type nsqSource struct {
+	messages chan *nsq.Message
+}
+
+type nsqMessage struct {
+	msg *nsq.Message
+}
+
+func (s *nsqSource) HandleMessage(m *nsq.Message) error {
+	m.DisableAutoResponse()
+	s.messages <- m
+	return nil
+}
+
+func (s *nsqSource) Receive(ctx context.Context) (consumer.Message, error) {
+	select {
+	case m := <-s.messages:
+		return &nsqMessage{msg: m}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (m *nsqMessage) Body() []byte {
+	return m.msg.Body
+}
+
+func (m *nsqMessage) Ack() error {
+	m.msg.Finish()
+	return nil
+}
+
+func (m *nsqMessage) Nack() error {
+	m.msg.Requeue(-1)
+	return nil
+}
+
+func main() {
+	client, err := getui.Init(getui.InitParams{
+		AppID:        os.Getenv("GETUI_APP_ID"),
+		AppKey:       os.Getenv("GETUI_APP_KEY"),
+		MasterSecret: os.Getenv("GETUI_MASTER_SECRET"),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src := &nsqSource{messages: make(chan *nsq.Message)}
+	q, err := nsq.NewConsumer("push-jobs", "getui-pusher", nsq.NewConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	q.AddHandler(src)
+	if err := q.ConnectToNSQLookupd("localhost:4161"); err != nil {
+		log.Fatal(err)
+	}
+	defer q.Stop()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := consumer.New(client, src, consumer.Options{
+		OnError: func(body []byte, err error) {
+			log.Printf("推送失败: %s, body: %s", err, body)
+		},
+	})
+	if err := c.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
+	client.Shutdown(context.Background())
+}
@@ -0,0 +1,176 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AdaptiveOptions 自适应并发配置，按 AIMD（加性增、乘性减）调整并发数：
+// 请求成功且耗时不超过 LatencyTarget 时逐步增加并发，遇到限流、网络错误或耗时超标时按 Backoff 成倍降低
+type AdaptiveOptions struct {
+	// MinConcurrency 最小并发数 默认1
+	MinConcurrency int
+	// MaxConcurrency 最大并发数 默认32
+	MaxConcurrency int
+	// InitialConcurrency 初始并发数 默认 MinConcurrency
+	InitialConcurrency int
+	// LatencyTarget 单个请求耗时超过该值视为过载 默认1秒
+	LatencyTarget time.Duration
+	// Backoff 过载时并发数乘以该系数，需在0到1之间 默认0.5
+	Backoff float64
+	// OnAdjust 并发数变化时调用，可用于上报指标，不能阻塞
+	OnAdjust func(concurrency int)
+}
+
+// aimdLimiter AIMD并发控制
+type aimdLimiter struct {
+	opts AdaptiveOptions
+
+	mu       sync.Mutex
+	limit    float64
+	inflight int
+	// lastDecrease 上次降低并发的时间，同一批过载的请求只降低一次
+	lastDecrease time.Time
+	// changed 并发数或进行中的请求数变化时关闭并替换
+	changed chan struct{}
+}
+
+func newAIMDLimiter(opts AdaptiveOptions) *aimdLimiter {
+	if opts.MinConcurrency <= 0 {
+		opts.MinConcurrency = 1
+	}
+	if opts.MaxConcurrency <= 0 {
+		opts.MaxConcurrency = 32
+	}
+	if opts.MaxConcurrency < opts.MinConcurrency {
+		opts.MaxConcurrency = opts.MinConcurrency
+	}
+	if opts.InitialConcurrency < opts.MinConcurrency {
+		opts.InitialConcurrency = opts.MinConcurrency
+	}
+	if opts.InitialConcurrency > opts.MaxConcurrency {
+		opts.InitialConcurrency = opts.MaxConcurrency
+	}
+	if opts.LatencyTarget <= 0 {
+		opts.LatencyTarget = time.Second
+	}
+	if opts.Backoff <= 0 || opts.Backoff >= 1 {
+		opts.Backoff = 0.5
+	}
+	return &aimdLimiter{opts: opts, limit: float64(opts.InitialConcurrency), changed: make(chan struct{})}
+}
+
+// acquire 等待空闲的并发名额或ctx结束
+func (l *aimdLimiter) acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inflight < int(l.limit) {
+			l.inflight++
+			l.mu.Unlock()
+			return nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release 归还名额，并按本次请求的耗时与错误调整并发数
+func (l *aimdLimiter) release(latency time.Duration, err error) {
+	l.mu.Lock()
+	l.inflight--
+	before := int(l.limit)
+
+	now := time.Now()
+	switch {
+	case overloaded(err) || latency > l.opts.LatencyTarget:
+		if now.Sub(l.lastDecrease) >= l.opts.LatencyTarget {
+			l.limit *= l.opts.Backoff
+			if l.limit < float64(l.opts.MinConcurrency) {
+				l.limit = float64(l.opts.MinConcurrency)
+			}
+			l.lastDecrease = now
+		}
+	case err == nil:
+		// 每轮（约 limit 个请求）成功后并发数加1
+		l.limit += 1 / l.limit
+		if l.limit > float64(l.opts.MaxConcurrency) {
+			l.limit = float64(l.opts.MaxConcurrency)
+		}
+	}
+
+	after := int(l.limit)
+	close(l.changed)
+	l.changed = make(chan struct{})
+	l.mu.Unlock()
+
+	if after != before && l.opts.OnAdjust != nil {
+		l.opts.OnAdjust(after)
+	}
+}
+
+// overloaded 是否为个推过载的信号：限流或网络错误等临时故障，ctx 结束不算
+func overloaded(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return errors.Is(err, ErrRateLimited) || isTransient(err)
+}
+
+// PushToSingleAdaptive 并发发送多条单推，并发数按 opts 自适应调整，每条为一个分片
+// 与 PushToSingleBulk 相同，ctx 取消后不再发送剩余的单推；返回的结果中各状态按序号排列
+func (c *client) PushToSingleAdaptive(ctx context.Context, bodies []SingleReqBody, opts AdaptiveOptions) (*ChunkedResult, error) {
+
+	c, err := c.forApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[PushToSingleAdaptive] %w", err)
+	}
+
+	limiter := newAIMDLimiter(opts)
+	ret := &ChunkedResult{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, body := range bodies {
+		chunk := ChunkResult{Index: i, Targets: singleTargets(body)}
+
+		if ctx.Err() != nil || limiter.acquire(ctx) != nil {
+			ret.NotAttempted = append(ret.NotAttempted, chunk)
+			continue
+		}
+
+		wg.Add(1)
+		go func(body SingleReqBody, chunk ChunkResult) {
+			defer wg.Done()
+
+			start := time.Now()
+			chunk.Ret, chunk.Err = c.pushToSingle(ctx, body)
+			limiter.release(time.Since(start), chunk.Err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if chunk.Err != nil {
+				ret.Failed = append(ret.Failed, chunk)
+				return
+			}
+			ret.Sent = append(ret.Sent, chunk)
+		}(body, chunk)
+	}
+	wg.Wait()
+
+	for _, chunks := range [][]ChunkResult{ret.Sent, ret.Failed, ret.NotAttempted} {
+		sort.Slice(chunks, func(i, j int) bool { return chunks[i].Index < chunks[j].Index })
+	}
+	if err := ret.err(ctx); err != nil {
+		return ret, fmt.Errorf("[PushToSingleAdaptive] %w", err)
+	}
+	return ret, nil
+}
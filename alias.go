@@ -0,0 +1,220 @@
+package getui
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// maxAliasBatch 个推单次绑定别名的上限
+const maxAliasBatch = 1000
+
+// AliasBinding cid与别名的绑定关系
+type AliasBinding struct {
+	CID   string `json:"cid"`
+	Alias string `json:"alias"`
+}
+
+// BindAlias 批量绑定别名，单次最多1000个
+// 参考资料 http://docs.getui.com/server/rest/user/#1
+func (c *client) BindAlias(list []AliasBinding) (ret *RspBody, err error) {
+
+	if len(list) == 0 || len(list) > maxAliasBatch {
+		return nil, fmt.Errorf("[BindAlias] 错误的绑定数量 %d, 单次需在1到%d之间", len(list), maxAliasBatch)
+	}
+	for _, b := range list {
+		err = c.checkValid(context.Background(), "BindAlias", c.validateCIDs(b.CID))
+		if err != nil {
+			return nil, fmt.Errorf("[BindAlias] %w", err)
+		}
+	}
+
+	body := struct {
+		AliasList []AliasBinding `json:"alias_list"`
+	}{list}
+
+	ret, err = doRequest[RspBody](context.Background(), c, "POST", "bind_alias", body)
+	if err != nil {
+		return nil, fmt.Errorf("[BindAlias] 发送 绑定别名 失败, err: %w", err)
+	}
+
+	return
+}
+
+// AliasImportOptions 批量导入别名配置
+type AliasImportOptions struct {
+	// BatchSize 每次绑定的数量 默认且最大1000
+	BatchSize int
+	// Concurrency 并发请求数 默认4
+	Concurrency int
+	// HasHeader 第一行为表头时跳过
+	HasHeader bool
+	// OnFailure 逐行回调失败记录；为空时失败记录收集到 AliasImportResult.Failures
+	OnFailure func(AliasImportFailure)
+}
+
+// AliasImportFailure 导入失败的行
+type AliasImportFailure struct {
+	// Line 在输入中的行号，从1开始
+	Line  int
+	CID   string
+	Alias string
+	Err   error
+}
+
+// AliasImportResult 导入结果
+type AliasImportResult struct {
+	Total     int
+	Succeeded int
+	Failed    int
+	Failures  []AliasImportFailure
+}
+
+type aliasRow struct {
+	line int
+	AliasBinding
+}
+
+// ImportAliases 从 r 流式读取 cid,alias 的CSV行，分批并发绑定
+// 格式错误、cid校验不通过的行在分批前逐行记为失败，不影响同批的其它行；绑定失败的批次逐行记为失败；
+// ctx结束时停止读取并返回已处理部分的结果
+// c 为 New 创建的客户端时cid校验遵循其 SkipCIDValidation 与 ValidationMode，否则按 ValidateCID 校验
+func ImportAliases(ctx context.Context, c Client, r io.Reader, opts AliasImportOptions) (*AliasImportResult, error) {
+	if opts.BatchSize <= 0 || opts.BatchSize > maxAliasBatch {
+		opts.BatchSize = maxAliasBatch
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+
+	validate := importValidator(ctx, c)
+	ret := &AliasImportResult{}
+	var mu sync.Mutex
+	fail := func(f AliasImportFailure) {
+		mu.Lock()
+		defer mu.Unlock()
+		ret.Failed++
+		if opts.OnFailure != nil {
+			opts.OnFailure(f)
+			return
+		}
+		ret.Failures = append(ret.Failures, f)
+	}
+
+	// 绑定协程
+	batches := make(chan []aliasRow)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				list := make([]AliasBinding, len(batch))
+				for i, row := range batch {
+					list[i] = row.AliasBinding
+				}
+
+				_, err := c.BindAlias(list)
+				if err != nil {
+					for _, row := range batch {
+						fail(AliasImportFailure{Line: row.line, CID: row.CID, Alias: row.Alias, Err: err})
+					}
+					continue
+				}
+
+				mu.Lock()
+				ret.Succeeded += len(batch)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// 读取CSV
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	var readErr error
+	batch := make([]aliasRow, 0, opts.BatchSize)
+	line := 0
+	send := func() bool {
+		if len(batch) == 0 {
+			return true
+		}
+		select {
+		case batches <- batch:
+			batch = make([]aliasRow, 0, opts.BatchSize)
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for ctx.Err() == nil {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				readErr = fmt.Errorf("[ImportAliases] 读取输入失败, err: %w", err)
+				break
+			}
+			ret.Total++
+			fail(AliasImportFailure{Line: line, Err: err})
+			continue
+		}
+		if line == 1 && opts.HasHeader {
+			continue
+		}
+
+		ret.Total++
+		if len(record) != 2 || len(strings.TrimSpace(record[0])) == 0 || len(strings.TrimSpace(record[1])) == 0 {
+			fail(AliasImportFailure{Line: line, Err: fmt.Errorf("格式错误, 需为 cid,alias: %q", strings.Join(record, ","))})
+			continue
+		}
+
+		row := aliasRow{line: line, AliasBinding: AliasBinding{CID: strings.TrimSpace(record[0]), Alias: strings.TrimSpace(record[1])}}
+		if err := validate(row.CID); err != nil {
+			fail(AliasImportFailure{Line: line, CID: row.CID, Alias: row.Alias, Err: err})
+			continue
+		}
+
+		batch = append(batch, row)
+		if len(batch) >= opts.BatchSize && !send() {
+			break
+		}
+	}
+	if readErr == nil {
+		send()
+	}
+
+	close(batches)
+	wg.Wait()
+
+	if readErr != nil {
+		return ret, readErr
+	}
+	if ctx.Err() != nil {
+		return ret, fmt.Errorf("[ImportAliases] 导入未完成, err: %w", ctx.Err())
+	}
+	return ret, nil
+}
+
+// importValidator 导入时逐行校验cid，避免一个错误的cid导致整批绑定失败
+func importValidator(ctx context.Context, c Client) func(cid string) error {
+	cl, ok := c.(*client)
+	if !ok {
+		return ValidateCID
+	}
+	return func(cid string) error {
+		return cl.checkValid(ctx, "ImportAliases", cl.validateCIDs(cid))
+	}
+}
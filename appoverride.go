@@ -0,0 +1,95 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+)
+
+// AppDescriptor 单次调用使用的应用，见 WithApp
+type AppDescriptor struct {
+	AppID        string
+	AppKey       string
+	MasterSecret string
+}
+
+// appOverrideKey context中应用的key
+type appOverrideKey struct{}
+
+// WithApp 在ctx中指定本次推送使用的应用，覆盖客户端默认的 AppID、AppKey、MasterSecret
+// 用于大量配置相同、只有凭证不同的白标应用。客户端为每个应用创建内部客户端并各自管理token，
+// 除凭证外的配置（HTTPClient、重试、MaxInflight 配额等）与默认应用共用。
+// 支持的方法：PushToSingleContext、PushToListContext、PushToAppContext、SilentPush、
+// PushToListChunked、PushToSingleBulk、PushToSingleAdaptive、PushPersonalized、SplitPush；AppID 与默认应用相同时不生效
+func WithApp(ctx context.Context, app AppDescriptor) context.Context {
+	return context.WithValue(ctx, appOverrideKey{}, app)
+}
+
+// AppFromContext 取出ctx中通过 WithApp 指定的应用
+func AppFromContext(ctx context.Context) (AppDescriptor, bool) {
+	app, ok := ctx.Value(appOverrideKey{}).(AppDescriptor)
+	return app, ok
+}
+
+// forApp ctx中指定了其它应用时返回该应用的客户端，首次使用时创建并鉴权；否则返回c
+func (c *client) forApp(ctx context.Context) (*client, error) {
+	app, ok := AppFromContext(ctx)
+	if !ok || app.AppID == c.AppID {
+		return c, nil
+	}
+	if len(app.AppID) == 0 || len(app.AppKey) == 0 || len(app.MasterSecret) == 0 {
+		return nil, fmt.Errorf("[WithApp] AppID、AppKey、MasterSecret 均不能为空")
+	}
+
+	c.appsMu.Lock()
+	defer c.appsMu.Unlock()
+
+	if c.closedApps {
+		return nil, ErrClientClosed
+	}
+	if sub, ok := c.apps[app.AppID]; ok {
+		return sub, nil
+	}
+
+	parms := c.InitParams
+	parms.AppID = app.AppID
+	parms.AppKey = app.AppKey
+	parms.MasterSecret = app.MasterSecret
+	parms.CredentialsProvider = nil
+	if c.localSigner {
+		parms.Signer = nil
+	}
+	// HTTPClient 已按 DialContext 创建，直接共用
+	parms.DialContext = nil
+
+	sub, err := newClient(parms)
+	if err != nil {
+		return nil, fmt.Errorf("[WithApp] 应用 %s 初始化失败, err: %w", app.AppID, err)
+	}
+	sub.limiter = c.limiter
+	sub.concurrency = c.concurrency
+	sub.retryBudget = c.retryBudget
+
+	if c.apps == nil {
+		c.apps = map[string]*client{}
+	}
+	c.apps[app.AppID] = sub
+	return sub, nil
+}
+
+// shutdownApps 关闭 WithApp 创建的客户端
+func (c *client) shutdownApps(ctx context.Context) error {
+	c.appsMu.Lock()
+	c.closedApps = true
+	apps := c.apps
+	c.apps = nil
+	c.appsMu.Unlock()
+
+	var firstErr error
+	for id, sub := range apps {
+		err := sub.Shutdown(ctx)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("应用 %s: %w", id, err)
+		}
+	}
+	return firstErr
+}
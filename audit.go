@@ -0,0 +1,240 @@
+package getui
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrAuditTampered 审计记录的序号不连续或哈希不匹配，记录被删除、插入或修改
+var ErrAuditTampered = errors.New("getui: 审计记录被篡改")
+
+// maxAuditTargets 审计记录中保留的目标个数，其余只计数
+const maxAuditTargets = 5
+
+// AuditRecord 一次推送操作的审计记录
+// Seq 从1开始连续递增，Hash 为 PrevHash 与记录内容的 sha256，删除、插入或修改任意一条都会被 VerifyAuditChain 发现
+type AuditRecord struct {
+	Seq      uint64    `json:"seq"`
+	Time     time.Time `json:"time"`
+	Service  string    `json:"service,omitempty"`
+	Actor    string    `json:"actor,omitempty"`
+	AppID    string    `json:"appid"`
+	Endpoint string    `json:"endpoint"`
+	// Template 消息模板或活动名，见 WithAudit
+	Template  string `json:"template,omitempty"`
+	RequestID string `json:"requestid,omitempty"`
+	TaskID    string `json:"taskid,omitempty"`
+	// TargetCount 目标总数，toapp、按标签推送为0
+	TargetCount int `json:"target_count"`
+	// Targets 前5个目标，其余只计数
+	Targets []string `json:"targets,omitempty"`
+	Result  string   `json:"result"`
+	Error   string   `json:"error,omitempty"`
+	// PrevHash 上一条记录的 Hash，第一条为空
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash"`
+}
+
+// hash 记录内容（不含 Hash）与 PrevHash 的sha256
+func (r AuditRecord) hash() string {
+	r.Hash = ""
+	data, _ := json.Marshal(r)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditSink 审计记录的存储，如文件、数据库、日志平台
+// WriteAudit 按序号顺序调用，不会并发调用
+type AuditSink interface {
+	WriteAudit(ctx context.Context, r AuditRecord) error
+}
+
+// AuditSinkFunc 函数形式的 AuditSink
+type AuditSinkFunc func(ctx context.Context, r AuditRecord) error
+
+// WriteAudit 实现 AuditSink
+func (f AuditSinkFunc) WriteAudit(ctx context.Context, r AuditRecord) error {
+	return f(ctx, r)
+}
+
+// jsonAuditSink 每条记录写入一行JSON
+type jsonAuditSink struct {
+	w io.Writer
+}
+
+// NewJSONAuditSink 每条审计记录写入一行JSON，可用 ReadAuditLog 读回校验
+func NewJSONAuditSink(w io.Writer) AuditSink {
+	return &jsonAuditSink{w: w}
+}
+
+func (s *jsonAuditSink) WriteAudit(ctx context.Context, r AuditRecord) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+// ReadAuditLog 读取 NewJSONAuditSink 写入的审计记录
+func ReadAuditLog(r io.Reader) ([]AuditRecord, error) {
+	var ret []AuditRecord
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return ret, fmt.Errorf("[ReadAuditLog] 第%d条记录解析失败, err: %w", len(ret)+1, err)
+		}
+		ret = append(ret, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return ret, fmt.Errorf("[ReadAuditLog] 读取失败, err: %w", err)
+	}
+	return ret, nil
+}
+
+// VerifyAuditChain 校验审计记录的序号连续、哈希链完整
+// records 可以是完整日志中连续的一段，第一条的 PrevHash 不做校验
+func VerifyAuditChain(records []AuditRecord) error {
+	for i, r := range records {
+		if r.Hash != r.hash() {
+			return fmt.Errorf("[VerifyAuditChain] seq %d 内容与哈希不符, err: %w", r.Seq, ErrAuditTampered)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := records[i-1]
+		if r.Seq != prev.Seq+1 {
+			return fmt.Errorf("[VerifyAuditChain] seq %d 之后为 %d, err: %w", prev.Seq, r.Seq, ErrAuditTampered)
+		}
+		if r.PrevHash != prev.Hash {
+			return fmt.Errorf("[VerifyAuditChain] seq %d 与上一条的哈希不符, err: %w", r.Seq, ErrAuditTampered)
+		}
+	}
+	return nil
+}
+
+// AuditorOptions 审计配置
+type AuditorOptions struct {
+	// Service 调用方服务名，记录在每条审计记录中
+	Service string
+	// Last 上次运行写入的最后一条记录，重启后接着其序号与哈希继续 默认从序号1开始
+	Last *AuditRecord
+	// OnError 写入 Sink 失败时调用，可为空；写入失败不影响推送，失败的记录不进入哈希链，其序号由下一条记录使用
+	OnError func(r AuditRecord, err error)
+}
+
+// Auditor 为推送操作分配序号与哈希并写入 AuditSink，可在多个客户端间共用以得到一条哈希链
+type Auditor struct {
+	sink    AuditSink
+	service string
+	onError func(AuditRecord, error)
+
+	mu       sync.Mutex
+	seq      uint64
+	lastHash string
+}
+
+// NewAuditor 创建审计器
+func NewAuditor(sink AuditSink, opts AuditorOptions) *Auditor {
+	a := &Auditor{sink: sink, service: opts.Service, onError: opts.OnError}
+	if opts.Last != nil {
+		a.seq, a.lastHash = opts.Last.Seq, opts.Last.Hash
+	}
+	return a
+}
+
+// Record 分配序号与哈希并写入 Sink，返回写入的记录
+// 写入失败时序号与哈希链不推进，Sink 中的记录始终连续，VerifyAuditChain 报告的不连续只可能来自篡改
+func (a *Auditor) Record(ctx context.Context, r AuditRecord) (AuditRecord, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	r.Seq = a.seq + 1
+	if len(r.Service) == 0 {
+		r.Service = a.service
+	}
+	r.PrevHash = a.lastHash
+	r.Hash = r.hash()
+
+	err := a.sink.WriteAudit(ctx, r)
+	if err != nil {
+		if a.onError != nil {
+			a.onError(r, err)
+		}
+		return r, fmt.Errorf("[Auditor] 写入审计记录 %d 失败, err: %w", r.Seq, err)
+	}
+	a.seq, a.lastHash = r.Seq, r.Hash
+	return r, nil
+}
+
+// auditKey context中审计信息的key
+type auditKey struct{}
+
+// AuditInfo 调用方附加到审计记录中的信息
+type AuditInfo struct {
+	// Actor 发起推送的人或服务，如运营账号、job名
+	Actor string
+	// Template 消息模板或活动名
+	Template string
+}
+
+// WithAudit 在ctx中设置审计信息，通过 ...Context 等带ctx的方法推送时记录到审计记录中
+func WithAudit(ctx context.Context, info AuditInfo) context.Context {
+	return context.WithValue(ctx, auditKey{}, info)
+}
+
+// AuditFromContext 取出ctx中的审计信息
+func AuditFromContext(ctx context.Context) AuditInfo {
+	info, _ := ctx.Value(auditKey{}).(AuditInfo)
+	return info
+}
+
+// auditPush 推送完成后写入审计记录，成功与失败都记录；未配置 Auditor 时不做任何事
+// 推送已发出，ctx 取消或超时也要写入记录；写入失败只记录日志，不影响推送结果
+func (c *client) auditPush(ctx context.Context, endpoint string, start time.Time, requestID string, targets []string, targetCount int, ret *RspBody, err error) {
+	if c.Auditor == nil {
+		return
+	}
+
+	info := AuditFromContext(ctx)
+	r := AuditRecord{
+		Time:        start,
+		Actor:       info.Actor,
+		AppID:       c.AppID,
+		Endpoint:    endpoint,
+		Template:    info.Template,
+		RequestID:   requestID,
+		TargetCount: targetCount,
+	}
+	if len(targets) > maxAuditTargets {
+		targets = targets[:maxAuditTargets]
+	}
+	r.Targets = append([]string(nil), targets...)
+	if ret != nil {
+		r.TaskID = ret.TaskID
+		r.Result = ret.Result
+	}
+	if err != nil {
+		r.Error = c.redact(err.Error())
+		if len(r.Result) == 0 {
+			r.Result = "error"
+		}
+	}
+
+	if _, werr := c.Auditor.Record(context.WithoutCancel(ctx), r); werr != nil {
+		c.log(ctx, LogError, "写入审计记录失败", LogField{"endpoint", endpoint}, LogField{"requestid", requestID}, LogField{"err", werr.Error()})
+	}
+}
@@ -0,0 +1,448 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultAuthExpireMargin 默认在token过期前10分钟刷新
+	defaultAuthExpireMargin = 10 * time.Minute
+	// defaultAuthRefreshJitter 默认随机提前至多5分钟刷新
+	defaultAuthRefreshJitter = 5 * time.Minute
+	// minRefreshInterval 两次刷新的最小间隔，避免刷新失败时频繁请求
+	minRefreshInterval = time.Minute
+	// defaultAuthHeartbeat 默认刷新周期
+	defaultAuthHeartbeat = 20 * time.Hour
+	// maxAuthHeartbeat 个推token的有效期，刷新周期不能超过它
+	maxAuthHeartbeat = 24 * time.Hour
+)
+
+// authHeartbeat 校验刷新周期，0 时使用默认值
+// 旧版本按小时数使用该字段（AuthHeartbeat: 20），不超过24的值仍视为小时数
+func authHeartbeat(d time.Duration) (time.Duration, error) {
+	if d == 0 {
+		return defaultAuthHeartbeat, nil
+	}
+	if d > 0 && d <= maxAuthHeartbeat/time.Hour {
+		d *= time.Hour
+	}
+	if d < minRefreshInterval || d > maxAuthHeartbeat {
+		return 0, fmt.Errorf("AuthHeartbeat %s 超出范围, 需在 [%s, %s] 之间", d, minRefreshInterval, maxAuthHeartbeat)
+	}
+	return d, nil
+}
+
+// AuthToken 客户端-token，需要取得时间与刷新错误时使用 TokenInfo
+func (c *client) AuthToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.authToken
+}
+
+// AuthTokenExpireTime 客户端-token过期时间，个推未返回时为零值
+func (c *client) AuthTokenExpireTime() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.authExpireTime
+}
+
+func (c *client) init() (err error) {
+
+	// LazyAuth 时首次请求再申请token
+	if c.LazyAuth {
+		return nil
+	}
+
+	// 申请token
+	err = c.startupAuth()
+	if err != nil {
+		return err
+	}
+
+	// 定时刷新token
+	c.startRefreshLoop()
+
+	return nil
+}
+
+// startRefreshLoop 启动定时刷新，只启动一次
+func (c *client) startRefreshLoop() {
+	c.refreshLoopOnce.Do(func() { go c.refreshLoop() })
+}
+
+// Authenticate 确保已有token，没有时申请并启动定时刷新；已有token时不做任何事
+// 配合 LazyAuth 使用，可在服务就绪前主动完成鉴权
+func (c *client) Authenticate(ctx context.Context) error {
+	err := c.ensureAuth(ctx)
+	if err != nil {
+		return fmt.Errorf("[Authenticate] 申请token失败, err: %w", err)
+	}
+	return nil
+}
+
+// ensureAuth 没有token时申请token并启动定时刷新
+func (c *client) ensureAuth(ctx context.Context) error {
+	if len(c.AuthToken()) > 0 {
+		return nil
+	}
+
+	c.credMu.Lock()
+	defer c.credMu.Unlock()
+	if len(c.AuthToken()) > 0 {
+		return nil
+	}
+	err := c.refreshAuth(ctx, false)
+	if err != nil {
+		return err
+	}
+	c.startRefreshLoop()
+	return nil
+}
+
+// startupAuth 首次申请token，StartupAuthTimeout 内遇到网络错误等临时故障时按 RetryBackoff 退避重试
+// 凭证错误等不会因重试而成功的错误立即返回
+func (c *client) startupAuth() error {
+	ctx := context.Background()
+	if c.StartupAuthTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.StartupAuthTimeout)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := c.refreshAuth(ctx, false)
+		if err == nil || c.StartupAuthTimeout <= 0 || !isTransient(err) {
+			return err
+		}
+
+		delay := c.retryDelay(attempt)
+		c.log(ctx, LogWarn, "首次鉴权失败, 稍后重试", LogField{"attempt", attempt}, LogField{"delay", delay.String()}, LogField{"err", err.Error()})
+		if sleepCtx(ctx, delay) != nil {
+			return fmt.Errorf("[startupAuth] %s 内首次鉴权未成功, 共尝试 %d 次, err: %w", c.StartupAuthTimeout, attempt, err)
+		}
+	}
+}
+
+// refreshLoop 定时刷新token
+func (c *client) refreshLoop() {
+	for {
+		d := c.nextRefreshDelay()
+		c.mu.Lock()
+		c.nextRefresh = time.Now().Add(d)
+		c.mu.Unlock()
+
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-c.resumeRefresh:
+			// 恢复自动刷新，按当前token重新计算下次刷新时间
+			timer.Stop()
+			continue
+		case <-c.stopRefresh:
+			timer.Stop()
+			return
+		}
+
+		c.credMu.Lock()
+		if c.refreshPaused {
+			c.credMu.Unlock()
+			continue
+		}
+
+		err := c.refreshAuth(context.Background(), false)
+		c.credMu.Unlock()
+		if err != nil {
+			c.log(context.Background(), LogError, "刷新token失败", LogField{"appid", c.AppID}, LogField{"err", err.Error()})
+		}
+	}
+}
+
+// PauseAutoRefresh 暂停后台定时刷新token，如由外部编排凭证轮换期间
+// 返回时进行中的后台刷新已结束；暂停期间仍可调用 RefreshAuth、UpdateCredentials
+func (c *client) PauseAutoRefresh() {
+	c.credMu.Lock()
+	defer c.credMu.Unlock()
+	c.refreshPaused = true
+}
+
+// ResumeAutoRefresh 恢复后台定时刷新token，并按当前token重新计算下次刷新时间
+func (c *client) ResumeAutoRefresh() {
+	c.credMu.Lock()
+	c.refreshPaused = false
+	c.credMu.Unlock()
+
+	select {
+	case c.resumeRefresh <- struct{}{}:
+	default:
+	}
+}
+
+// nextRefreshDelay 距下次刷新的时长
+// 以 AuthHeartbeat 为周期；个推返回了过期时间时，不晚于过期前 AuthExpireMargin；
+// 再随机提前 [0, AuthRefreshJitter)，错开各副本的刷新时间
+func (c *client) nextRefreshDelay() time.Duration {
+	d := c.AuthHeartbeat
+
+	expireTime := c.AuthTokenExpireTime()
+	if !expireTime.IsZero() {
+		untilExpire := time.Until(expireTime) - c.AuthExpireMargin
+		if untilExpire < d {
+			d = untilExpire
+		}
+	}
+
+	if c.AuthRefreshJitter > 0 {
+		d -= time.Duration(rand.Int63n(int64(c.AuthRefreshJitter)))
+	}
+
+	if d < minRefreshInterval {
+		d = minRefreshInterval
+	}
+	return d
+}
+
+// refreshAuth 刷新认证，默认20小时一次
+// force 为true时不复用共享缓存中的token
+func (c *client) refreshAuth(ctx context.Context, force bool) (err error) {
+	defer func() {
+		c.mu.Lock()
+		c.lastRefreshErr = err
+		c.mu.Unlock()
+	}()
+
+	// 共享缓存中有仍然有效的token则直接使用
+	if c.TokenCache != nil {
+		return c.refreshAuthShared(ctx, force)
+	}
+
+	// 有token则先关闭，关闭失败不影响申请新token
+	if !c.KeepTokenOnRefresh && len(c.AuthToken()) > 0 {
+		_, err := c.CloseAuth()
+		if err != nil {
+			c.log(ctx, LogWarn, "刷新前关闭旧token失败, 继续申请新token", LogField{"err", err.Error()})
+		} else {
+			c.setAuthToken("", time.Time{})
+		}
+	}
+
+	token, expireTime, err := c.requestAuth(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 将token放到实例中
+	c.setAuthToken(token, expireTime)
+
+	return nil
+}
+
+// refreshAuthShared 通过共享缓存刷新认证
+// 缓存中的token距离过期超过 AuthExpireMargin 时直接复用，否则申请新token并写回缓存
+func (c *client) refreshAuthShared(ctx context.Context, force bool) error {
+
+	if !force {
+		token, expireTime, err := c.TokenCache.Get(ctx, c.AppID)
+		if err != nil {
+			return fmt.Errorf("[refreshAuth] 读取共享token失败, err: %w", err)
+		}
+		if len(token) > 0 && (expireTime.IsZero() || time.Until(expireTime) > c.AuthExpireMargin) {
+			c.setAuthToken(token, expireTime)
+			return nil
+		}
+	}
+
+	token, expireTime, err := c.requestAuth(ctx)
+	if err != nil {
+		return err
+	}
+	c.setAuthToken(token, expireTime)
+
+	err = c.TokenCache.Set(ctx, c.AppID, token, expireTime)
+	if err != nil {
+		return fmt.Errorf("[refreshAuth] 写入共享token失败, err: %w", err)
+	}
+
+	return nil
+}
+
+// requestAuth 向个推申请token
+func (c *client) requestAuth(ctx context.Context) (token string, expireTime time.Time, err error) {
+
+	err = c.loadCredentials(ctx)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("[refreshAuth] %w", err)
+	}
+
+	skew := atomic.LoadInt64(&c.serverSkew)
+	ret, err := c.authSign(ctx)
+
+	// 本地时钟偏差导致签名失败时，按服务器时间推算的偏差重试一次
+	if err != nil && ret != nil && ret.Result == "sign_error" && atomic.LoadInt64(&c.serverSkew) != skew {
+		c.log(ctx, LogWarn, "鉴权签名失败, 按服务器时间校正时间戳后重试", LogField{"skew", time.Duration(atomic.LoadInt64(&c.serverSkew)).String()})
+		ret, err = c.authSign(ctx)
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("[refreshAuth] 发送auth请求失败, err: %w", err)
+	}
+
+	// 过期时间为毫秒时间戳
+	if len(ret.ExpireTime) > 0 {
+		expireMs, err := strconv.ParseInt(ret.ExpireTime, 10, 64)
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("[refreshAuth] 无法解析token过期时间 %s, err: %s", ret.ExpireTime, err)
+		}
+		expireTime = time.Unix(0, expireMs*int64(time.Millisecond))
+	}
+
+	return ret.AuthToken, expireTime, nil
+}
+
+// authSign 签名并请求 auth_sign，result 不为 ok 时仍返回解析结果
+func (c *client) authSign(ctx context.Context) (*authSignRsp, error) {
+	appKey, signer := c.credentials()
+	ts := authTimestamp(c.now())
+	signStr, err := signer.Sign(ctx, appKey, ts)
+	if err != nil {
+		return nil, fmt.Errorf("计算签名失败, err: %w", err)
+	}
+	body := authSignBody{AppKey: appKey, Timestamp: ts, Sign: signStr}
+
+	return doRequest[authSignRsp](ctx, c, "POST", "auth_sign", body)
+}
+
+// authSignRsp auth_sign 返回结构
+type authSignRsp struct {
+	Result     string `json:"result"`
+	AuthToken  string `json:"auth_token"`
+	ExpireTime string `json:"expire_time"`
+}
+
+func (r *authSignRsp) result() string {
+	return r.Result
+}
+
+// appKey 当前的AppKey
+func (c *client) appKey() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.AppKey
+}
+
+// credentials 当前的AppKey与签名
+func (c *client) credentials() (string, Signer) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.AppKey, c.Signer
+}
+
+// setCredentials 替换凭证
+func (c *client) setCredentials(appKey, masterSecret string, signer Signer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.AppKey = appKey
+	c.MasterSecret = masterSecret
+	c.Signer = signer
+}
+
+// UpdateCredentials 热更新 AppKey 与 MasterSecret，并立即使用新凭证重新鉴权
+// 使用自定义 Signer 时 masterSecret 传空，仅更新 AppKey；
+// 新凭证鉴权失败时恢复原凭证并重新鉴权，返回错误
+// 配置了 CredentialsProvider 时凭证以其为准，返回 ErrCredentialsProviderSet
+func (c *client) UpdateCredentials(appKey, masterSecret string) error {
+	if c.CredentialsProvider != nil {
+		return fmt.Errorf("[UpdateCredentials] %w", ErrCredentialsProviderSet)
+	}
+
+	c.credMu.Lock()
+	defer c.credMu.Unlock()
+
+	c.mu.RLock()
+	oldAppKey, oldMasterSecret, oldSigner := c.AppKey, c.MasterSecret, c.Signer
+	c.mu.RUnlock()
+
+	signer := oldSigner
+	if len(masterSecret) > 0 {
+		var err error
+		signer, err = newSigner(c.SignAlgorithm, masterSecret)
+		if err != nil {
+			return fmt.Errorf("[UpdateCredentials] %w", err)
+		}
+	}
+	c.setCredentials(appKey, masterSecret, signer)
+
+	err := c.refreshAuth(context.Background(), true)
+	if err != nil {
+		c.setCredentials(oldAppKey, oldMasterSecret, oldSigner)
+		c.refreshAuth(context.Background(), true)
+		return fmt.Errorf("[UpdateCredentials] 新凭证鉴权失败, 已恢复原凭证, err: %w", err)
+	}
+
+	return nil
+}
+
+// RefreshAuth 立即申请新token，如发现token已在外部失效时，不必等待定时刷新
+// 配置了 TokenCache 时不复用缓存中的token，新token会写回缓存
+func (c *client) RefreshAuth(ctx context.Context) error {
+	c.credMu.Lock()
+	defer c.credMu.Unlock()
+
+	err := c.refreshAuth(ctx, true)
+	if err != nil {
+		return fmt.Errorf("[RefreshAuth] 刷新token失败, err: %w", err)
+	}
+	return nil
+}
+
+// setAuthToken 更新token及其过期时间
+func (c *client) setAuthToken(token string, expireTime time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.authToken = token
+	c.authExpireTime = expireTime
+	c.authIssuedAt = time.Time{}
+	if len(token) > 0 {
+		c.authIssuedAt = time.Now()
+	}
+}
+
+// TokenInfo 当前token的状态
+type TokenInfo struct {
+	Token string
+	// IssuedAt 本实例取得token的时间，token来自 TokenCache 时为读取缓存的时间
+	IssuedAt time.Time
+	// ExpireTime token过期时间，个推未返回时为零值
+	ExpireTime time.Time
+	// LastRefreshErr 最近一次刷新的错误，刷新成功后为nil
+	LastRefreshErr error
+	// NextRefresh 后台下次定时刷新的计划时间，定时刷新未启动时为零值；暂停期间到点后不刷新
+	NextRefresh time.Time
+}
+
+// TokenInfo 当前token、取得与过期时间及最近一次刷新的错误
+func (c *client) TokenInfo() TokenInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return TokenInfo{
+		Token:          c.authToken,
+		IssuedAt:       c.authIssuedAt,
+		ExpireTime:     c.authExpireTime,
+		LastRefreshErr: c.lastRefreshErr,
+		NextRefresh:    c.nextRefresh,
+	}
+}
+
+// CloseAuth 清空Auth
+func (c *client) CloseAuth() (ret *RspBody, err error) {
+
+	ret, err = doRequest[RspBody](context.Background(), c, "POST", "auth_close", nil)
+	if err != nil {
+		return nil, fmt.Errorf("[CloseAuth] 清空auth 失败, err: %w", err)
+	}
+
+	return
+}
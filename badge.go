@@ -0,0 +1,60 @@
+package getui
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Badge 角标辅助，生成 PushInfo.Aps.AutoBadge 的取值
+//
+//	getui.Badge.Set(5)       // "5"  角标设为5
+//	getui.Badge.Increment(1) // "+1" 角标加1
+//	getui.Badge.Decrement(1) // "-1" 角标减1
+var Badge badge
+
+type badge struct{}
+
+// Set 设置角标为 n，n 小于0时按0处理
+func (badge) Set(n int) string {
+	if n < 0 {
+		n = 0
+	}
+	return strconv.Itoa(n)
+}
+
+// Increment 角标加 n，n 为负数时等同 Decrement(-n)
+func (b badge) Increment(n int) string {
+	if n < 0 {
+		return b.Decrement(-n)
+	}
+	return "+" + strconv.Itoa(n)
+}
+
+// Decrement 角标减 n，n 为负数时等同 Increment(-n)
+func (b badge) Decrement(n int) string {
+	if n < 0 {
+		return b.Increment(-n)
+	}
+	return "-" + strconv.Itoa(n)
+}
+
+// ValidateBadge 校验 AutoBadge 格式，合法的取值为 "N"、"+N"、"-N"，空表示不修改角标
+func ValidateBadge(s string) error {
+	if len(s) == 0 {
+		return nil
+	}
+
+	digits := s
+	if s[0] == '+' || s[0] == '-' {
+		digits = s[1:]
+	}
+	if len(digits) == 0 {
+		return fmt.Errorf("错误的角标 %q, 需为 N、+N 或 -N", s)
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("错误的角标 %q, 需为 N、+N 或 -N", s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrBroadcastNotConfirmed 全量推送未确认
+var ErrBroadcastNotConfirmed = errors.New("getui: 全量推送未确认")
+
+// PushToAll 向应用的全部用户推送
+// confirmAppID 需与客户端的 AppID 一致，防止误用其它环境的配置全量推送；
+// 配置了 DisableBroadcast 时总是返回 ErrBroadcastNotConfirmed。
+// body.Condition 需为空，带条件的推送请使用 PushToApp
+func (c *client) PushToAll(body AppReqBody, confirmAppID string) (*RspBody, error) {
+	return c.PushToAllContext(context.Background(), body, confirmAppID)
+}
+
+// PushToAllContext 同 PushToAll，ctx 中通过 WithApp 指定的应用同样需要确认其appid
+func (c *client) PushToAllContext(ctx context.Context, body AppReqBody, confirmAppID string) (*RspBody, error) {
+
+	c, err := c.forApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[PushToAll] %w", err)
+	}
+	if c.DisableBroadcast {
+		return nil, fmt.Errorf("[PushToAll] 已配置 DisableBroadcast, err: %w", ErrBroadcastNotConfirmed)
+	}
+	if confirmAppID != c.AppID {
+		return nil, fmt.Errorf("[PushToAll] 确认的appid %q 与客户端的appid不一致, err: %w", confirmAppID, ErrBroadcastNotConfirmed)
+	}
+	if len(body.Condition) > 0 {
+		return nil, fmt.Errorf("[PushToAll] 全量推送不能带条件, 请使用 PushToApp, err: %w", ErrInvalidTarget)
+	}
+
+	// 个推以空条件表示全量
+	body.Condition = []AppReqBodyCondition{}
+
+	ret, err := c.pushToApp(ctx, body)
+	if err != nil {
+		return nil, fmt.Errorf("[PushToAll] %w", err)
+	}
+	return ret, nil
+}
+
+// checkBroadcast 条件为空的toapp推送即全量推送，配置了 DisableBroadcast 时拒绝
+func (c *client) checkBroadcast(conditions []AppReqBodyCondition) error {
+	if len(conditions) == 0 && c.DisableBroadcast {
+		return fmt.Errorf("条件为空即全量推送, 已配置 DisableBroadcast, err: %w", ErrBroadcastNotConfirmed)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package getui
+
+import (
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrInvalidCallbackSignature 回执签名校验失败
+var ErrInvalidCallbackSignature = errors.New("getui: 回执签名校验失败")
+
+// callbackSignFields 回执中参与签名的字段
+type callbackSignFields struct {
+	CID    string `json:"cid"`
+	TaskID string `json:"taskid"`
+	MsgID  string `json:"msgid"`
+	Sign   string `json:"sign"`
+}
+
+// CallbackSign 计算回执签名 md5(appkey+cid+taskid+msgid+mastersecret) 的十六进制
+func CallbackSign(appKey, cid, taskID, msgID, masterSecret string) string {
+	sign := md5.Sum([]byte(appKey + cid + taskID + msgID + masterSecret))
+	return fmt.Sprintf("%x", sign)
+}
+
+// VerifyCallbackSignature 校验个推回执的签名
+// 签名取自body的 sign 字段，body中没有时取 header 中的 sign；
+// 用于将回执接口挂在已有路由上时自行校验，签名不一致时返回 ErrInvalidCallbackSignature
+func VerifyCallbackSignature(header http.Header, body []byte, appKey, masterSecret string) error {
+
+	var fields callbackSignFields
+	err := json.Unmarshal(body, &fields)
+	if err != nil {
+		return fmt.Errorf("[VerifyCallbackSignature] 回执的JSON无法解析, err: %w", err)
+	}
+
+	sign := fields.Sign
+	if len(sign) == 0 {
+		sign = header.Get("sign")
+	}
+	if len(sign) == 0 {
+		return fmt.Errorf("[VerifyCallbackSignature] 回执缺少签名, err: %w", ErrInvalidCallbackSignature)
+	}
+
+	want := CallbackSign(appKey, fields.CID, fields.TaskID, fields.MsgID, masterSecret)
+	if subtle.ConstantTimeCompare([]byte(want), []byte(sign)) != 1 {
+		return fmt.Errorf("[VerifyCallbackSignature] 任务 %s 的回执签名不一致, err: %w", fields.TaskID, ErrInvalidCallbackSignature)
+	}
+
+	return nil
+}
+
+// ReceiptCampaignParam 回执地址中标识活动的查询参数，回执接口据此填写 Receipt.Campaign
+const ReceiptCampaignParam = "campaign"
+
+// CampaignCallbackURL 在回执接口地址上加上活动标识，作为推送的 CallbackURL
+// 不同活动的回执可由同一个回执接口按 Receipt.Campaign 区分，也可各自使用不同的接口地址
+func CampaignCallbackURL(base, campaign string) (string, error) {
+	err := validateCallbackURL(base)
+	if err != nil {
+		return "", fmt.Errorf("[CampaignCallbackURL] %w", err)
+	}
+	u, _ := url.Parse(base)
+	q := u.Query()
+	q.Set(ReceiptCampaignParam, campaign)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// validateCallbackURL 校验回执地址，需为http(s)的绝对地址，为空时不校验
+func validateCallbackURL(raw string) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || len(u.Host) == 0 {
+		return fmt.Errorf("错误的回执地址 %q, 需为http(s)的绝对地址", raw)
+	}
+	return nil
+}
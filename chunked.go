@@ -0,0 +1,392 @@
+package getui
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// maxListBatch push_list 单次请求的cid数上限
+const maxListBatch = 1000
+
+// ChunkResult 单个分片的发送结果
+type ChunkResult struct {
+	// Index 分片序号，从0开始
+	Index int
+	// Targets 分片内的 cid 或 alias
+	Targets []string
+	Ret     *RspBody
+	Err     error
+}
+
+// ChunkedResult 分片发送结果
+// ctx 中途取消时仍返回已完成的部分，可用 Pending 取出需要续发的目标
+type ChunkedResult struct {
+	Sent         []ChunkResult
+	Failed       []ChunkResult
+	NotAttempted []ChunkResult
+	// Duplicates 配置了 DedupeCIDs 时去掉的重复cid数
+	Duplicates int
+	// Pruned 设置了 PruneInvalidCIDs 时去掉的不存在的cid，调用方可据此清理自己的数据
+	Pruned []string
+}
+
+// DedupeCIDs 去掉重复的cid，保留首次出现的顺序，返回去重后的cid与去掉的个数
+func DedupeCIDs(cids []string) ([]string, int) {
+	seen := make(map[string]struct{}, len(cids))
+	ret := make([]string, 0, len(cids))
+	for _, cid := range cids {
+		if _, ok := seen[cid]; ok {
+			continue
+		}
+		seen[cid] = struct{}{}
+		ret = append(ret, cid)
+	}
+	return ret, len(cids) - len(ret)
+}
+
+// Pending 发送失败及未发送的目标
+// 注意：因ctx取消而失败的分片，请求可能已到达个推，续发前需自行权衡重复推送
+func (r *ChunkedResult) Pending() []string {
+	var ret []string
+	for _, chunks := range [][]ChunkResult{r.Failed, r.NotAttempted} {
+		for _, c := range chunks {
+			ret = append(ret, c.Targets...)
+		}
+	}
+	return ret
+}
+
+// FailedTarget 未送达个推的一个目标
+type FailedTarget struct {
+	// Target cid 或 alias
+	Target string
+	// Chunk 所在分片的序号
+	Chunk int
+	// Status 为 "failed" 或 "not_attempted"
+	Status string
+	// Err 分片的错误，未发送时为空
+	Err error
+}
+
+// Reason 失败原因
+func (f FailedTarget) Reason() string {
+	if f.Err != nil {
+		return f.Err.Error()
+	}
+	return f.Status
+}
+
+// FailedTargets 发送失败及未发送的目标及原因，按分片序号排列，可据此只对这些目标续发
+func (r *ChunkedResult) FailedTargets() []FailedTarget {
+	var chunks []ChunkResult
+	chunks = append(chunks, r.Failed...)
+	chunks = append(chunks, r.NotAttempted...)
+	sort.SliceStable(chunks, func(i, j int) bool { return chunks[i].Index < chunks[j].Index })
+
+	var ret []FailedTarget
+	for _, c := range chunks {
+		status := "failed"
+		if c.Err == nil {
+			status = "not_attempted"
+		}
+		for _, target := range c.Targets {
+			ret = append(ret, FailedTarget{Target: target, Chunk: c.Index, Status: status, Err: c.Err})
+		}
+	}
+	return ret
+}
+
+// WriteFailedCSV 将 FailedTargets 以CSV写入w，列为 target,chunk,status,reason
+func (r *ChunkedResult) WriteFailedCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"target", "chunk", "status", "reason"})
+	if err != nil {
+		return fmt.Errorf("[WriteFailedCSV] 写入失败, err: %w", err)
+	}
+	for _, f := range r.FailedTargets() {
+		err = cw.Write([]string{f.Target, strconv.Itoa(f.Chunk), f.Status, f.Reason()})
+		if err != nil {
+			return fmt.Errorf("[WriteFailedCSV] 写入失败, err: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("[WriteFailedCSV] 写入失败, err: %w", err)
+	}
+	return nil
+}
+
+// err 汇总结果的错误，ctx 已取消时包含ctx的错误
+func (r *ChunkedResult) err(ctx context.Context) error {
+	if ctx.Err() != nil && (len(r.Failed) > 0 || len(r.NotAttempted) > 0) {
+		return fmt.Errorf("发送被中断, 已发送%d个分片, 失败%d个, 未发送%d个, err: %w", len(r.Sent), len(r.Failed), len(r.NotAttempted), ctx.Err())
+	}
+	if len(r.Failed) > 0 {
+		return fmt.Errorf("%d个分片发送失败, 第一个错误: %w", len(r.Failed), r.Failed[0].Err)
+	}
+	return nil
+}
+
+// PushToListChunked 将大量cid按 chunkSize 分片依次 push_list，所有分片共用一个消息共同体（taskid）
+// chunkSize <=0 或超过1000时按1000分片；ctx 取消后不再发送剩余分片，返回的结果中列出各分片的状态
+func (c *client) PushToListChunked(ctx context.Context, body ListReqBody, chunkSize int) (*ChunkedResult, error) {
+
+	c, err := c.forApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[PushToListChunked] %w", err)
+	}
+	lc, err := c.newListCampaign(ctx, "PushToListChunked", body, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	for !lc.done() {
+		lc.sendNext(ctx)
+	}
+
+	if err := lc.ret.err(ctx); err != nil {
+		return lc.ret, fmt.Errorf("[PushToListChunked] %w", err)
+	}
+	return lc.ret, nil
+}
+
+// listCampaign 分片list推的发送进度，分片按顺序逐个发送
+type listCampaign struct {
+	c         *client
+	body      ListReqBody
+	chunkSize int
+	// index、start 下一个分片的序号与起始位置
+	index, start int
+	// enc 各分片只有cid与taskid不同，公共部分只序列化一次
+	enc *listEncoder
+	ret *ChunkedResult
+}
+
+// newListCampaign 校验目标并按配置去重、去掉不存在的cid，op 为错误信息中的调用方
+func (c *client) newListCampaign(ctx context.Context, op string, body ListReqBody, chunkSize int) (*listCampaign, error) {
+
+	if len(body.CID) == 0 {
+		return nil, fmt.Errorf("[%s] 错误的目标, cid 不能为空, err: %w", op, ErrInvalidTarget)
+	}
+	if len(body.Alias) > 0 {
+		return nil, fmt.Errorf("[%s] 分片发送只支持 cid, err: %w", op, ErrInvalidTarget)
+	}
+	err := c.checkValid(ctx, op, c.validateCIDs(body.CID...))
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %w", op, err)
+	}
+	if chunkSize <= 0 || chunkSize > maxListBatch {
+		chunkSize = maxListBatch
+	}
+
+	lc := &listCampaign{c: c, body: body, chunkSize: chunkSize, enc: &listEncoder{}, ret: &ChunkedResult{}}
+	if c.DedupeCIDs {
+		lc.body.CID, lc.ret.Duplicates = DedupeCIDs(lc.body.CID)
+	}
+	if body.PruneInvalidCIDs {
+		lc.body.CID, lc.ret.Pruned = c.pruneCIDs(ctx, lc.body.CID)
+	}
+	return lc, nil
+}
+
+// done 所有分片都已处理
+func (lc *listCampaign) done() bool {
+	return lc.start >= len(lc.body.CID)
+}
+
+// sendNext 发送下一个分片，ctx 已结束时记为未发送
+func (lc *listCampaign) sendNext(ctx context.Context) {
+	end := lc.start + lc.chunkSize
+	if end > len(lc.body.CID) {
+		end = len(lc.body.CID)
+	}
+	chunk := ChunkResult{Index: lc.index, Targets: lc.body.CID[lc.start:end]}
+	lc.index, lc.start = lc.index+1, end
+
+	if ctx.Err() != nil {
+		lc.ret.NotAttempted = append(lc.ret.NotAttempted, chunk)
+		return
+	}
+
+	part := lc.body
+	part.CID = chunk.Targets
+	chunk.Ret, chunk.Err = lc.c.pushToList(ctx, part, lc.enc)
+	if chunk.Err != nil {
+		lc.ret.Failed = append(lc.ret.Failed, chunk)
+		return
+	}
+	lc.ret.Sent = append(lc.ret.Sent, chunk)
+	// 后续分片复用已保存的消息共同体
+	lc.body.TaskID = chunk.Ret.TaskID
+}
+
+// PushToSingleBulk 依次发送多条单推，每条为一个分片
+// ctx 取消后不再发送剩余的单推，返回的结果中列出各条的状态
+func (c *client) PushToSingleBulk(ctx context.Context, bodies []SingleReqBody) (*ChunkedResult, error) {
+
+	c, err := c.forApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[PushToSingleBulk] %w", err)
+	}
+
+	ret := &ChunkedResult{}
+	for i, body := range bodies {
+		chunk := ChunkResult{Index: i, Targets: singleTargets(body)}
+
+		if ctx.Err() != nil {
+			ret.NotAttempted = append(ret.NotAttempted, chunk)
+			continue
+		}
+
+		chunk.Ret, chunk.Err = c.pushToSingle(ctx, body)
+		if chunk.Err != nil {
+			ret.Failed = append(ret.Failed, chunk)
+			continue
+		}
+		ret.Sent = append(ret.Sent, chunk)
+	}
+
+	if err := ret.err(ctx); err != nil {
+		return ret, fmt.Errorf("[PushToSingleBulk] %w", err)
+	}
+	return ret, nil
+}
+
+// pruneCIDs 批量查询用户状态，去掉不存在的cid
+// 查询失败或 ctx 结束前未查询的cid无法确定状态，保留并照常发送
+func (c *client) pruneCIDs(ctx context.Context, cids []string) (kept, pruned []string) {
+	existed, err := c.userExistedBulk(ctx, cids, 0)
+	if err != nil {
+		c.log(ctx, LogWarn, "预检用户状态部分失败, 保留这些cid", LogField{"err", err.Error()})
+	}
+	for _, cid := range cids {
+		if ok, checked := existed[cid]; checked && !ok {
+			pruned = append(pruned, cid)
+			continue
+		}
+		kept = append(kept, cid)
+	}
+	return kept, pruned
+}
+
+// CampaignChunk 单个分片的汇总
+type CampaignChunk struct {
+	Index   int
+	TaskID  string
+	Targets int
+	// Status 为 "sent"、"failed" 或 "not_attempted"
+	Status string
+	Err    error
+}
+
+// CampaignResult 分片推送的汇总报告，列出每个分片的taskid与状态及目标总数
+type CampaignResult struct {
+	// Chunks 按分片序号排列
+	Chunks []CampaignChunk
+	// TaskIDs 去重后的taskid，按首次出现的顺序
+	TaskIDs             []string
+	TotalTargets        int
+	SentTargets         int
+	FailedTargets       int
+	NotAttemptedTargets int
+	Duplicates          int
+	Pruned              int
+}
+
+// Campaign 汇总各分片的结果
+func (r *ChunkedResult) Campaign() *CampaignResult {
+	ret := &CampaignResult{Duplicates: r.Duplicates, Pruned: len(r.Pruned)}
+
+	add := func(chunks []ChunkResult, status string, count *int) {
+		for _, c := range chunks {
+			cc := CampaignChunk{Index: c.Index, Targets: len(c.Targets), Status: status, Err: c.Err}
+			if c.Ret != nil {
+				cc.TaskID = c.Ret.TaskID
+			}
+			ret.Chunks = append(ret.Chunks, cc)
+			*count += cc.Targets
+			ret.TotalTargets += cc.Targets
+		}
+	}
+	add(r.Sent, "sent", &ret.SentTargets)
+	add(r.Failed, "failed", &ret.FailedTargets)
+	add(r.NotAttempted, "not_attempted", &ret.NotAttemptedTargets)
+	sort.Slice(ret.Chunks, func(i, j int) bool { return ret.Chunks[i].Index < ret.Chunks[j].Index })
+
+	seen := map[string]bool{}
+	for _, c := range ret.Chunks {
+		if len(c.TaskID) > 0 && !seen[c.TaskID] {
+			seen[c.TaskID] = true
+			ret.TaskIDs = append(ret.TaskIDs, c.TaskID)
+		}
+	}
+	return ret
+}
+
+// listShared push_list 请求中各分片相同的部分
+type listShared struct {
+	Message      Message      `json:"message"`
+	Notification Notification `json:"notification"`
+	Alias        string       `json:"alias,omitempty"`
+	PushInfo     PushInfo     `json:"push_info"`
+	NeedDetail   bool         `json:"need_detail"`
+}
+
+// listEncoder 分片发送时缓存 push_list 请求中已序列化的公共部分，每个分片只序列化cid与taskid
+// 同一个 listEncoder 只能用于同一个消息的各分片
+type listEncoder struct {
+	shared []byte
+}
+
+// encode 序列化 push_list 请求，首次调用时序列化并缓存公共部分
+func (e *listEncoder) encode(codec Codec, body ListReqBody) ([]byte, error) {
+	if e.shared == nil {
+		shared, err := codec.Marshal(listShared{
+			Message:      body.Message,
+			Notification: body.Notification,
+			Alias:        body.Alias,
+			PushInfo:     body.PushInfo,
+			NeedDetail:   body.NeedDetail,
+		})
+		if err != nil {
+			return nil, err
+		}
+		e.shared = bytes.TrimRight(shared, " \n")
+	}
+
+	taskID, err := codec.Marshal(body.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.Grow(len(e.shared) + len(body.CID)*35 + len(taskID) + 32)
+	buf.Write(e.shared[:len(e.shared)-1])
+	if len(body.CID) > 0 {
+		cids, err := codec.Marshal(body.CID)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(`,"cid":`)
+		buf.Write(cids)
+	}
+	buf.WriteString(`,"taskid":`)
+	buf.Write(taskID)
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// postList 发送 push_list 请求，enc 为空时整体序列化
+func (c *client) postList(ctx context.Context, body ListReqBody, enc *listEncoder) (*RspBody, error) {
+	if enc == nil {
+		return doRequest[RspBody](ctx, c, "POST", "push_list", body)
+	}
+	data, err := enc.encode(c.Codec, body)
+	if err != nil {
+		return nil, fmt.Errorf("请求body序列化失败, err: %w", err)
+	}
+	return doRequest[RspBody](ctx, c, "POST", "push_list", rawBody(data))
+}
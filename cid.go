@@ -0,0 +1,33 @@
+package getui
+
+import "fmt"
+
+// cidLength 个推cid的长度
+const cidLength = 32
+
+// ValidateCID 检查cid格式：32位十六进制
+// 只做格式检查，不代表该cid已注册，用于在请求前发现空串、截断、误填别名等错误
+func ValidateCID(cid string) error {
+	if len(cid) != cidLength {
+		return fmt.Errorf("错误的cid %q, 需为%d位十六进制, err: %w", cid, cidLength, ErrInvalidTarget)
+	}
+	for _, r := range cid {
+		if !('0' <= r && r <= '9' || 'a' <= r && r <= 'f' || 'A' <= r && r <= 'F') {
+			return fmt.Errorf("错误的cid %q, 需为%d位十六进制, err: %w", cid, cidLength, ErrInvalidTarget)
+		}
+	}
+	return nil
+}
+
+// validateCIDs 检查cid格式，配置了 SkipCIDValidation 时不检查
+func (c *client) validateCIDs(cids ...string) error {
+	if c.SkipCIDValidation {
+		return nil
+	}
+	for _, cid := range cids {
+		if err := ValidateCID(cid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,13 +1,14 @@
 package getui
 
 import (
-	"bytes"
-	"crypto/sha256"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"net"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -16,6 +17,8 @@ type Message struct {
 	AppKey    string `json:"appkey"`
 	IsOffline bool   `json:"is_offline"`
 	MsgType   string `json:"msgtype"`
+	// OfflineExpireTime 离线存储时长 单位毫秒，建议通过 body 的 SetTTL 设置
+	OfflineExpireTime int64 `json:"offline_expire_time,omitempty"`
 }
 
 // Notification 请求消息配置 Notification
@@ -25,6 +28,13 @@ type Notification struct {
 		Type  int    `json:"type"`
 		Text  string `json:"text"`
 		Title string `json:"title"`
+		// IsRing、IsVibrate 为空时使用 InitParams.Defaults，均未设置时不发送
+		IsRing    *bool `json:"is_ring,omitempty"`
+		IsVibrate *bool `json:"is_vibrate,omitempty"`
+		// Channel Android 8.0 以上的通知渠道，为空时使用 InitParams.Defaults
+		Channel      string `json:"channel,omitempty"`
+		ChannelName  string `json:"channel_name,omitempty"`
+		ChannelLevel int    `json:"channel_level,omitempty"`
 	} `json:"style"`
 	TransmissionType    bool   `json:"transmission_type"`
 	TransmissionContent string `json:"transmission_content"`
@@ -40,11 +50,18 @@ type PushInfo struct {
 			Title string `json:"title,omitempty"`
 			Body  string `json:"body,omitempty"`
 		} `json:"alert"`
+		// AutoBadge 角标，建议通过 Badge.Set/Increment/Decrement 生成
 		AutoBadge        string `json:"autoBadge,omitempty"`
 		ContentAvailable int    `json:"content-available,omitempty"`
+		// Sound iOS提示音，为空时使用 InitParams.Defaults
+		Sound string `json:"sound,omitempty"`
+		// MutableContent 为1时iOS交由 Notification Service Extension 处理，建议通过 SetRichMedia 设置
+		MutableContent int `json:"mutable-content,omitempty"`
 	} `json:"aps"`
 
 	Multimedia []PushInfoMultimedia `json:"multimedia,omitempty"`
+	// Media iOS富媒体附件，由 Notification Service Extension 下载展示
+	Media *RichMedia `json:"media,omitempty"`
 }
 
 // PushInfoMultimedia 推送消息多媒体信息
@@ -63,6 +80,10 @@ type SingleReqBody struct {
 	Alias        string       `json:"alias,omitempty"`
 	RequestID    string       `json:"requestid"`
 	PushInfo     PushInfo     `json:"push_info"`
+	// SMS 短信补量，为空时不补发
+	SMS *SMSInfo `json:"sms_message,omitempty"`
+	// CallbackURL 本次推送的回执地址，为空时使用个推后台配置的地址，可通过 CampaignCallbackURL 生成
+	CallbackURL string `json:"callback_url,omitempty"`
 }
 
 // ListReqBody 个推请求body list
@@ -76,6 +97,14 @@ type ListReqBody struct {
 	TaskID            string       `json:"taskid"`
 	NeedDetail        bool         `json:"need_detail"`
 	OfflineExpireTime int64        `json:"-"`
+	// GroupName 任务组名，保存消息共同体时发送，便于在个推后台按组统计
+	GroupName string `json:"-"`
+	// PruneInvalidCIDs 发送前批量查询用户状态，去掉不存在的cid，仅 PushToListChunked 支持
+	PruneInvalidCIDs bool `json:"-"`
+	// SMS 短信补量，随消息共同体一起保存，为空时不补发
+	SMS *SMSInfo `json:"-"`
+	// CallbackURL 回执地址，随消息共同体一起保存，为空时使用个推后台配置的地址
+	CallbackURL string `json:"-"`
 }
 
 // AppReqBody 个推请求body toapp
@@ -85,9 +114,18 @@ type AppReqBody struct {
 	Notification Notification          `json:"notification"`
 	Condition    []AppReqBodyCondition `json:"condition"`
 	RequestID    string                `json:"requestid"`
+	// PushInfo iOS推送信息，为空时不发送
+	PushInfo *PushInfo `json:"push_info,omitempty"`
+	// PushTime 定时下发时间，北京时间 yyyyMMddHHmm，建议通过 ScheduleAt 设置
+	PushTime string `json:"push_time,omitempty"`
+	// SMS 短信补量，为空时不补发
+	SMS *SMSInfo `json:"sms_message,omitempty"`
+	// CallbackURL 本次推送的回执地址，为空时使用个推后台配置的地址，可通过 CampaignCallbackURL 生成
+	CallbackURL string `json:"callback_url,omitempty"`
 }
 
 // AppReqBodyCondition toapp 过滤条件
+// Key 见 ConditionTag 等，OptType 见 OptTypeOr 等，可通过 NewCondition 创建
 // 参考资料 http://docs.getui.com/server/rest/push/#5-toapp
 type AppReqBodyCondition struct {
 	Key     string   `json:"key"`
@@ -95,11 +133,18 @@ type AppReqBodyCondition struct {
 	OptType string   `json:"opt_type"`
 }
 
+// 推送返回的 RspBody.Status，个推原文拼写为 "successed"
+const (
+	// StatusSuccessOnline 终端在线，已直接下发
+	StatusSuccessOnline = "successed_online"
+	// StatusSuccessOffline 终端离线，消息已保存，在离线有效期内上线时下发
+	StatusSuccessOffline = "successed_offline"
+	// StatusSuccessIgnore 终端长期不活跃，个推不下发，通常可视为失效的cid（见 DeadCIDCollector）
+	StatusSuccessIgnore = "successed_ignore"
+)
+
 // RspBody 个推Rsp body
-// 个推请求返回的结构
-// status : successed_offline 离线下发
-//          successed_online 在线下发
-//          successed_ignore 非活跃用户不下发
+// 个推请求返回的结构，单推的 Status 见 StatusSuccessOnline 等
 type RspBody struct {
 	Result    string `json:"result"`
 	TaskID    string `json:"taskid"`
@@ -122,11 +167,46 @@ type Client interface {
 	PushToSingle(SingleReqBody) (*RspBody, error)
 	PushToList(ListReqBody) (*RspBody, error)
 	PushToApp(AppReqBody) (*RspBody, error)
+	PushToSingleContext(ctx context.Context, body SingleReqBody) (*RspBody, error)
+	PushToListContext(ctx context.Context, body ListReqBody) (*RspBody, error)
+	PushToAppContext(ctx context.Context, body AppReqBody) (*RspBody, error)
+	PushToAll(body AppReqBody, confirmAppID string) (*RspBody, error)
+	PushToAllContext(ctx context.Context, body AppReqBody, confirmAppID string) (*RspBody, error)
+	PushToTag(tag string, notification Notification, pushInfo PushInfo) (*RspBody, error)
+	SilentPush(ctx context.Context, body SingleReqBody) (*RspBody, error)
+	PushToListChunked(ctx context.Context, body ListReqBody, chunkSize int) (*ChunkedResult, error)
+	PushToSingleBulk(ctx context.Context, bodies []SingleReqBody) (*ChunkedResult, error)
+	PushToSingleAdaptive(ctx context.Context, bodies []SingleReqBody, opts AdaptiveOptions) (*ChunkedResult, error)
+	PushPersonalized(ctx context.Context, p Personalization) (*ChunkedResult, error)
+	SplitPush(ctx context.Context, audience []string, variants []Variant, ratios []float64) (*SplitResult, error)
 	StopTask(string) (*RspBody, error)
 	UserStatus(string) (*UserStatus, error)
 	CloseAuth() (*RspBody, error)
 	UserExisted(string) (bool, error)
+	UserExistedBulk(cids []string, concurrency int) (map[string]bool, error)
+	UserExistedBulkContext(ctx context.Context, cids []string, concurrency int) (map[string]bool, error)
+	BindAlias([]AliasBinding) (*RspBody, error)
+	PushResult(taskIDs ...string) (PushResultList, error)
+	WaitForResult(ctx context.Context, taskID string, pollInterval time.Duration) (*PushResult, error)
+	QueryAppPush(date time.Time) (*AppPushReport, error)
+	QueryAppUser(date time.Time) (*AppUserReport, error)
+	ScheduledTasks(pageSize int) *Iterator[TaskInfo]
+	HistoryTasks(since, until time.Time, pageSize int) *Iterator[TaskInfo]
+	BuildRequest(body interface{}) ([]byte, error)
+	Prepare(ctx context.Context, bodies ...interface{}) ([]*PreparedPush, error)
+	SendPrepared(ctx context.Context, pushes []*PreparedPush) (*ChunkedResult, error)
+	ReplayInteractions(ctx context.Context, interactions []Interaction) ([]ReplayResult, error)
+	EncodePayload(v interface{}) (string, error)
+	UpdateCredentials(appKey, masterSecret string) error
+	RefreshAuth(ctx context.Context) error
+	WarmUp(ctx context.Context) error
+	Authenticate(ctx context.Context) error
+	PauseAutoRefresh()
+	ResumeAutoRefresh()
 	AuthToken() string
+	AuthTokenExpireTime() time.Time
+	TokenInfo() TokenInfo
+	Shutdown(context.Context) error
 }
 
 // InitParams 初始化参数
@@ -135,255 +215,427 @@ type InitParams struct {
 	AppSecret    string
 	AppKey       string
 	MasterSecret string
-	// AuthHeartbeat Auth刷新时间 单位小时 默认20小时
+	// Signer 鉴权签名，为空时使用 MasterSecret 按 SignAlgorithm 本地计算
+	Signer Signer
+	// CredentialsProvider 凭证来源，设置后每次申请token前读取 AppKey、MasterSecret，
+	// AppID 为空时在初始化时读取；凭证轮换后无需重建客户端
+	CredentialsProvider CredentialsProvider
+	// SignAlgorithm 本地计算签名的算法 默认 SignSHA256，旧版应用可使用 SignMD5
+	SignAlgorithm SignAlgorithm
+	// ClockSkew 计算签名时间戳时加到本地时间上的偏差，本地时钟慢于个推服务器时为正
+	ClockSkew time.Duration
+	// ClockSkewFromServer 由个推响应的 Date 头推算本地时钟偏差，与 ClockSkew 叠加
+	// 鉴权因时间戳返回 sign_error 且偏差有变化时，按新的偏差重新鉴权一次
+	ClockSkewFromServer bool
+	// AuthHeartbeat Auth刷新周期 默认20小时，需在1分钟到24小时（token有效期）之间
+	// 兼容旧用法：不超过24的值（如 AuthHeartbeat: 20）视为小时数
 	AuthHeartbeat time.Duration
+	// AuthExpireMargin 个推返回token过期时间时，提前多久刷新 默认10分钟
+	AuthExpireMargin time.Duration
+	// AuthRefreshJitter 刷新时间的随机提前量上限 默认5分钟，小于0时不加抖动
+	// 多副本部署时避免同时刷新触发个推鉴权频率限制
+	AuthRefreshJitter time.Duration
+	// TokenCache 多副本共享的token缓存，为空时各实例独立申请token
+	// Redis实现见 redistoken 包
+	TokenCache TokenCache
+	// HTTPClient 发送请求使用的http客户端，为空时使用 http.DefaultClient
+	// 可配合 Recorder/Replayer 录制或回放请求
+	HTTPClient *http.Client
+	// DialContext 建立连接使用的拨号函数，可用于DNS缓存、固定IP或指定出口网卡
+	// 设置后基于 http.DefaultTransport 创建http客户端，不能与 HTTPClient 同时设置
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	// APIHosts 个推接口地址，如 "https://restapi.getui.com/v1/"，按顺序优先使用 默认只使用个推主域名
+	// 可加入个推公布的备用域名或IP，某个地址连接失败时自动切换到下一个
+	APIHosts []string
+	// HostCooldown 地址连接失败后排到最后的时长，期间仍可作为最后的选择 默认30秒
+	HostCooldown time.Duration
+	// MaxResponseBytes 单个响应body的最大字节数，超过则报错 默认1MB
+	MaxResponseBytes int64
+	// MaxInflight 同时进行的请求数上限，超出的请求等待空闲或ctx结束，鉴权请求不计入 默认0不限制
+	// 用于防止大量goroutine同时推送时建立过多连接，Manager 的 AppConfig.MaxConcurrency 会覆盖该值
+	MaxInflight int
+	// PushLog 推送日志输出，每次推送写入一行JSON（见 PushLogEntry），便于采集到ELK等 默认不输出
+	PushLog io.Writer
+	// DedupeWindow 推送去重窗口，窗口期内相同目标、相同内容的推送返回 ErrDuplicatePush 默认0不去重
+	DedupeWindow time.Duration
+	// Store 推送成功后保存发送结果 默认不保存
+	Store Store
+	// MaxRetries 单个请求的最大重试次数，是否重试由 RetryPolicy 决定 默认0不重试
+	MaxRetries int
+	// RetryBackoff 首次重试前的等待时间，之后每次翻倍，最长5秒 默认200毫秒
+	RetryBackoff time.Duration
+	// RetryBudget 整个客户端在 RetryBudgetWindow 内最多重试的次数，用完后返回 ErrRetryBudgetExhausted 默认0不限制
+	RetryBudget int
+	// StartupAuthTimeout 初始化时首次鉴权遇到网络错误、限流等临时故障时，在该时长内按 RetryBackoff 退避重试（最长间隔5秒）
+	// 避免部署时的短暂网络抖动导致初始化失败；凭证错误不重试 默认0不重试
+	StartupAuthTimeout time.Duration
+	// LazyAuth 初始化时不申请token，首次请求或调用 Authenticate 时再申请 默认false
+	// 构造测试用客户端或不一定发送请求的客户端时无需访问网络
+	LazyAuth bool
+	// RetryBudgetWindow 重试预算的统计窗口 默认1分钟
+	RetryBudgetWindow time.Duration
+	// RetryPolicy 每次请求失败后判断是否重试 默认 DefaultRetryPolicy（推送只在请求未发出时重试）
+	RetryPolicy RetryPolicy
+	// SkipCIDValidation 不检查cid格式（见 ValidateCID） 默认检查
+	SkipCIDValidation bool
+	// UserAgent 追加在 User-Agent 中的应用标识，如 "order-service/1.2"，便于排查时区分流量来源
+	// 默认只发送 getui-go/<Version>
+	UserAgent string
+	// Logger 客户端日志 默认不输出
+	Logger Logger
+	// Metrics 上报各接口的请求次数，实现 HistogramMetrics 时同时上报耗时 默认不上报
+	Metrics Metrics
+	// DisableBroadcast 禁止全量推送（PushToAll 及条件为空的 PushToApp），如测试环境
+	DisableBroadcast bool
+	// TagPushMode PushToTag 的推送方式 默认 TagPushCondition
+	TagPushMode TagPushMode
+	// Defaults 推送的默认提示音、通知渠道等，推送中未设置的字段使用该值
+	Defaults PushDefaults
+	// ValidationMode cid格式、离线时长、角标、条件等校验不通过时的处理方式 默认 ValidationStrict
+	// 可在测试环境使用严格模式，生产环境使用宽松模式只记录警告
+	ValidationMode ValidationMode
+	// DedupeCIDs list推前去掉重复的cid（保留首次出现的顺序） 默认不去重
+	DedupeCIDs bool
+	// AuthHeader 携带鉴权token的请求头名，按原样发送不做规范化 默认 AuthHeaderV1
+	// 仅在个推调整请求头要求时需要设置
+	AuthHeader string
+	// KeepTokenOnRefresh 刷新token前不调用 CloseAuth 关闭旧token 默认关闭
+	// 其它副本或进程仍在使用同一个token时需要设置，旧token到期后自然失效
+	KeepTokenOnRefresh bool
+	// OnError 每个失败的请求调用一次，参数中包含接口、requestid 与个推返回的 result 等
+	// 在发送请求的goroutine中同步调用，不能阻塞
+	OnError func(ctx context.Context, info ErrorInfo)
+	// Codec 个推接口请求与响应body的JSON编解码 默认 StdCodec，不影响审计、日志等本地格式，见 Codec
+	// 如 jsoniter：实现 Codec 时直接调用 jsoniter.ConfigCompatibleWithStandardLibrary 的同名方法
+	Codec Codec
+	// PayloadSerializer EncodePayload 序列化透传内容的方式 默认 JSONPayload
+	// 二进制格式见 Base64Payload 与 payloadpb 包
+	PayloadSerializer PayloadSerializer
+	// DeadCIDs 收集单推返回 StatusSuccessIgnore 的cid 默认不收集
+	DeadCIDs *DeadCIDCollector
+	// Auditor 每次推送（成功或失败）写入一条带序号与哈希链的审计记录 默认不记录
+	Auditor *Auditor
 }
 
 type client struct {
 	InitParams
-	lastUpdateTokenTime time.Time
-	authToken           string
+
+	// mu 保护token及凭证相关字段，token由后台定时刷新，凭证可热更新
+	mu             sync.RWMutex
+	authToken      string
+	authIssuedAt   time.Time
+	authExpireTime time.Time
+	lastRefreshErr error
+	// nextRefresh 后台下次定时刷新的计划时间
+	nextRefresh time.Time
+
+	// credMu 串行化凭证更新与token刷新，refreshPaused 为true时后台不刷新token
+	credMu        sync.Mutex
+	refreshPaused bool
+	// refreshLoopOnce LazyAuth 时定时刷新在首次申请token后启动
+	refreshLoopOnce sync.Once
+	resumeRefresh   chan struct{}
+
+	// hosts 接口地址及其可用状态
+	hosts *hostPool
+
+	// serverSkew 由服务器 Date 头推算的时钟偏差，单位纳秒
+	serverSkew int64
+
+	// localSigner 签名由 MasterSecret 本地计算，凭证轮换时需重建
+	localSigner bool
+
+	// lifeMu 保护关闭状态，inflight 记录进行中的请求
+	lifeMu      sync.Mutex
+	closed      bool
+	inflight    sync.WaitGroup
+	stopRefresh chan struct{}
+
+	dedupe *dedupe
+
+	pushLogMu sync.Mutex
+
+	// retryBudget 为空时不限制重试次数
+	retryBudget *retryBudget
+
+	// limiter 为 Manager 设置的应用配额，concurrency 由 MaxInflight 或 Manager 设置，为空时不限制
+	limiter     *rateLimiter
+	concurrency chan struct{}
+
+	// apps WithApp 指定的其它应用的客户端，以AppID为key
+	appsMu     sync.Mutex
+	apps       map[string]*client
+	closedApps bool
 }
 
+// defaultMaxResponseBytes 默认响应body上限
+const defaultMaxResponseBytes = 1 << 20
+
 var single *client
 
 // Init 客户端-单例
 func Init(parms InitParams) (c Client, err error) {
 	if single == nil {
-		single = new(client)
-		single.AppID = parms.AppID
-		single.AppSecret = parms.AppSecret
-		single.AppKey = parms.AppKey
-		single.MasterSecret = parms.MasterSecret
-		single.AuthHeartbeat = parms.AuthHeartbeat
-
-		err = single.init()
+		cli, err := newClient(parms)
 		if err != nil {
-			return nil, fmt.Errorf("[GetClient] 初始化失败，err: %s", err)
+			return nil, fmt.Errorf("[GetClient] 初始化失败，err: %w", err)
 		}
-
+		single = cli
 	}
 	return single, nil
 }
 
-// AuthToken 客户端-token
-func (c *client) AuthToken() string {
-	return c.authToken
-}
-
-func (c *client) init() (err error) {
-
-	// 申请token
-	err = c.refreshAuth()
+// New 创建独立的客户端，不影响 Init 的单例
+// 多用于测试或同一进程内需要不同配置的场景
+func New(parms InitParams) (c Client, err error) {
+	cli, err := newClient(parms)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("[New] 初始化失败，err: %w", err)
 	}
-
-	// 定时刷新token
-	go func() {
-		if c.AuthHeartbeat == 0 {
-			c.AuthHeartbeat = 20
-		}
-
-		timer := time.NewTicker(c.AuthHeartbeat * time.Hour)
-		for t := range timer.C {
-			c.lastUpdateTokenTime = t
-			c.refreshAuth()
-		}
-
-		select {}
-	}()
-
-	return nil
+	return cli, nil
 }
 
-// refreshAuth 刷新认证，默认20小时一次
-func (c *client) refreshAuth() error {
-
-	// 有token则先清除掉
-	if len(c.authToken) > 0 {
-		_, err := c.CloseAuth()
+func newClient(parms InitParams) (*client, error) {
+	c := new(client)
+	c.stopRefresh = make(chan struct{})
+	c.resumeRefresh = make(chan struct{}, 1)
+	c.AppID = parms.AppID
+	c.AppSecret = parms.AppSecret
+	c.AppKey = parms.AppKey
+	c.MasterSecret = parms.MasterSecret
+	c.SignAlgorithm = parms.SignAlgorithm
+	c.CredentialsProvider = parms.CredentialsProvider
+	if c.CredentialsProvider != nil {
+		creds, err := c.CredentialsProvider.Credentials(context.Background())
 		if err != nil {
-			return fmt.Errorf("[refreshAuth] 关闭json，失败,err:%s", err)
+			return nil, fmt.Errorf("[newClient] 读取凭证失败, err: %w", err)
 		}
+		if len(c.AppID) == 0 {
+			c.AppID = creds.AppID
+		}
+		c.AppKey = creds.AppKey
+		c.MasterSecret = creds.MasterSecret
 	}
-
-	// 请求authToken
-	// 参数构造
-	ts := fmt.Sprintf("%d", int64(time.Now().UnixNano()/1000000))
-	sign := sha256.Sum256([]byte(c.AppKey + ts + c.MasterSecret))
-	signStr := fmt.Sprintf("%x", sign)
-	body := struct {
-		AppKey    string `json:"appkey"`
-		Timestamp string `json:"timestamp"`
-		Sign      string `json:"sign"`
-	}{AppKey: c.AppKey, Timestamp: ts, Sign: signStr}
-	data, _ := json.Marshal(body)
-
-	// 创建请求
-	req, err := http.NewRequest("POST", "https://restapi.getui.com/v1/"+c.AppID+"/auth_sign", ioutil.NopCloser(bytes.NewReader(data)))
+	c.ClockSkew = parms.ClockSkew
+	c.ClockSkewFromServer = parms.ClockSkewFromServer
+	signer, err := newSigner(c.SignAlgorithm, c.MasterSecret)
 	if err != nil {
-		return fmt.Errorf("[refreshAuth] 创建auth请求失败, err: %s", err)
+		return nil, fmt.Errorf("[newClient] %w", err)
 	}
-	req.Header.Add("Content-Type", "application/json")
-
-	// 发送请求
-	rsp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("[refreshAuth] 发送auth请求失败, err: %s", err)
+	c.Signer = parms.Signer
+	c.localSigner = c.Signer == nil
+	if c.localSigner {
+		c.Signer = signer
 	}
-	defer rsp.Body.Close()
-
-	// 解析-body
-	rspBody, err := ioutil.ReadAll(rsp.Body)
+	heartbeat, err := authHeartbeat(parms.AuthHeartbeat)
 	if err != nil {
-		return fmt.Errorf("[refreshAuth] 发送auth请求返回的body无法解析, err: %s", err)
+		return nil, fmt.Errorf("[newClient] %w", err)
 	}
-
-	// 解析-JSON
-	ret := &struct {
-		Result    string `json:"result"`
-		AuthToken string `json:"auth_token"`
-	}{}
-	err = json.Unmarshal(rspBody, ret)
-	if err != nil {
-		return fmt.Errorf("[refreshAuth] 发送auth请求返回的JSON无法解析, err: %s", err)
+	c.AuthHeartbeat = heartbeat
+	c.AuthExpireMargin = parms.AuthExpireMargin
+	if c.AuthExpireMargin <= 0 {
+		c.AuthExpireMargin = defaultAuthExpireMargin
 	}
-
-	// 将token放到实例中
-	c.authToken = ret.AuthToken
-
-	return nil
-}
-
-// CloseAuth 清空Auth
-func (c *client) CloseAuth() (ret *RspBody, err error) {
-	req, err := http.NewRequest("POST", "https://restapi.getui.com/v1/"+c.AppID+"/auth_close", nil)
+	c.AuthRefreshJitter = parms.AuthRefreshJitter
+	if c.AuthRefreshJitter == 0 {
+		c.AuthRefreshJitter = defaultAuthRefreshJitter
+	}
+	c.TokenCache = parms.TokenCache
+	c.HTTPClient = parms.HTTPClient
+	c.DialContext = parms.DialContext
+	c.APIHosts = parms.APIHosts
+	c.HostCooldown = parms.HostCooldown
+	c.hosts, err = newHostPool(c.APIHosts, c.HostCooldown)
 	if err != nil {
-		return nil, fmt.Errorf("[CloseAuth] 创建 清空auth 请求失败, err: %s", err)
+		return nil, fmt.Errorf("[newClient] %w", err)
+	}
+	if c.DialContext != nil {
+		if c.HTTPClient != nil {
+			return nil, fmt.Errorf("[newClient] HTTPClient 与 DialContext 不能同时设置")
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.DialContext = c.DialContext
+		c.HTTPClient = &http.Client{Transport: transport}
+	}
+	c.MaxResponseBytes = parms.MaxResponseBytes
+	if c.MaxResponseBytes <= 0 {
+		c.MaxResponseBytes = defaultMaxResponseBytes
+	}
+	c.MaxInflight = parms.MaxInflight
+	if c.MaxInflight > 0 {
+		c.concurrency = make(chan struct{}, c.MaxInflight)
+	}
+	c.PushLog = parms.PushLog
+	c.DedupeWindow = parms.DedupeWindow
+	if c.DedupeWindow > 0 {
+		c.dedupe = newDedupe(c.DedupeWindow)
+	}
+	c.Store = parms.Store
+	c.DeadCIDs = parms.DeadCIDs
+	c.Auditor = parms.Auditor
+	c.MaxRetries = parms.MaxRetries
+	c.StartupAuthTimeout = parms.StartupAuthTimeout
+	c.LazyAuth = parms.LazyAuth
+	c.RetryBackoff = parms.RetryBackoff
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = defaultRetryBackoff
+	}
+	c.RetryBudget = parms.RetryBudget
+	c.RetryBudgetWindow = parms.RetryBudgetWindow
+	c.RetryPolicy = parms.RetryPolicy
+	if c.RetryBudgetWindow <= 0 {
+		c.RetryBudgetWindow = defaultRetryBudgetWindow
+	}
+	c.SkipCIDValidation = parms.SkipCIDValidation
+	c.UserAgent = parms.UserAgent
+	c.Logger = parms.Logger
+	c.Metrics = parms.Metrics
+	c.DisableBroadcast = parms.DisableBroadcast
+	c.TagPushMode = parms.TagPushMode
+	c.Defaults = parms.Defaults
+	c.ValidationMode = parms.ValidationMode
+	c.DedupeCIDs = parms.DedupeCIDs
+	c.AuthHeader = parms.AuthHeader
+	c.KeepTokenOnRefresh = parms.KeepTokenOnRefresh
+	c.OnError = parms.OnError
+	c.Codec = parms.Codec
+	if c.Codec == nil {
+		c.Codec = StdCodec{}
+	}
+	c.PayloadSerializer = parms.PayloadSerializer
+	if c.PayloadSerializer == nil {
+		c.PayloadSerializer = JSONPayload
+	}
+	if len(c.AuthHeader) == 0 {
+		c.AuthHeader = AuthHeaderV1
+	}
+	if c.Defaults.OfflineExpire != 0 {
+		if _, err := offlineExpireMs(c.Defaults.OfflineExpire); err != nil {
+			return nil, fmt.Errorf("[newClient] Defaults.OfflineExpire 错误, err: %w", err)
+		}
+	}
+	if c.RetryBudget > 0 {
+		c.retryBudget = newRetryBudget(c.RetryBudget, c.RetryBudgetWindow)
 	}
 
-	req.Header["authtoken"] = []string{c.authToken}
-	rsp, err := http.DefaultClient.Do(req)
+	err = c.init()
 	if err != nil {
-		return nil, fmt.Errorf("[CloseAuth] 发送 清空auth 请求失败, err: %s", err)
+		return nil, err
 	}
-	defer rsp.Body.Close()
+	return c, nil
+}
 
-	rspBody, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("[CloseAuth] 清空auth 请求返回的body无法解析, err: %s", err)
+// httpClient 实际发送请求的http客户端
+func (c *client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
 	}
+	return http.DefaultClient
+}
 
-	ret = &RspBody{}
-	err = json.Unmarshal(rspBody, ret)
+// readBody 读取响应body，超过 MaxResponseBytes 时报错
+// 不再流式解析：需先判断是否为JSON，非JSON的body原样放入 TransportError；内存占用受 MaxResponseBytes 限制
+func (c *client) readBody(body io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(body, c.MaxResponseBytes+1))
 	if err != nil {
-		return nil, fmt.Errorf("[CloseAuth] 清空auth 请求返回的JSON无法解析, err: %s", err)
+		return nil, err
 	}
-
-	if ret.Result != "ok" {
-		return nil, fmt.Errorf("[CloseAuth] 清空auth 失败, desc: %s", ret.Desc)
+	if int64(len(data)) > c.MaxResponseBytes {
+		return nil, fmt.Errorf("响应body超过%d字节上限", c.MaxResponseBytes)
 	}
-
-	return
+	return data, nil
 }
 
 // PushToSingle 发送单客户端信息
 // 参考资料 http://docs.getui.com/server/rest/push/#3
 func (c *client) PushToSingle(body SingleReqBody) (ret *RspBody, err error) {
+	return c.pushToSingle(context.Background(), body)
+}
 
-	if len(body.CID) == 0 && len(body.Alias) == 0 {
-		return nil, fmt.Errorf("[PushToSingle] 错误的目标设备, cid 与 alias 任选且必选一个")
-	}
-
-	body.Message.AppKey = c.AppKey
-	if len(body.RequestID) == 0 {
-		body.RequestID = strconv.FormatInt(time.Now().UnixNano(), 12)
-	}
-
-	// 构造请求
-	data, _ := json.Marshal(body)
-	req, err := http.NewRequest("POST", "https://restapi.getui.com/v1/"+c.AppID+"/push_single", ioutil.NopCloser(bytes.NewReader(data)))
+// PushToSingleContext 同 PushToSingle，body 未指定 RequestID 时使用ctx中的requestid（见 WithRequestID）
+func (c *client) PushToSingleContext(ctx context.Context, body SingleReqBody) (*RspBody, error) {
+	c, err := c.forApp(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("[PushToSingle] 创建 发送单客户端信息 请求失败, err: %s", err)
+		return nil, fmt.Errorf("[PushToSingle] %w", err)
 	}
+	return c.pushToSingle(ctx, body)
+}
 
-	req.Header["Content-Type"] = []string{"application/json"}
-	req.Header["authtoken"] = []string{c.authToken}
+func (c *client) pushToSingle(ctx context.Context, body SingleReqBody) (ret *RspBody, err error) {
 
-	// 发送请求
-	rsp, err := http.DefaultClient.Do(req)
+	body, err = c.prepareSingle(ctx, body)
 	if err != nil {
-		return nil, fmt.Errorf("[PushToSingle] 发送 单客户端信息 请求失败, err: %s", err)
+		return nil, err
 	}
-	defer rsp.Body.Close()
+	return c.sendSingle(ctx, body, body)
+}
 
-	// 解析-body
-	rspBody, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("[PushToSingle] 发送 单客户端信息请求 返回的body无法解析, err: %s", err)
-	}
+// sendSingle 发送已 prepare 的单推，payload 为请求body，预构建的推送为构建时的JSON
+func (c *client) sendSingle(ctx context.Context, body SingleReqBody, payload interface{}) (ret *RspBody, err error) {
 
-	// 解析-json
-	ret = &RspBody{
-		RequestID: body.RequestID,
-	}
-	err = json.Unmarshal(rspBody, ret)
+	key, err := c.checkDuplicate([]string{body.CID, body.Alias}, body.Message, body.Notification, body.PushInfo)
 	if err != nil {
-		return nil, fmt.Errorf("[PushToSingle] 发送 单客户端信息 请求返回的JSON无法解析, err: %s", err)
+		return nil, fmt.Errorf("[PushToSingle] 推送去重, err: %w", err)
 	}
+	defer func() { c.releaseDuplicate(key, err) }()
 
-	if ret.Result != "ok" {
-		return nil, fmt.Errorf("[PushToSingle] 发送 单客户端信息 请求不成功, ret: %v", ret)
+	start := time.Now()
+	ret, err = doRequest[RspBody](ctx, c, "POST", "push_single", payload)
+	c.logPush("push_single", start, body.RequestID, 1, ret, err)
+	c.savePush("push_single", start, body.RequestID, "", singleTargets(body), 1, ret, err)
+	c.auditPush(ctx, "push_single", start, body.RequestID, singleTargets(body), 1, ret, err)
+	c.observeDeadCID(ctx, body.CID, ret)
+	if err != nil {
+		return nil, fmt.Errorf("[PushToSingle] 发送 单客户端信息 失败, requestid: %s, err: %w", body.RequestID, err)
 	}
+	ret.RequestID = body.RequestID
 
 	return
 }
 
 // Push 向app推送
+// body.Condition 为空即全量推送，配置了 DisableBroadcast 时返回 ErrBroadcastNotConfirmed；需要确认appid时请使用 PushToAll
 // 参考资料 http://docs.getui.com/server/rest/push/#5-toapp
 func (c *client) PushToApp(body AppReqBody) (ret *RspBody, err error) {
+	return c.pushToApp(context.Background(), body)
+}
 
-	body.Message.AppKey = c.AppKey
-	if len(body.RequestID) == 0 {
-		body.RequestID = strconv.FormatInt(time.Now().UnixNano(), 12)
-	}
-
-	// 构造请求
-	data, _ := json.Marshal(body)
-	req, err := http.NewRequest("POST", "https://restapi.getui.com/v1/"+c.AppID+"/push_app", ioutil.NopCloser(bytes.NewReader(data)))
+// PushToAppContext 同 PushToApp，body 未指定 RequestID 时使用ctx中的requestid（见 WithRequestID）
+func (c *client) PushToAppContext(ctx context.Context, body AppReqBody) (*RspBody, error) {
+	c, err := c.forApp(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("[PushToSingle] 创建 向app推送信息 请求失败, err: %s", err)
+		return nil, fmt.Errorf("[PushToApp] %w", err)
 	}
+	return c.pushToApp(ctx, body)
+}
 
-	req.Header["Content-Type"] = []string{"application/json"}
-	req.Header["authtoken"] = []string{c.authToken}
+func (c *client) pushToApp(ctx context.Context, body AppReqBody) (ret *RspBody, err error) {
 
-	// 发送请求
-	rsp, err := http.DefaultClient.Do(req)
+	body, err = c.prepareApp(ctx, body)
 	if err != nil {
-		return nil, fmt.Errorf("[PushToSingle] 发送 向app推送信 息请求失败, err: %s", err)
+		return nil, err
 	}
-	defer rsp.Body.Close()
+	return c.sendApp(ctx, body, body)
+}
 
-	// 解析-body
-	rspBody, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("[PushToSingle] 发送 向app推送信息 请求返回的body无法解析, err: %s", err)
-	}
+// sendApp 发送已 prepare 的toapp推送，payload 为请求body，预构建的推送为构建时的JSON
+func (c *client) sendApp(ctx context.Context, body AppReqBody, payload interface{}) (ret *RspBody, err error) {
 
-	// 解析-json
-	ret = &RspBody{
-		RequestID: body.RequestID,
-	}
-	err = json.Unmarshal(rspBody, ret)
+	key, err := c.checkDuplicate(body.Condition, body.Message, body.Notification, body.PushInfo)
 	if err != nil {
-		return nil, fmt.Errorf("[PushToSingle] 发送 向app推送信息 请求返回的JSON无法解析, err: %s", err)
+		return nil, fmt.Errorf("[PushToApp] 推送去重, err: %w", err)
 	}
+	defer func() { c.releaseDuplicate(key, err) }()
 
-	if ret.Result != "ok" {
-		return nil, fmt.Errorf("[PushToSingle] 发送 向app推送信息 请求不成功, ret: %v ", ret)
+	start := time.Now()
+	ret, err = doRequest[RspBody](ctx, c, "POST", "push_app", payload)
+	c.logPush("push_app", start, body.RequestID, 0, ret, err)
+	c.savePush("push_app", start, body.RequestID, "", nil, 0, ret, err)
+	c.auditPush(ctx, "push_app", start, body.RequestID, nil, 0, ret, err)
+	if err != nil {
+		return nil, fmt.Errorf("[PushToApp] 发送 向app推送信息 失败, requestid: %s, err: %w", body.RequestID, err)
 	}
+	ret.RequestID = body.RequestID
 
 	return
 }
@@ -392,36 +644,9 @@ func (c *client) PushToApp(body AppReqBody) (ret *RspBody, err error) {
 // 参考资料 http://docs.getui.com/server/rest/push/#6-stop
 func (c *client) StopTask(taskID string) (ret *RspBody, err error) {
 
-	req, err := http.NewRequest("DELETE", "https://restapi.getui.com/v1/"+c.AppID+"/stop_task/"+taskID, nil)
-	if err != nil {
-		return nil, fmt.Errorf("[StopTask] 创建 终止群推任务 信息请求失败, err: %s", err)
-	}
-
-	req.Header["Content-Type"] = []string{"application/json"}
-	req.Header["authtoken"] = []string{c.authToken}
-
-	// 发送请求
-	rsp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("[StopTask] 发送 终止群推任务 信息请求失败, err: %s", err)
-	}
-	defer rsp.Body.Close()
-
-	// 解析-body
-	rspBody, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("[StopTask] 发送 终止群推任务 信息请求返回的body无法解析, err: %s", err)
-	}
-
-	// 解析-json
-	ret = &RspBody{}
-	err = json.Unmarshal(rspBody, ret)
+	ret, err = doRequest[RspBody](context.Background(), c, "DELETE", "stop_task/"+taskID, nil)
 	if err != nil {
-		return nil, fmt.Errorf("[StopTask] 发送 终止群推任务 信息请求返回的JSON无法解析, err: %s", err)
-	}
-
-	if ret.Result != "ok" {
-		return nil, fmt.Errorf("[StopTask] 发送 终止群推任务 信息请求不成功, ret: %v", ret)
+		return nil, fmt.Errorf("[StopTask] 发送 终止群推任务 失败, err: %w", err)
 	}
 
 	return
@@ -430,33 +655,19 @@ func (c *client) StopTask(taskID string) (ret *RspBody, err error) {
 // UserStatus 查看用户状态
 // 参考资料 http://docs.getui.com/server/rest/push/#11_1
 func (c *client) UserStatus(cid string) (ret *UserStatus, err error) {
+	return c.userStatus(context.Background(), cid)
+}
 
-	req, err := http.NewRequest("GET", "https://restapi.getui.com/v1/"+c.AppID+"/user_status/"+cid, nil)
-	if err != nil {
-		return nil, fmt.Errorf("[UserStatus] 创建 查看用户状态 请求失败, err: %s", err)
-	}
-
-	req.Header["Content-Type"] = []string{"application/json"}
-	req.Header["authtoken"] = []string{c.authToken}
+func (c *client) userStatus(ctx context.Context, cid string) (ret *UserStatus, err error) {
 
-	// 发送请求
-	rsp, err := http.DefaultClient.Do(req)
+	err = c.checkValid(ctx, "UserStatus", c.validateCIDs(cid))
 	if err != nil {
-		return nil, fmt.Errorf("[UserStatus] 发送 查看用户状态 请求失败, err: %s", err)
+		return nil, fmt.Errorf("[UserStatus] %w", err)
 	}
-	defer rsp.Body.Close()
 
-	// 解析-body
-	rspBody, err := ioutil.ReadAll(rsp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("[UserStatus] 发送 查看用户状态 请求返回的body无法解析, err: %s", err)
-	}
-
-	// 解析-json
-	ret = &UserStatus{}
-	err = json.Unmarshal(rspBody, ret)
-	if err != nil {
-		return nil, fmt.Errorf("[UserStatus] 发送 查看用户状态 返回的JSON无法解析,ret:%v, err: %s", ret, err)
+	ret, err = doRequest[UserStatus](ctx, c, "GET", "user_status/"+cid, nil)
+	if ret == nil {
+		return nil, fmt.Errorf("[UserStatus] 发送 查看用户状态 失败, err: %w", err)
 	}
 
 	// 当status 为offline时，才有该字段
@@ -468,8 +679,8 @@ func (c *client) UserStatus(cid string) (ret *UserStatus, err error) {
 		ret.LastLogin = time.Unix(int64(lastLoginUnix)/1000, 0)
 	}
 
-	if ret.Result != "ok" {
-		return ret, fmt.Errorf("[UserStatus] 发送 查看用户状态 请求不成功, ret: %v", ret)
+	if err != nil {
+		return ret, fmt.Errorf("[UserStatus] 发送 查看用户状态 失败, err: %w", err)
 	}
 
 	return
@@ -477,15 +688,18 @@ func (c *client) UserStatus(cid string) (ret *UserStatus, err error) {
 
 // UserExisted 用户是否存在
 func (c *client) UserExisted(cid string) (existed bool, err error) {
+	return c.userExisted(context.Background(), cid)
+}
 
-	ret, err := c.UserStatus(cid)
-	if err != nil {
-		return false, fmt.Errorf("[UserExisted] 查看用户是否存在 失败, err: %s", err)
-	}
+func (c *client) userExisted(ctx context.Context, cid string) (existed bool, err error) {
 
-	if ret.Result == "no_user" {
+	_, err = c.userStatus(ctx, cid)
+	if errors.Is(err, ErrNoUser) {
 		return false, nil
 	}
+	if err != nil {
+		return false, fmt.Errorf("[UserExisted] 查看用户是否存在 失败, err: %w", err)
+	}
 
 	return true, nil
 }
@@ -493,104 +707,216 @@ func (c *client) UserExisted(cid string) (existed bool, err error) {
 // PushToList 发送单条信息
 // 参考资料 http://docs.getui.com/server/rest/push/#4-tolist
 func (c *client) PushToList(body ListReqBody) (ret *RspBody, err error) {
+	return c.pushToList(context.Background(), body, nil)
+}
 
-	if len(body.CID) == 0 && len(body.Alias) == 0 {
-		return nil, fmt.Errorf("[PushToList] 错误的目标, cid 与 alias 任选且必选一个")
+// PushToListContext 同 PushToList，日志与错误中带上ctx中的requestid（见 WithRequestID）
+func (c *client) PushToListContext(ctx context.Context, body ListReqBody) (*RspBody, error) {
+	c, err := c.forApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[PushToList] %w", err)
 	}
+	return c.pushToList(ctx, body, nil)
+}
 
-	ret, err = c.saveListBody(body)
+// pushToList body.TaskID 不为空时复用已保存的消息共同体，不再调用 save_list_body
+// push_list 返回taskid失效时重新保存消息共同体并重试一次，返回的 TaskID 为新的taskid
+// enc 不为空时复用其中已序列化的公共部分，见 listEncoder
+func (c *client) pushToList(ctx context.Context, body ListReqBody, enc *listEncoder) (ret *RspBody, err error) {
+
+	body, err = c.prepareList(ctx, body)
 	if err != nil {
-		return nil, fmt.Errorf("[PushToList] 保存消息共同体, 失败，err:%s", err)
+		return nil, err
 	}
 
-	body.Message.AppKey = c.AppKey
-	body.TaskID = ret.TaskID
+	key, err := c.checkDuplicate([]interface{}{body.CID, body.Alias}, body.Message, body.Notification, body.PushInfo, body.OfflineExpireTime)
+	if err != nil {
+		return nil, fmt.Errorf("[PushToList] 推送去重, err: %w", err)
+	}
+	defer func() { c.releaseDuplicate(key, err) }()
 
-	body.NeedDetail = true
+	if len(body.TaskID) == 0 {
+		ret, err = c.saveListBody(ctx, body)
+		if err != nil {
+			return nil, fmt.Errorf("[PushToList] 保存消息共同体, 失败，err:%w", err)
+		}
+		body.TaskID = ret.TaskID
+	}
 
-	// 构造请求
-	data, _ := json.Marshal(body)
-	req, err := http.NewRequest("POST", "https://restapi.getui.com/v1/"+c.AppID+"/push_list", ioutil.NopCloser(bytes.NewReader(data)))
-	if err != nil {
-		return nil, fmt.Errorf("[PushToList] 创建 发送tolist信息 请求失败, err: %s", err)
+	targetCount := len(body.CID)
+	if len(body.Alias) > 0 {
+		targetCount++
 	}
 
-	req.Header["Content-Type"] = []string{"application/json"}
-	req.Header["authtoken"] = []string{c.authToken}
+	start := time.Now()
+	ret, err = c.postList(ctx, body, enc)
 
-	// 发送请求
-	rsp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("[PushToList] 发送 tolist信息 请求失败, err: %s", err)
+	// 消息共同体有有效期，taskid过期时重新保存并重试一次
+	if errors.Is(err, ErrTaskNotFound) {
+		c.log(ctx, LogWarn, "taskid已失效, 重新保存消息共同体", LogField{"taskid", body.TaskID})
+		var saved *RspBody
+		saved, err = c.saveListBody(ctx, body)
+		if err != nil {
+			return nil, fmt.Errorf("[PushToList] taskid %s 已失效, 重新保存消息共同体失败, err: %w", body.TaskID, err)
+		}
+		body.TaskID = saved.TaskID
+		start = time.Now()
+		ret, err = c.postList(ctx, body, enc)
 	}
-	defer rsp.Body.Close()
 
-	// 解析-body
-	rspBody, err := ioutil.ReadAll(rsp.Body)
+	if ret != nil && len(ret.TaskID) == 0 {
+		ret.TaskID = body.TaskID
+	}
+	reqID := RequestIDFromContext(ctx)
+	c.logPush("push_list", start, reqID, targetCount, ret, err)
+	c.savePush("push_list", start, reqID, body.GroupName, listTargets(body), targetCount, ret, err)
+	c.auditPush(ctx, "push_list", start, reqID, listTargets(body), targetCount, ret, err)
 	if err != nil {
-		return nil, fmt.Errorf("[PushToList] 发送 tolist信息请求 返回的body无法解析, err: %s", err)
+		return nil, fmt.Errorf("[PushToList] 发送 tolist信息 失败, taskid: %s, err: %w", body.TaskID, err)
 	}
 
-	// 解析-json
-	ret = &RspBody{
-		TaskID: body.TaskID,
+	return
+}
+
+// prepareSingle 校验单推body，填充默认值、appkey与requestid
+func (c *client) prepareSingle(ctx context.Context, body SingleReqBody) (SingleReqBody, error) {
+
+	if len(body.CID) == 0 && len(body.Alias) == 0 {
+		return body, fmt.Errorf("[PushToSingle] 错误的目标设备, cid 与 alias 任选且必选一个, err: %w", ErrInvalidTarget)
 	}
-	err = json.Unmarshal(rspBody, ret)
-	if err != nil {
-		return nil, fmt.Errorf("[PushToList] 发送 tolist信息 请求返回的JSON无法解析, err: %s", err)
+	if len(body.CID) > 0 {
+		err := c.checkValid(ctx, "PushToSingle", c.validateCIDs(body.CID))
+		if err != nil {
+			return body, fmt.Errorf("[PushToSingle] %w", err)
+		}
 	}
 
-	if ret.Result != "ok" {
-		return nil, fmt.Errorf("[PushToList] 发送 tolist信息 请求不成功, ret: %v", ret)
+	c.applyDefaults(&body.Message, &body.Notification, &body.PushInfo)
+	err := c.checkValid(ctx, "PushToSingle", validateOfflineExpire(body.Message.OfflineExpireTime))
+	if err != nil {
+		return body, fmt.Errorf("[PushToSingle] %w", err)
+	}
+	err = c.checkValid(ctx, "PushToSingle", ValidateBadge(body.PushInfo.Aps.AutoBadge))
+	if err != nil {
+		return body, fmt.Errorf("[PushToSingle] %w", err)
+	}
+	err = c.checkValid(ctx, "PushToSingle", validateSMS(body.SMS, body.Message.IsOffline, body.Message.OfflineExpireTime))
+	if err != nil {
+		return body, fmt.Errorf("[PushToSingle] %w", err)
+	}
+	err = c.checkValid(ctx, "PushToSingle", validateCallbackURL(body.CallbackURL))
+	if err != nil {
+		return body, fmt.Errorf("[PushToSingle] %w", err)
 	}
 
-	return
+	body.Message.AppKey = c.appKey()
+	body.RequestID = requestID(ctx, body.RequestID)
+
+	return body, nil
 }
 
-// PushToList前需要执行该步
-// 参考资料 http://docs.getui.com/server/rest/push/#4-tolist 的save_list_body
-func (c *client) saveListBody(listBody ListReqBody) (ret *RspBody, err error) {
+// prepareApp 校验toapp body，填充默认值、appkey与requestid
+func (c *client) prepareApp(ctx context.Context, body AppReqBody) (AppReqBody, error) {
 
-	body := SaveListBody{}
-	body.Message.AppKey = c.AppKey
-	body.Message.IsOffLine = listBody.Message.IsOffline
-	body.Message.OfflineExpireTime = listBody.OfflineExpireTime
-	body.Message.MsgType = listBody.Message.MsgType
-
-	body.Notification = listBody.Notification
+	// 全量推送不受 ValidationMode 影响
+	err := c.checkBroadcast(body.Condition)
+	if err != nil {
+		return body, fmt.Errorf("[PushToApp] %w", err)
+	}
+	err = c.checkValid(ctx, "PushToApp", validateConditions(body.Condition))
+	if err != nil {
+		return body, fmt.Errorf("[PushToApp] %w", err)
+	}
 
-	// 构造请求
-	data, _ := json.Marshal(body)
-	req, err := http.NewRequest("POST", "https://restapi.getui.com/v1/"+c.AppID+"/save_list_body", ioutil.NopCloser(bytes.NewReader(data)))
+	c.applyDefaults(&body.Message, &body.Notification, body.PushInfo)
+	err = c.checkValid(ctx, "PushToApp", validateOfflineExpire(body.Message.OfflineExpireTime))
+	if err != nil {
+		return body, fmt.Errorf("[PushToApp] %w", err)
+	}
+	if body.PushInfo != nil {
+		err = c.checkValid(ctx, "PushToApp", ValidateBadge(body.PushInfo.Aps.AutoBadge))
+		if err != nil {
+			return body, fmt.Errorf("[PushToApp] %w", err)
+		}
+	}
+	err = c.checkValid(ctx, "PushToApp", validateSMS(body.SMS, body.Message.IsOffline, body.Message.OfflineExpireTime))
+	if err != nil {
+		return body, fmt.Errorf("[PushToApp] %w", err)
+	}
+	err = c.checkValid(ctx, "PushToApp", validateCallbackURL(body.CallbackURL))
 	if err != nil {
-		return nil, fmt.Errorf("[saveListBody] 创建 保存消息共同体 信息 请求失败, err: %s", err)
+		return body, fmt.Errorf("[PushToApp] %w", err)
 	}
 
-	req.Header["Content-Type"] = []string{"application/json"}
-	req.Header["authtoken"] = []string{c.authToken}
+	body.Message.AppKey = c.appKey()
+	body.RequestID = requestID(ctx, body.RequestID)
+
+	return body, nil
+}
 
-	// 发送请求
-	rsp, err := http.DefaultClient.Do(req)
+// prepareList 校验list推body，填充默认值与appkey
+func (c *client) prepareList(ctx context.Context, body ListReqBody) (ListReqBody, error) {
+
+	if len(body.CID) == 0 && len(body.Alias) == 0 {
+		return body, fmt.Errorf("[PushToList] 错误的目标, cid 与 alias 任选且必选一个, err: %w", ErrInvalidTarget)
+	}
+	if c.DedupeCIDs {
+		var removed int
+		body.CID, removed = DedupeCIDs(body.CID)
+		if removed > 0 {
+			c.log(ctx, LogInfo, "去掉重复的cid", LogField{"removed", removed})
+		}
+	}
+	err := c.checkValid(ctx, "PushToList", c.validateCIDs(body.CID...))
 	if err != nil {
-		return nil, fmt.Errorf("[saveListBody] 发送 保存消息共同体 请求失败, err: %s", err)
+		return body, fmt.Errorf("[PushToList] %w", err)
 	}
-	defer rsp.Body.Close()
 
-	// 解析-body
-	rspBody, err := ioutil.ReadAll(rsp.Body)
+	c.applyDefaults(&body.Message, &body.Notification, &body.PushInfo)
+	if body.OfflineExpireTime == 0 {
+		body.OfflineExpireTime = body.Message.OfflineExpireTime
+	}
+	err = c.checkValid(ctx, "PushToList", validateOfflineExpire(body.OfflineExpireTime))
 	if err != nil {
-		return nil, fmt.Errorf("[saveListBody] 发送 保存消息共同体 返回的body无法解析, err: %s", err)
+		return body, fmt.Errorf("[PushToList] %w", err)
 	}
-
-	// 解析-json
-	ret = &RspBody{}
-	err = json.Unmarshal(rspBody, ret)
+	err = c.checkValid(ctx, "PushToList", ValidateBadge(body.PushInfo.Aps.AutoBadge))
+	if err != nil {
+		return body, fmt.Errorf("[PushToList] %w", err)
+	}
+	err = c.checkValid(ctx, "PushToList", validateSMS(body.SMS, body.Message.IsOffline, body.OfflineExpireTime))
+	if err != nil {
+		return body, fmt.Errorf("[PushToList] %w", err)
+	}
+	err = c.checkValid(ctx, "PushToList", validateCallbackURL(body.CallbackURL))
 	if err != nil {
-		return nil, fmt.Errorf("[saveListBody] 发送 保存消息共同体 请求返回的JSON无法解析, err: %s", err)
+		return body, fmt.Errorf("[PushToList] %w", err)
 	}
 
-	if ret.Result != "ok" {
-		return nil, fmt.Errorf("[saveListBody] 发送 保存消息共同体 请求不成功, ret: %v", ret)
+	body.Message.AppKey = c.appKey()
+	body.NeedDetail = true
+
+	return body, nil
+}
+
+// PushToList前需要执行该步
+// 参考资料 http://docs.getui.com/server/rest/push/#4-tolist 的save_list_body
+func (c *client) saveListBody(ctx context.Context, listBody ListReqBody) (ret *RspBody, err error) {
+
+	body := SaveListBody{}
+	body.Message.AppKey = c.appKey()
+	body.Message.IsOffLine = listBody.Message.IsOffline
+	body.Message.OfflineExpireTime = listBody.OfflineExpireTime
+	body.Message.MsgType = listBody.Message.MsgType
+
+	body.Notification = listBody.Notification
+	body.GroupName = listBody.GroupName
+	body.SMS = listBody.SMS
+	body.CallbackURL = listBody.CallbackURL
+
+	ret, err = doRequest[RspBody](ctx, c, "POST", "save_list_body", body)
+	if err != nil {
+		return nil, fmt.Errorf("[saveListBody] 发送 保存消息共同体 失败, err: %w", err)
 	}
 	return
 }
@@ -0,0 +1,33 @@
+package getui
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// minServerSkew 由 Date 头推算的偏差小于该值时视为时钟一致，Date 头只精确到秒
+const minServerSkew = 2 * time.Second
+
+// now 计算签名时间戳使用的时间，加上 ClockSkew 及由服务器 Date 头推算的偏差
+func (c *client) now() time.Time {
+	return time.Now().Add(c.ClockSkew + time.Duration(atomic.LoadInt64(&c.serverSkew)))
+}
+
+// observeServerDate 由响应的 Date 头推算本地时钟的偏差
+// 未开启 ClockSkewFromServer 或没有 Date 头时不处理
+func (c *client) observeServerDate(rsp *http.Response) {
+	if !c.ClockSkewFromServer || rsp == nil {
+		return
+	}
+	date, err := http.ParseTime(rsp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+
+	skew := date.Sub(time.Now())
+	if skew > -minServerSkew && skew < minServerSkew {
+		skew = 0
+	}
+	atomic.StoreInt64(&c.serverSkew, int64(skew))
+}
@@ -0,0 +1,186 @@
+package getui
+
+// 请求body的复制与修改
+// With 方法在副本上修改并返回副本，原body不变，可放心地基于同一个模板逐个定制收件人：
+//
+//	base := getui.SingleReqBody{...}
+//	for _, u := range users {
+//		client.PushToSingle(base.WithCID(u.CID).WithText("你好 " + u.Name))
+//	}
+
+// Clone 深拷贝，副本与原body不共享切片与指针
+func (b SingleReqBody) Clone() SingleReqBody {
+	b.Notification = b.Notification.clone()
+	b.PushInfo = b.PushInfo.Clone()
+	b.SMS = b.SMS.clone()
+	return b
+}
+
+// WithCID 设置单推目标cid，同时清空 Alias
+func (b SingleReqBody) WithCID(cid string) SingleReqBody {
+	b = b.Clone()
+	b.CID, b.Alias = cid, ""
+	return b
+}
+
+// WithAlias 设置单推目标别名，同时清空 CID
+func (b SingleReqBody) WithAlias(alias string) SingleReqBody {
+	b = b.Clone()
+	b.CID, b.Alias = "", alias
+	return b
+}
+
+// WithTitle 设置通知标题与iOS提示标题
+func (b SingleReqBody) WithTitle(title string) SingleReqBody {
+	b = b.Clone()
+	b.Notification.Style.Title = title
+	b.PushInfo.Aps.Alert.Title = title
+	return b
+}
+
+// WithText 设置通知内容与iOS提示内容
+func (b SingleReqBody) WithText(text string) SingleReqBody {
+	b = b.Clone()
+	b.Notification.Style.Text = text
+	b.PushInfo.Aps.Alert.Body = text
+	return b
+}
+
+// WithTransmission 设置透传内容
+func (b SingleReqBody) WithTransmission(content string) SingleReqBody {
+	b = b.Clone()
+	b.Notification.TransmissionContent = content
+	return b
+}
+
+// WithRequestID 设置requestid
+func (b SingleReqBody) WithRequestID(id string) SingleReqBody {
+	b = b.Clone()
+	b.RequestID = id
+	return b
+}
+
+// Clone 深拷贝，副本与原body不共享切片与指针
+func (b ListReqBody) Clone() ListReqBody {
+	b.Notification = b.Notification.clone()
+	b.PushInfo = b.PushInfo.Clone()
+	b.CID = cloneStrings(b.CID)
+	b.SMS = b.SMS.clone()
+	return b
+}
+
+// WithCIDs 设置list推的目标cid，同时清空 Alias 与 TaskID
+func (b ListReqBody) WithCIDs(cids ...string) ListReqBody {
+	b = b.Clone()
+	b.CID, b.Alias, b.TaskID = cloneStrings(cids), "", ""
+	return b
+}
+
+// WithTitle 设置通知标题与iOS提示标题，同时清空 TaskID
+func (b ListReqBody) WithTitle(title string) ListReqBody {
+	b = b.Clone()
+	b.Notification.Style.Title = title
+	b.PushInfo.Aps.Alert.Title = title
+	b.TaskID = ""
+	return b
+}
+
+// WithText 设置通知内容与iOS提示内容，同时清空 TaskID
+func (b ListReqBody) WithText(text string) ListReqBody {
+	b = b.Clone()
+	b.Notification.Style.Text = text
+	b.PushInfo.Aps.Alert.Body = text
+	b.TaskID = ""
+	return b
+}
+
+// WithTransmission 设置透传内容，同时清空 TaskID
+func (b ListReqBody) WithTransmission(content string) ListReqBody {
+	b = b.Clone()
+	b.Notification.TransmissionContent = content
+	b.TaskID = ""
+	return b
+}
+
+// Clone 深拷贝，副本与原body不共享切片与指针
+func (b AppReqBody) Clone() AppReqBody {
+	b.Notification = b.Notification.clone()
+	if b.PushInfo != nil {
+		p := b.PushInfo.Clone()
+		b.PushInfo = &p
+	}
+	b.SMS = b.SMS.clone()
+	if b.Condition != nil {
+		conditions := make([]AppReqBodyCondition, len(b.Condition))
+		for i, c := range b.Condition {
+			c.Values = cloneStrings(c.Values)
+			conditions[i] = c
+		}
+		b.Condition = conditions
+	}
+	return b
+}
+
+// WithCondition 追加toapp条件
+func (b AppReqBody) WithCondition(conditions ...AppReqBodyCondition) AppReqBody {
+	b = b.Clone()
+	b.Condition = append(b.Condition, AppReqBody{Condition: conditions}.Clone().Condition...)
+	return b
+}
+
+// WithTitle 设置通知标题，PushInfo 不为空时同时设置iOS提示标题
+func (b AppReqBody) WithTitle(title string) AppReqBody {
+	b = b.Clone()
+	b.Notification.Style.Title = title
+	if b.PushInfo != nil {
+		b.PushInfo.Aps.Alert.Title = title
+	}
+	return b
+}
+
+// WithText 设置通知内容，PushInfo 不为空时同时设置iOS提示内容
+func (b AppReqBody) WithText(text string) AppReqBody {
+	b = b.Clone()
+	b.Notification.Style.Text = text
+	if b.PushInfo != nil {
+		b.PushInfo.Aps.Alert.Body = text
+	}
+	return b
+}
+
+// WithRequestID 设置requestid
+func (b AppReqBody) WithRequestID(id string) AppReqBody {
+	b = b.Clone()
+	b.RequestID = id
+	return b
+}
+
+// Clone 深拷贝，副本与原 PushInfo 不共享切片与指针
+func (p PushInfo) Clone() PushInfo {
+	if p.Multimedia != nil {
+		p.Multimedia = append([]PushInfoMultimedia(nil), p.Multimedia...)
+	}
+	if p.Media != nil {
+		m := *p.Media
+		p.Media = &m
+	}
+	return p
+}
+
+// clone 深拷贝 IsRing、IsVibrate
+func (n Notification) clone() Notification {
+	if n.Style.IsRing != nil {
+		n.Style.IsRing = Bool(*n.Style.IsRing)
+	}
+	if n.Style.IsVibrate != nil {
+		n.Style.IsVibrate = Bool(*n.Style.IsVibrate)
+	}
+	return n
+}
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
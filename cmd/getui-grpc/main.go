@@ -0,0 +1,74 @@
+// getui-grpc 个推推送的gRPC服务，供非Go服务推送，无需各自实现个推的鉴权与签名
+//
+//	getui-grpc [-addr 127.0.0.1:9090]
+//
+// 凭证从环境变量 GETUI_APP_ID、GETUI_APP_KEY、GETUI_MASTER_SECRET 读取，接口定义见 getui.proto
+//
+// 设置了环境变量 GETUI_GRPC_TOKEN 时，请求需带 metadata "authorization: Bearer <token>"；
+// 监听非本机地址时必须设置，避免任何能访问端口的人都能推送
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/printfcoder/getui/internal/netutil"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:9090", "监听地址，默认只监听本机")
+	flag.Parse()
+
+	err := run(*addr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(addr string) error {
+	token := os.Getenv("GETUI_GRPC_TOKEN")
+	if len(token) == 0 && !netutil.IsLoopback(addr) {
+		return fmt.Errorf("监听非本机地址 %s 时需设置 GETUI_GRPC_TOKEN", addr)
+	}
+
+	client, err := getui.New(getui.InitParams{CredentialsProvider: getui.EnvCredentials("")})
+	if err != nil {
+		return fmt.Errorf("创建个推客户端失败, err: %w", err)
+	}
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("监听 %s 失败, err: %w", addr, err)
+	}
+
+	var opts []grpc.ServerOption
+	if len(token) > 0 {
+		opts = append(opts, grpc.UnaryInterceptor(tokenAuth(token)))
+	}
+	srv := grpc.NewServer(opts...)
+	registerPushServer(srv, &pushServer{client: client})
+
+	// 收到退出信号后不再接受新请求，等待进行中的推送完成
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-stop
+		srv.GracefulStop()
+	}()
+
+	err = srv.Serve(lis)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	client.Shutdown(ctx)
+	return err
+}
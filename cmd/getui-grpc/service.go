@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/printfcoder/getui"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// pushServer getui.v1.Push 服务的实现
+type pushServer struct {
+	client getui.Client
+}
+
+// listRequest PushList 的请求，补充 ListReqBody 中不随 push_list 发送的字段
+type listRequest struct {
+	getui.ListReqBody
+	GroupName   string         `json:"group_name"`
+	SMS         *getui.SMSInfo `json:"sms_message"`
+	CallbackURL string         `json:"callback_url"`
+}
+
+// userStatusRequest UserStatus 的请求
+type userStatusRequest struct {
+	CID string `json:"cid"`
+}
+
+func (s *pushServer) pushSingle(ctx context.Context, body getui.SingleReqBody) (interface{}, error) {
+	return s.client.PushToSingleContext(ctx, body)
+}
+
+func (s *pushServer) pushList(ctx context.Context, req listRequest) (interface{}, error) {
+	body := req.ListReqBody
+	body.GroupName, body.SMS, body.CallbackURL = req.GroupName, req.SMS, req.CallbackURL
+	return s.client.PushToListContext(ctx, body)
+}
+
+// pushApp 条件为空即全量推送，不通过gRPC开放
+func (s *pushServer) pushApp(ctx context.Context, body getui.AppReqBody) (interface{}, error) {
+	if len(body.Condition) == 0 {
+		return nil, status.Error(codes.PermissionDenied, "condition 不能为空, 不支持全量推送")
+	}
+	return s.client.PushToAppContext(ctx, body)
+}
+
+func (s *pushServer) userStatus(ctx context.Context, req userStatusRequest) (interface{}, error) {
+	if len(req.CID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "cid 不能为空")
+	}
+	return s.client.UserStatus(req.CID)
+}
+
+// registerPushServer 注册 getui.v1.Push 服务
+// 没有生成的桩代码，请求与返回均为 google.protobuf.Struct，按json字段与 getui 中的结构互转
+func registerPushServer(r grpc.ServiceRegistrar, s *pushServer) {
+	r.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "getui.v1.Push",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "PushSingle", Handler: handler("PushSingle", s.pushSingle)},
+			{MethodName: "PushList", Handler: handler("PushList", s.pushList)},
+			{MethodName: "PushApp", Handler: handler("PushApp", s.pushApp)},
+			{MethodName: "UserStatus", Handler: handler("UserStatus", s.userStatus)},
+		},
+		Metadata: "getui.proto",
+	}, s)
+}
+
+// handler 将 Struct 请求解析为 Req 后调用 call，并将结果转为 Struct
+func handler[Req any](method string, call func(ctx context.Context, req Req) (interface{}, error)) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := new(structpb.Struct)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		invoke := func(ctx context.Context, in interface{}) (interface{}, error) {
+			var req Req
+			err := convert(in.(*structpb.Struct).AsMap(), &req)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "请求格式错误: %s", err)
+			}
+			ret, err := call(ctx, req)
+			if err != nil {
+				return nil, grpcError(err)
+			}
+			return toStruct(ret)
+		}
+		if interceptor == nil {
+			return invoke(ctx, in)
+		}
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/getui.v1.Push/" + method}
+		return interceptor(ctx, in, info, invoke)
+	}
+}
+
+// convert 经json转换结构
+func convert(from, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, to)
+}
+
+// toStruct 将返回结果转为 Struct
+func toStruct(v interface{}) (*structpb.Struct, error) {
+	var m map[string]interface{}
+	err := convert(v, &m)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "返回结果转换失败: %s", err)
+	}
+	return structpb.NewStruct(m)
+}
+
+// grpcError 将客户端的错误映射为gRPC状态码
+func grpcError(err error) error {
+	var te *getui.TransportError
+	code := codes.Internal
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, getui.ErrInvalidTarget):
+		code = codes.InvalidArgument
+	case errors.Is(err, getui.ErrNoUser), errors.Is(err, getui.ErrTaskNotFound):
+		code = codes.NotFound
+	case errors.Is(err, getui.ErrRateLimited):
+		code = codes.ResourceExhausted
+	case errors.Is(err, getui.ErrTokenExpired):
+		code = codes.Unauthenticated
+	case errors.Is(err, getui.ErrBroadcastNotConfirmed):
+		code = codes.PermissionDenied
+	case errors.As(err, &te):
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Error())
+}
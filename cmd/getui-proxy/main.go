@@ -0,0 +1,78 @@
+// getui-proxy 个推推送的HTTP边车，多个服务共用一个配置好鉴权、token刷新与重试的客户端
+//
+//	getui-proxy [-addr 127.0.0.1:8080] [-max-retries 2]
+//
+// 凭证从环境变量 GETUI_APP_ID、GETUI_APP_KEY、GETUI_MASTER_SECRET 读取
+// 设置了环境变量 GETUI_PROXY_TOKEN 时，/push 需带请求头 "Authorization: Bearer <token>"；
+// 监听非本机地址时必须设置，避免任何能访问端口的人都能推送
+//
+// 接口：
+//
+//	POST /push           简化的推送请求，见 pushRequest
+//	GET  /healthz        客户端可用时返回200
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/printfcoder/getui/internal/netutil"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8080", "监听地址，默认只监听本机")
+	maxRetries := flag.Int("max-retries", 2, "单个请求的最大重试次数，推送只在请求未发出时重试")
+	flag.Parse()
+
+	err := run(*addr, *maxRetries)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(addr string, maxRetries int) error {
+	token := os.Getenv("GETUI_PROXY_TOKEN")
+	if len(token) == 0 && !netutil.IsLoopback(addr) {
+		return fmt.Errorf("监听非本机地址 %s 时需设置 GETUI_PROXY_TOKEN", addr)
+	}
+
+	client, err := getui.New(getui.InitParams{
+		CredentialsProvider: getui.EnvCredentials(""),
+		// 默认的 RetryPolicy 推送只在请求未发出或429时重试，避免重复推送
+		MaxRetries: maxRetries,
+	})
+	if err != nil {
+		return fmt.Errorf("创建个推客户端失败, err: %w", err)
+	}
+
+	srv := &http.Server{Addr: addr, Handler: newProxy(client, token)}
+
+	// 收到退出信号后不再接受新请求，等待进行中的推送完成
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}()
+
+	err = srv.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	client.Shutdown(ctx)
+	return err
+}
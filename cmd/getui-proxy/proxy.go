@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/printfcoder/getui"
+)
+
+// maxRequestBytes 推送请求body上限
+const maxRequestBytes = 4 << 20
+
+// errBadRequest 简化请求的内容错误
+var errBadRequest = errors.New("错误的请求")
+
+// pushRequest 简化的推送请求
+// 一个cid或alias时单推，多个cid时分片list推；只有 payload 时为透传消息
+type pushRequest struct {
+	CIDs  []string `json:"cids"`
+	Alias string   `json:"alias"`
+	Title string   `json:"title"`
+	Text  string   `json:"text"`
+	// Payload 透传内容，由应用自行处理
+	Payload string `json:"payload"`
+	// OfflineSeconds 离线存储时长 单位秒，0 表示不离线存储
+	OfflineSeconds int    `json:"offline_seconds"`
+	RequestID      string `json:"request_id"`
+}
+
+// pushResponse 推送结果
+type pushResponse struct {
+	Result  string   `json:"result"`
+	TaskIDs []string `json:"taskids,omitempty"`
+	// Failed 分片list推中发送失败的cid，可由调用方重试
+	Failed []string `json:"failed,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// proxy HTTP接口
+type proxy struct {
+	client getui.Client
+	// token 不为空时 /push 需带 "Authorization: Bearer <token>"
+	token string
+	mux   *http.ServeMux
+}
+
+func newProxy(client getui.Client, token string) http.Handler {
+	p := &proxy{client: client, token: token, mux: http.NewServeMux()}
+	p.mux.HandleFunc("/push", p.push)
+	p.mux.HandleFunc("/healthz", p.healthz)
+	return p.mux
+}
+
+func (p *proxy) healthz(w http.ResponseWriter, req *http.Request) {
+	if len(p.client.AuthToken()) == 0 {
+		http.Error(w, "no auth token", http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte("ok"))
+}
+
+func (p *proxy) push(w http.ResponseWriter, req *http.Request) {
+	if !p.authorized(req) {
+		writeJSON(w, http.StatusUnauthorized, pushResponse{Result: "error", Error: "token 错误"})
+		return
+	}
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var r pushRequest
+	data, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxRequestBytes))
+	if err == nil {
+		err = json.Unmarshal(data, &r)
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, pushResponse{Result: "error", Error: fmt.Sprintf("错误的请求: %s", err)})
+		return
+	}
+
+	ctx := req.Context()
+	if len(r.RequestID) > 0 {
+		ctx = getui.WithRequestID(ctx, r.RequestID)
+	}
+	ret, err := p.send(ctx, r)
+	if err != nil {
+		ret.Result, ret.Error = "error", err.Error()
+		writeJSON(w, httpStatus(err), ret)
+		return
+	}
+	ret.Result = "ok"
+	writeJSON(w, http.StatusOK, ret)
+}
+
+// authorized 校验请求头中的token，未配置token时不校验
+func (p *proxy) authorized(req *http.Request) bool {
+	if len(p.token) == 0 {
+		return true
+	}
+	got := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(got), []byte(p.token)) == 1
+}
+
+// send 按目标数选择单推或分片list推
+func (p *proxy) send(ctx context.Context, r pushRequest) (pushResponse, error) {
+	var ret pushResponse
+	msg, notification, pushInfo, err := r.message()
+	if err != nil {
+		return ret, err
+	}
+
+	if len(r.CIDs) > 1 {
+		body := getui.ListReqBody{Message: msg, Notification: notification, PushInfo: pushInfo, CID: r.CIDs}
+		if err := r.setTTL(&body); err != nil {
+			return ret, err
+		}
+		chunked, err := p.client.PushToListChunked(ctx, body, 0)
+		if chunked != nil {
+			ret.TaskIDs = chunked.Campaign().TaskIDs
+			ret.Failed = chunked.Pending()
+		}
+		return ret, err
+	}
+
+	body := getui.SingleReqBody{Message: msg, Notification: notification, PushInfo: pushInfo, Alias: r.Alias}
+	if len(r.CIDs) == 1 {
+		body.CID = r.CIDs[0]
+	}
+	if err := r.setTTL(&body); err != nil {
+		return ret, err
+	}
+	single, err := p.client.PushToSingleContext(ctx, body)
+	if single != nil {
+		ret.TaskIDs = []string{single.TaskID}
+	}
+	return ret, err
+}
+
+// ttlSetter 可设置离线时长的推送body
+type ttlSetter interface {
+	SetTTL(d time.Duration) error
+}
+
+// setTTL 请求指定了离线时长时设置到body
+func (r pushRequest) setTTL(body ttlSetter) error {
+	if r.OfflineSeconds <= 0 {
+		return nil
+	}
+	err := body.SetTTL(time.Duration(r.OfflineSeconds) * time.Second)
+	if err != nil {
+		return fmt.Errorf("%s, err: %w", err, errBadRequest)
+	}
+	return nil
+}
+
+// message 由简化请求生成消息体
+func (r pushRequest) message() (msg getui.Message, notification getui.Notification, pushInfo getui.PushInfo, err error) {
+	if len(r.Title) == 0 && len(r.Text) == 0 && len(r.Payload) == 0 {
+		return msg, notification, pushInfo, fmt.Errorf("title、text、payload 不能都为空, err: %w", errBadRequest)
+	}
+
+	msg.MsgType = "notification"
+	if len(r.Title) == 0 && len(r.Text) == 0 {
+		msg.MsgType = "transmission"
+		notification.TransmissionType = true
+	}
+	notification.Style.Title, notification.Style.Text = r.Title, r.Text
+	notification.TransmissionContent = r.Payload
+	pushInfo.Aps.Alert.Title, pushInfo.Aps.Alert.Body = r.Title, r.Text
+	if msg.MsgType == "transmission" {
+		pushInfo.Aps.ContentAvailable = 1
+	}
+	return msg, notification, pushInfo, nil
+}
+
+// httpStatus 将客户端的错误映射为http状态码
+func httpStatus(err error) int {
+	var te *getui.TransportError
+	switch {
+	case errors.Is(err, errBadRequest), errors.Is(err, getui.ErrInvalidTarget):
+		return http.StatusBadRequest
+	case errors.Is(err, getui.ErrNoUser):
+		return http.StatusNotFound
+	case errors.Is(err, getui.ErrDuplicatePush):
+		return http.StatusConflict
+	case errors.Is(err, getui.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, getui.ErrClientClosed):
+		return http.StatusServiceUnavailable
+	case errors.As(err, &te), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
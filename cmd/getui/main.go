@@ -0,0 +1,46 @@
+// getui 个推调试命令行
+//
+//	getui sign -appkey <appkey> [-timestamp <毫秒时间戳>] [-algorithm sha256|md5]
+//	getui replay -file <golden文件> [-host <接口地址>] [-yes]
+//
+// 凭证从环境变量 GETUI_APP_ID、GETUI_APP_KEY、GETUI_MASTER_SECRET 读取，也可通过 -appid 等参数指定
+// replay 未指定 -host 时回放到个推正式接口，发送前需要输入 yes 确认
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// commands 子命令
+var commands = map[string]func(args []string) error{
+	"sign":   signCmd,
+	"replay": replayCmd,
+}
+
+func main() {
+	if len(os.Args) < 2 || commands[os.Args[1]] == nil {
+		usage()
+		os.Exit(2)
+	}
+
+	err := commands[os.Args[1]](os.Args[2:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: getui <命令> [参数]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "命令:")
+	fmt.Fprintln(os.Stderr, "  sign    计算鉴权签名并输出 auth_sign 请求body")
+	fmt.Fprintln(os.Stderr, "  replay  回放录制的请求到沙箱、模拟服务或正式接口")
+}
+
+// newFlagSet 子命令参数
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("getui "+name, flag.ExitOnError)
+}
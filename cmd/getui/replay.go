@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/printfcoder/getui"
+)
+
+// realAPIHost 个推正式接口地址，回放到该地址前需要确认
+const realAPIHost = "https://restapi.getui.com/v1/"
+
+// replayCmd 回放 Recorder 录制的请求，复现用户反馈的推送问题
+func replayCmd(args []string) error {
+	fs := newFlagSet("replay")
+	file := fs.String("file", "", "Recorder 录制的golden文件")
+	host := fs.String("host", "", "接口地址，如沙箱或本地模拟服务 http://127.0.0.1:8080/v1/，默认个推正式接口")
+	appID := fs.String("appid", os.Getenv("GETUI_APP_ID"), "AppID，默认读取 GETUI_APP_ID")
+	appKey := fs.String("appkey", os.Getenv("GETUI_APP_KEY"), "AppKey，默认读取 GETUI_APP_KEY")
+	masterSecret := fs.String("mastersecret", os.Getenv("GETUI_MASTER_SECRET"), "MasterSecret，默认读取 GETUI_MASTER_SECRET")
+	yes := fs.Bool("yes", false, "回放到个推正式接口时不再确认")
+	fs.Parse(args)
+
+	if len(*file) == 0 {
+		return fmt.Errorf("file 必填")
+	}
+	if len(*appID) == 0 || len(*appKey) == 0 || len(*masterSecret) == 0 {
+		return fmt.Errorf("appid、appkey 与 mastersecret 必填")
+	}
+
+	interactions, err := getui.ReadInteractions(*file)
+	if err != nil {
+		return err
+	}
+
+	target := *host
+	if len(target) == 0 {
+		target = realAPIHost
+	}
+	if strings.TrimSuffix(target, "/") == strings.TrimSuffix(realAPIHost, "/") && !*yes {
+		ok, err := confirm(os.Stdin, os.Stdout, interactions, *appID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("已取消")
+		}
+	}
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        *appID,
+		AppKey:       *appKey,
+		MasterSecret: *masterSecret,
+		APIHosts:     []string{target},
+		UserAgent:    "getui-replay",
+	})
+	if err != nil {
+		return err
+	}
+	defer client.CloseAuth()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results, err := client.ReplayInteractions(ctx, interactions)
+	for _, r := range results {
+		fmt.Printf("%s %s\n", r.Recorded.Method, r.Path)
+		fmt.Printf("  录制: %s\n", r.Recorded.ResponseBody)
+		if r.Err != nil {
+			fmt.Printf("  回放: 失败, %v\n", r.Err)
+			continue
+		}
+		fmt.Printf("  回放: result=%s status=%s taskid=%s\n", r.Ret.Result, r.Ret.Status, r.Ret.TaskID)
+	}
+	return err
+}
+
+// confirm 列出将要发送的请求，输入 yes 确认后才回放到正式接口
+func confirm(in io.Reader, out io.Writer, interactions []getui.Interaction, appID string) (bool, error) {
+	fmt.Fprintf(out, "将向个推正式接口（appid: %s）发送以下请求，用户会真实收到推送：\n", appID)
+	for _, it := range interactions {
+		if strings.HasSuffix(it.Path, "/auth_sign") || strings.HasSuffix(it.Path, "/auth_close") {
+			continue
+		}
+		fmt.Fprintf(out, "  %s %s\n", it.Method, it.Path)
+	}
+	fmt.Fprint(out, "输入 yes 确认: ")
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("读取确认失败, err: %w", err)
+	}
+	return strings.TrimSpace(line) == "yes", nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/printfcoder/getui"
+)
+
+// signCmd 计算签名，排查 sign_error
+func signCmd(args []string) error {
+	fs := newFlagSet("sign")
+	appKey := fs.String("appkey", os.Getenv("GETUI_APP_KEY"), "AppKey，默认读取 GETUI_APP_KEY")
+	masterSecret := fs.String("mastersecret", os.Getenv("GETUI_MASTER_SECRET"), "MasterSecret，默认读取 GETUI_MASTER_SECRET")
+	timestamp := fs.String("timestamp", "", "毫秒时间戳，默认当前时间")
+	algorithm := fs.String("algorithm", string(getui.SignSHA256), "签名算法 sha256 或 md5，需与应用使用的一致")
+	fs.Parse(args)
+
+	if len(*appKey) == 0 || len(*masterSecret) == 0 {
+		return fmt.Errorf("appkey 与 mastersecret 必填")
+	}
+
+	ret, err := getui.DebugSign(*appKey, *masterSecret, *timestamp, getui.SignAlgorithm(*algorithm))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("appkey:    %s\n", ret.AppKey)
+	fmt.Printf("timestamp: %s\n", ret.Timestamp)
+	fmt.Printf("algorithm: %s\n", ret.Algorithm)
+	fmt.Printf("sign:      %s\n", ret.Sign)
+	fmt.Printf("body:      %s\n", ret.Body)
+	return nil
+}
@@ -0,0 +1,25 @@
+package getui
+
+import "encoding/json"
+
+// Codec 个推接口请求与响应body的JSON编解码
+// 默认使用 encoding/json，可替换为 jsoniter、sonic 等兼容实现以降低大cid列表序列化的开销
+// 只用于接口body：透传内容由 PayloadSerializer 序列化；审计记录、推送日志、去重key、DebugSign 等
+// 需要与配置无关的稳定输出（如审计哈希），固定使用 encoding/json
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StdCodec 基于 encoding/json 的 Codec
+type StdCodec struct{}
+
+// Marshal 见 json.Marshal
+func (StdCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal 见 json.Unmarshal
+func (StdCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
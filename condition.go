@@ -0,0 +1,63 @@
+package getui
+
+import "fmt"
+
+// toapp 条件的 key
+// 参考资料 http://docs.getui.com/server/rest/push/#5-toapp
+const (
+	// ConditionPhoneType 手机类型，值见 PhoneTypeAndroid 等
+	ConditionPhoneType = "phonetype"
+	// ConditionRegion 地区，值为地区编码
+	ConditionRegion = "region"
+	// ConditionTag 用户标签
+	ConditionTag = "tag"
+	// ConditionCustomTag 自定义标签
+	ConditionCustomTag = "custom_tag"
+	// ConditionPortrait 用户画像，值为画像编号，见 PortraitTag
+	ConditionPortrait = "portrait"
+)
+
+// toapp 条件的 opt_type，同一条件内多个值之间的关系；条件之间总是"且"
+const (
+	// OptTypeOr 满足任意一个值
+	OptTypeOr = "0"
+	// OptTypeAnd 满足全部值
+	OptTypeAnd = "1"
+	// OptTypeNot 不满足任何一个值
+	OptTypeNot = "2"
+)
+
+// NewCondition 创建toapp条件
+func NewCondition(key, optType string, values ...string) AppReqBodyCondition {
+	return AppReqBodyCondition{Key: key, Values: values, OptType: optType}
+}
+
+// 手机类型，用于 ConditionPhoneType 条件
+const (
+	PhoneTypeAndroid = "ANDROID"
+	PhoneTypeIOS     = "IOS"
+)
+
+// ValidatePhoneType 检查手机类型是否为 PhoneTypeAndroid 或 PhoneTypeIOS
+func ValidatePhoneType(phoneType string) error {
+	switch phoneType {
+	case PhoneTypeAndroid, PhoneTypeIOS:
+		return nil
+	}
+	return fmt.Errorf("错误的手机类型 %q, 需为 %s 或 %s, err: %w", phoneType, PhoneTypeAndroid, PhoneTypeIOS, ErrInvalidTarget)
+}
+
+// validateConditions 检查toapp条件中的手机类型
+func validateConditions(conditions []AppReqBodyCondition) error {
+	for _, c := range conditions {
+		if c.Key != ConditionPhoneType {
+			continue
+		}
+		for _, v := range c.Values {
+			if err := ValidatePhoneType(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
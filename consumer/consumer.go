@@ -0,0 +1,237 @@
+// Package consumer 从消息队列读取推送任务并通过 getui.Client 发送
+// 业务方只需向队列投递约定格式的JSON消息，无需引入SDK；Kafka、NSQ 的接入示例见 _examples 目录
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/printfcoder/getui"
+)
+
+// 推送任务类型
+const (
+	JobTypeSingle = "single"
+	JobTypeList   = "list"
+	JobTypeApp    = "app"
+)
+
+// PushJob 队列消息的JSON格式
+// {"id":"order-1","type":"single","single":{...SingleReqBody...}}
+type PushJob struct {
+	// ID 任务id，可为空；OnResult 可按该id回写结果
+	ID     string               `json:"id,omitempty"`
+	Type   string               `json:"type"`
+	Single *getui.SingleReqBody `json:"single,omitempty"`
+	List   *getui.ListReqBody   `json:"list,omitempty"`
+	App    *getui.AppReqBody    `json:"app,omitempty"`
+}
+
+// Message 队列中的一条消息
+type Message interface {
+	// Body 消息内容，即 PushJob 的JSON
+	Body() []byte
+	// Ack 处理完成，不再投递
+	Ack() error
+	// Nack 处理失败，由队列重新投递
+	Nack() error
+}
+
+// Source 消息来源，由各队列实现
+type Source interface {
+	// Receive 阻塞读取下一条消息，ctx结束时返回
+	Receive(ctx context.Context) (Message, error)
+}
+
+// Options 消费配置
+type Options struct {
+	// Concurrency 并发处理的消息数 默认4
+	Concurrency int
+	// Retries 临时故障（见 getui.IsTransient）时在本地重试的次数 默认0不重试，交由队列重新投递
+	// 客户端已配置 MaxRetries 时不要再设置，避免重试次数相乘
+	Retries int
+	// Backoff 首次重试的等待时间，之后每次翻倍 默认1秒
+	Backoff time.Duration
+	// OnError 消息无法解析、发送失败时回调，可为空
+	OnError func(body []byte, err error)
+	// OnResult 发送成功或重试后仍失败时回调，可为空，无法解析的消息不回调
+	OnResult func(ctx context.Context, job PushJob, ret *getui.RspBody, err error)
+	// QPS 所有并发合计每秒最多发送的请求数（含重试） 默认不限制
+	QPS float64
+}
+
+// Consumer 队列消费者
+type Consumer struct {
+	client getui.Client
+	source Source
+	opts   Options
+	// tick 配置了 QPS 时每次发送前等待
+	tick <-chan time.Time
+}
+
+// New 创建消费者
+func New(client getui.Client, source Source, opts Options) *Consumer {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+	return &Consumer{client: client, source: source, opts: opts}
+}
+
+// Run 持续消费直到ctx结束，返回前等待处理中的消息完成
+func (c *Consumer) Run(ctx context.Context) error {
+	if c.opts.QPS > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / c.opts.QPS))
+		defer ticker.Stop()
+		c.tick = ticker.C
+	}
+
+	sem := make(chan struct{}, c.opts.Concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		}
+
+		msg, err := c.source.Receive(ctx)
+		if err != nil {
+			<-sem
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("[consumer.Run] 读取消息失败, err: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			c.handle(ctx, msg)
+		}()
+	}
+}
+
+// handle 处理一条消息
+// 无法解析的消息与cid错误、用户不存在等重试也不会成功的失败直接确认并回调 OnError，避免反复投递；
+// 网络错误、限流等临时故障交由队列重新投递；确认失败同样回调 OnError
+func (c *Consumer) handle(ctx context.Context, msg Message) {
+	job := PushJob{}
+	err := json.Unmarshal(msg.Body(), &job)
+	if err == nil {
+		err = job.validate()
+	}
+	if err != nil {
+		c.onError(msg.Body(), fmt.Errorf("[consumer] 错误的推送任务, err: %w", err))
+		c.ack(msg)
+		return
+	}
+
+	ret, err := c.send(ctx, job)
+	if c.opts.OnResult != nil {
+		c.opts.OnResult(ctx, job, ret, err)
+	}
+	if err != nil {
+		c.onError(msg.Body(), err)
+		if !retryable(err) {
+			c.ack(msg)
+			return
+		}
+		c.nack(msg)
+		return
+	}
+
+	c.ack(msg)
+}
+
+// ack 确认消息，失败时回调 OnError
+func (c *Consumer) ack(msg Message) {
+	if err := msg.Ack(); err != nil {
+		c.onError(msg.Body(), fmt.Errorf("[consumer] 确认消息失败, err: %w", err))
+	}
+}
+
+// nack 交由队列重新投递，失败时回调 OnError
+func (c *Consumer) nack(msg Message) {
+	if err := msg.Nack(); err != nil {
+		c.onError(msg.Body(), fmt.Errorf("[consumer] 重新投递消息失败, err: %w", err))
+	}
+}
+
+// retryable 临时故障，稍后重新投递可能成功；客户端已关闭时同样交由其它实例处理
+func retryable(err error) bool {
+	if errors.Is(err, getui.ErrDuplicatePush) {
+		return false
+	}
+	return getui.IsTransient(err) || errors.Is(err, getui.ErrClientClosed)
+}
+
+// send 按 QPS 限速发送，临时故障时按指数退避重试
+func (c *Consumer) send(ctx context.Context, job PushJob) (ret *getui.RspBody, err error) {
+	backoff := c.opts.Backoff
+	for i := 0; ; i++ {
+		if c.tick != nil {
+			select {
+			case <-c.tick:
+			case <-ctx.Done():
+				if err == nil {
+					err = ctx.Err()
+				}
+				return ret, err
+			}
+		}
+
+		ret, err = job.send(ctx, c.client)
+		if err == nil || !getui.IsTransient(err) || i >= c.opts.Retries {
+			return ret, err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ret, err
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Consumer) onError(body []byte, err error) {
+	if c.opts.OnError != nil {
+		c.opts.OnError(body, err)
+	}
+}
+
+func (j PushJob) validate() error {
+	switch {
+	case j.Type == JobTypeSingle && j.Single != nil:
+	case j.Type == JobTypeList && j.List != nil:
+	case j.Type == JobTypeApp && j.App != nil:
+	default:
+		return fmt.Errorf("type 为 %q 时缺少对应的请求body", j.Type)
+	}
+	return nil
+}
+
+func (j PushJob) send(ctx context.Context, client getui.Client) (ret *getui.RspBody, err error) {
+	switch j.Type {
+	case JobTypeSingle:
+		ret, err = client.PushToSingleContext(ctx, *j.Single)
+	case JobTypeList:
+		ret, err = client.PushToListContext(ctx, *j.List)
+	default:
+		ret, err = client.PushToAppContext(ctx, *j.App)
+	}
+	return
+}
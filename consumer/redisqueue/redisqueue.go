@@ -0,0 +1,232 @@
+// Package redisqueue 基于Redis list的推送任务队列，配合 consumer 包即为开箱即用的推送worker
+//
+// 生产方 LPUSH consumer.PushJob 的JSON到队列，worker BRPOP 读取并发送，
+// 带 id 的任务的结果写入 {ResultPrefix}{id}；
+// 临时故障的任务按退避时间放入 {key}:delayed 延迟重投，失败 MaxAttempts 次后移入死信队列 {key}:dead：
+//
+//	q := redisqueue.New(rdb, "getui:jobs", redisqueue.Options{})
+//	consumer.New(client, q, q.ConsumerOptions(consumer.Options{QPS: 50})).Run(ctx)
+package redisqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/printfcoder/getui/consumer"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultResultPrefix 默认结果key前缀
+const DefaultResultPrefix = "getui:result:"
+
+// Options 队列配置
+type Options struct {
+	// ResultPrefix 结果key前缀 默认 DefaultResultPrefix
+	ResultPrefix string
+	// ResultTTL 结果保留时长 默认24小时
+	ResultTTL time.Duration
+	// PollTimeout 单次BRPOP的等待时间，ctx结束最多延迟该时长被发现 默认1秒
+	PollTimeout time.Duration
+	// MaxAttempts 任务最多投递的次数，超过后移入死信队列 默认5
+	MaxAttempts int
+	// RetryBackoff 首次重投前的等待时间，之后每次翻倍，最长10分钟 默认1秒
+	RetryBackoff time.Duration
+	// DeadLetterKey 死信队列的key，元素为 Envelope 的JSON 默认 {key}:dead
+	DeadLetterKey string
+}
+
+// maxRetryBackoff 重投等待时间的上限
+const maxRetryBackoff = 10 * time.Minute
+
+// Envelope 重投与死信队列中的任务，带已投递的次数
+// 生产方直接投递 consumer.PushJob 的JSON即可，无需使用该结构
+type Envelope struct {
+	Attempts int             `json:"attempts"`
+	Job      json.RawMessage `json:"job"`
+	// NackedAt 最后一次失败的毫秒时间戳
+	NackedAt int64 `json:"nacked_at"`
+}
+
+// Result 写入结果key的JSON
+type Result struct {
+	ID string `json:"id"`
+	// Status 为 "ok" 或 "failed"
+	Status string `json:"status"`
+	TaskID string `json:"taskid,omitempty"`
+	// Result 个推返回的 result
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Time 完成的毫秒时间戳
+	Time int64 `json:"time"`
+}
+
+// Queue Redis推送任务队列，实现 consumer.Source
+type Queue struct {
+	rdb        redis.UniversalClient
+	key        string
+	delayedKey string
+	opts       Options
+}
+
+// New 创建队列，key 为任务list的key
+func New(rdb redis.UniversalClient, key string, opts Options) *Queue {
+	if len(opts.ResultPrefix) == 0 {
+		opts.ResultPrefix = DefaultResultPrefix
+	}
+	if opts.ResultTTL <= 0 {
+		opts.ResultTTL = 24 * time.Hour
+	}
+	if opts.PollTimeout <= 0 {
+		opts.PollTimeout = time.Second
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = time.Second
+	}
+	if len(opts.DeadLetterKey) == 0 {
+		opts.DeadLetterKey = key + ":dead"
+	}
+	return &Queue{rdb: rdb, key: key, delayedKey: key + ":delayed", opts: opts}
+}
+
+// message 从队列取出的任务
+// BRPOP 取出即从队列删除，Ack 无需操作；Nack 按退避时间延迟重投，超过 MaxAttempts 次移入死信队列
+type message struct {
+	q        *Queue
+	body     []byte
+	attempts int
+}
+
+func (m *message) Body() []byte { return m.body }
+
+func (m *message) Ack() error { return nil }
+
+func (m *message) Nack() error {
+	ctx := context.Background()
+	env := Envelope{Attempts: m.attempts, Job: m.body, NackedAt: time.Now().UnixNano() / int64(time.Millisecond)}
+	data, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("[redisqueue] 序列化任务失败, err: %w", err)
+	}
+
+	if m.attempts >= m.q.opts.MaxAttempts {
+		err = m.q.rdb.LPush(ctx, m.q.opts.DeadLetterKey, data).Err()
+		if err != nil {
+			return fmt.Errorf("[redisqueue] 任务移入死信队列失败, err: %w", err)
+		}
+		return nil
+	}
+
+	backoff := m.q.opts.RetryBackoff << uint(m.attempts-1)
+	if backoff <= 0 || backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	readyAt := time.Now().Add(backoff).UnixNano() / int64(time.Millisecond)
+	err = m.q.rdb.ZAdd(ctx, m.q.delayedKey, redis.Z{Score: float64(readyAt), Member: data}).Err()
+	if err != nil {
+		return fmt.Errorf("[redisqueue] 任务延迟重投失败, err: %w", err)
+	}
+	return nil
+}
+
+// promote 将到期的延迟任务放回队列；多个worker同时处理时，ZREM 成功的一方负责放回
+func (q *Queue) promote(ctx context.Context) error {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	due, err := q.rdb.ZRangeByScore(ctx, q.delayedKey, &redis.ZRangeBy{Min: "-inf", Max: strconv.FormatInt(now, 10), Count: 100}).Result()
+	if err != nil {
+		return err
+	}
+	for _, data := range due {
+		removed, err := q.rdb.ZRem(ctx, q.delayedKey, data).Result()
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			continue
+		}
+		err = q.rdb.LPush(ctx, q.key, data).Err()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newMessage 解析队列中的元素，重投的任务为 Envelope，生产方投递的为 consumer.PushJob
+func (q *Queue) newMessage(data string) *message {
+	var env Envelope
+	if json.Unmarshal([]byte(data), &env) == nil && len(env.Job) > 0 {
+		return &message{q: q, body: env.Job, attempts: env.Attempts + 1}
+	}
+	return &message{q: q, body: []byte(data), attempts: 1}
+}
+
+// Receive 实现 consumer.Source，阻塞读取下一个任务
+func (q *Queue) Receive(ctx context.Context) (consumer.Message, error) {
+	for {
+		err := q.promote(ctx)
+		if err != nil && ctx.Err() == nil {
+			return nil, fmt.Errorf("[redisqueue] 读取延迟任务失败, err: %w", err)
+		}
+
+		ret, err := q.rdb.BRPop(ctx, q.opts.PollTimeout, q.key).Result()
+		if errors.Is(err, redis.Nil) {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("[redisqueue] 读取任务失败, err: %w", err)
+		}
+		// BRPOP 返回 [key, value]
+		return q.newMessage(ret[1]), nil
+	}
+}
+
+// WriteResult 将任务结果写入 {ResultPrefix}{id}，没有id的任务不写
+func (q *Queue) WriteResult(ctx context.Context, job consumer.PushJob, ret *getui.RspBody, err error) error {
+	if len(job.ID) == 0 {
+		return nil
+	}
+
+	r := Result{ID: job.ID, Status: "ok", Time: time.Now().UnixNano() / int64(time.Millisecond)}
+	if ret != nil {
+		r.TaskID, r.Result = ret.TaskID, ret.Result
+	}
+	if err != nil {
+		r.Status, r.Error = "failed", err.Error()
+	}
+	data, _ := json.Marshal(r)
+
+	// 发送已结束，ctx 取消时结果仍需写入
+	err = q.rdb.Set(context.WithoutCancel(ctx), q.opts.ResultPrefix+job.ID, data, q.opts.ResultTTL).Err()
+	if err != nil {
+		return fmt.Errorf("[redisqueue] 写入任务 %s 的结果失败, err: %w", job.ID, err)
+	}
+	return nil
+}
+
+// ConsumerOptions 在 opts 上设置 OnResult 为写入结果，原有的 OnResult 仍会调用
+// 写入失败时通过 opts.OnError 回调
+func (q *Queue) ConsumerOptions(opts consumer.Options) consumer.Options {
+	onResult, onError := opts.OnResult, opts.OnError
+	opts.OnResult = func(ctx context.Context, job consumer.PushJob, ret *getui.RspBody, err error) {
+		werr := q.WriteResult(ctx, job, ret, err)
+		if werr != nil && onError != nil {
+			body, _ := json.Marshal(job)
+			onError(body, werr)
+		}
+		if onResult != nil {
+			onResult(ctx, job, ret, err)
+		}
+	}
+	return opts
+}
@@ -0,0 +1,102 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrCredentialsProviderSet 配置了 CredentialsProvider 时不能再通过 UpdateCredentials 修改凭证，下次鉴权时会被其覆盖
+var ErrCredentialsProviderSet = errors.New("getui: 已配置 CredentialsProvider, 请在凭证来源中轮换凭证")
+
+// Credentials 应用凭证
+type Credentials struct {
+	AppID        string `json:"app_id"`
+	AppKey       string `json:"app_key"`
+	MasterSecret string `json:"master_secret"`
+}
+
+// CredentialsProvider 应用凭证来源
+// 每次申请token前调用，凭证轮换后下次鉴权即使用新凭证；AppID 只在初始化时读取
+// Vault 实现见 vaultcreds 包
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsProviderFunc 函数形式的 CredentialsProvider
+type CredentialsProviderFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials 实现 CredentialsProvider
+func (f CredentialsProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// EnvCredentials 从环境变量 {prefix}APP_ID、{prefix}APP_KEY、{prefix}MASTER_SECRET 读取凭证
+// prefix 为空时使用 "GETUI_"
+func EnvCredentials(prefix string) CredentialsProvider {
+	if len(prefix) == 0 {
+		prefix = "GETUI_"
+	}
+	return CredentialsProviderFunc(func(ctx context.Context) (Credentials, error) {
+		creds := Credentials{
+			AppID:        os.Getenv(prefix + "APP_ID"),
+			AppKey:       os.Getenv(prefix + "APP_KEY"),
+			MasterSecret: os.Getenv(prefix + "MASTER_SECRET"),
+		}
+		if len(creds.AppKey) == 0 {
+			return creds, fmt.Errorf("[EnvCredentials] 环境变量 %sAPP_KEY 为空", prefix)
+		}
+		return creds, nil
+	})
+}
+
+// FileCredentials 从JSON文件读取凭证，格式见 Credentials 的json标签
+// 每次调用都重新读取文件，适用于由外部挂载并轮换的密钥文件
+func FileCredentials(path string) CredentialsProvider {
+	return CredentialsProviderFunc(func(ctx context.Context) (Credentials, error) {
+		var creds Credentials
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return creds, fmt.Errorf("[FileCredentials] 读取凭证文件失败, err: %w", err)
+		}
+		err = json.Unmarshal(data, &creds)
+		if err != nil {
+			return creds, fmt.Errorf("[FileCredentials] 凭证文件格式错误, err: %w", err)
+		}
+		if len(creds.AppKey) == 0 {
+			return creds, fmt.Errorf("[FileCredentials] 凭证文件中 app_key 为空")
+		}
+		return creds, nil
+	})
+}
+
+// loadCredentials 从 CredentialsProvider 读取凭证，有变化时替换当前凭证
+// 使用自定义 Signer 时只更新 AppKey
+func (c *client) loadCredentials(ctx context.Context) error {
+	if c.CredentialsProvider == nil {
+		return nil
+	}
+	creds, err := c.CredentialsProvider.Credentials(ctx)
+	if err != nil {
+		return fmt.Errorf("读取凭证失败, err: %w", err)
+	}
+
+	c.mu.RLock()
+	appKey, masterSecret, signer := c.AppKey, c.MasterSecret, c.Signer
+	c.mu.RUnlock()
+	if creds.AppKey == appKey && (!c.localSigner || creds.MasterSecret == masterSecret) {
+		return nil
+	}
+
+	if c.localSigner {
+		masterSecret = creds.MasterSecret
+		signer, err = newSigner(c.SignAlgorithm, masterSecret)
+		if err != nil {
+			return err
+		}
+	}
+	c.setCredentials(creds.AppKey, masterSecret, signer)
+	return nil
+}
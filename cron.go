@@ -0,0 +1,176 @@
+package getui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule 解析后的cron表达式
+// 支持5段 "分 时 日 月 周" 或6段 "秒 分 时 日 月 周"，每段支持 * , - /，周日可写作0或7；
+// 另支持 @hourly @daily @weekly @monthly
+type CronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	// domStar、dowStar 日与周是否为*，与标准cron一致：两者都有限定时满足其一即可
+	domStar, dowStar bool
+}
+
+// cronDescriptors 常用的预定义表达式
+var cronDescriptors = map[string]string{
+	"@hourly":  "0 0 * * * *",
+	"@daily":   "0 0 0 * * *",
+	"@weekly":  "0 0 0 * * 0",
+	"@monthly": "0 0 0 1 * *",
+}
+
+// ParseCron 解析cron表达式
+func ParseCron(spec string) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := cronDescriptors[spec]; ok {
+		spec = d
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("[ParseCron] 错误的cron表达式 %q, 应为5段或6段", spec)
+	}
+
+	bounds := []struct {
+		name     string
+		min, max int
+	}{
+		{"秒", 0, 59}, {"分", 0, 59}, {"时", 0, 23}, {"日", 1, 31}, {"月", 1, 12}, {"周", 0, 7},
+	}
+	bits := make([]uint64, len(fields))
+	for i, f := range fields {
+		b, err := parseCronField(f, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("[ParseCron] cron表达式 %q 的%s字段错误, err: %s", spec, bounds[i].name, err)
+		}
+		bits[i] = b
+	}
+
+	// 周日可写作7
+	if bits[5]&(1<<7) != 0 {
+		bits[5] = bits[5]&^(1<<7) | 1
+	}
+
+	return &CronSchedule{
+		second:  bits[0],
+		minute:  bits[1],
+		hour:    bits[2],
+		dom:     bits[3],
+		month:   bits[4],
+		dow:     bits[5],
+		domStar: fields[3] == "*",
+		dowStar: fields[5] == "*",
+	}, nil
+}
+
+// parseCronField 解析单个字段为位图
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("错误的步长 %q", part)
+			}
+			rng, step = part[:i], s
+		}
+
+		lo, hi := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			i := strings.Index(rng, "-")
+			a, err1 := strconv.Atoi(rng[:i])
+			b, err2 := strconv.Atoi(rng[i+1:])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("错误的范围 %q", part)
+			}
+			lo, hi = a, b
+		default:
+			a, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("错误的取值 %q", part)
+			}
+			lo, hi = a, a
+			if step > 1 {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q 超出范围 %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next 返回 t 之后（不含t）的下一个触发时间，按 t 的时区计算；5年内无触发时间时返回零值
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Second).Add(time.Second)
+	yearLimit := t.Year() + 5
+
+WRAP:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for s.month&(1<<uint(t.Month())) == 0 {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		if t.Day() == 1 {
+			goto WRAP
+		}
+	}
+
+	for s.hour&(1<<uint(t.Hour())) == 0 {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		if t.Hour() == 0 {
+			goto WRAP
+		}
+	}
+
+	for s.minute&(1<<uint(t.Minute())) == 0 {
+		t = t.Truncate(time.Minute).Add(time.Minute)
+		if t.Minute() == 0 {
+			goto WRAP
+		}
+	}
+
+	for s.second&(1<<uint(t.Second())) == 0 {
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto WRAP
+		}
+	}
+
+	return t
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
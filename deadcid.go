@@ -0,0 +1,134 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 失效cid的原因
+const (
+	// DeadCIDInvalid 回执 actionId 为 10003，cid无效，通常为应用已卸载
+	DeadCIDInvalid = "invalid_cid"
+	// DeadCIDIgnored 单推返回 StatusSuccessIgnore，终端长期不活跃
+	DeadCIDIgnored = StatusSuccessIgnore
+)
+
+// DeadCID 失效的cid
+type DeadCID struct {
+	CID    string    `json:"cid"`
+	Reason string    `json:"reason"`
+	TaskID string    `json:"taskid,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// DeadCIDCollector 收集失效的cid，用于从业务的用户设备表中清理
+// 配置在 InitParams.DeadCIDs 时自动收集单推返回的 StatusSuccessIgnore；
+// 将 OnReceipt 配置为 ReceiptHandlerOptions.OnReceipt 时收集cid无效的回执。
+// 同一cid只记录首次，通过 OnDeadCID 回调或 List、Drain 取出
+type DeadCIDCollector struct {
+	// OnDeadCID 首次发现失效cid时调用，在发送请求或处理回执的goroutine中同步调用，不能阻塞
+	OnDeadCID func(ctx context.Context, d DeadCID)
+
+	mu   sync.Mutex
+	dead map[string]DeadCID
+}
+
+// NewDeadCIDCollector 创建失效cid收集器，onDead 可为空
+func NewDeadCIDCollector(onDead func(ctx context.Context, d DeadCID)) *DeadCIDCollector {
+	return &DeadCIDCollector{OnDeadCID: onDead, dead: map[string]DeadCID{}}
+}
+
+// Add 记录失效的cid，已记录过时返回false
+func (c *DeadCIDCollector) Add(ctx context.Context, d DeadCID) bool {
+	if len(d.CID) == 0 {
+		return false
+	}
+	if d.At.IsZero() {
+		d.At = time.Now()
+	}
+
+	c.mu.Lock()
+	if _, ok := c.dead[d.CID]; ok {
+		c.mu.Unlock()
+		return false
+	}
+	c.dead[d.CID] = d
+	c.mu.Unlock()
+
+	if c.OnDeadCID != nil {
+		c.OnDeadCID(ctx, d)
+	}
+	return true
+}
+
+// OnReceipt 收集cid无效的回执，签名与 ReceiptHandlerOptions.OnReceipt 一致
+func (c *DeadCIDCollector) OnReceipt(ctx context.Context, r *Receipt) error {
+	if r.Type() == ReceiptInvalidCID {
+		c.Add(ctx, DeadCID{CID: r.CID, Reason: DeadCIDInvalid, TaskID: r.TaskID, At: r.Time()})
+	}
+	return nil
+}
+
+// List 已记录的失效cid，按cid排列
+func (c *DeadCIDCollector) List() []DeadCID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ret := make([]DeadCID, 0, len(c.dead))
+	for _, d := range c.dead {
+		ret = append(ret, d)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].CID < ret[j].CID })
+	return ret
+}
+
+// Drain 取出并清空已记录的失效cid，按cid排列，适合定期批量清理
+// 清空后同一cid再次失效时会重新记录
+func (c *DeadCIDCollector) Drain() []DeadCID {
+	ret := c.List()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range ret {
+		delete(c.dead, d.CID)
+	}
+	return ret
+}
+
+// LoadDeadCIDs 从 Store 读取满足条件的cid无效回执与 StatusSuccessIgnore 的单推结果，同一cid只返回首次，按cid排列
+func LoadDeadCIDs(ctx context.Context, store Store, q StoreQuery) ([]DeadCID, error) {
+	pushes, err := store.PushResults(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("[LoadDeadCIDs] 读取发送结果失败, err: %w", err)
+	}
+	receipts, err := store.Receipts(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("[LoadDeadCIDs] 读取回执失败, err: %w", err)
+	}
+
+	c := NewDeadCIDCollector(nil)
+	for _, p := range pushes {
+		if p.Status == StatusSuccessIgnore && len(p.Targets) == 1 && isCIDEndpoint(p.Endpoint) {
+			c.Add(ctx, DeadCID{CID: p.Targets[0], Reason: DeadCIDIgnored, TaskID: p.TaskID, At: p.SentAt})
+		}
+	}
+	for i := range receipts {
+		_ = c.OnReceipt(ctx, &receipts[i])
+	}
+	return c.List(), nil
+}
+
+// isCIDEndpoint 发送结果的目标可能为cid的单推接口
+func isCIDEndpoint(endpoint string) bool {
+	return endpoint == "push_single" || endpoint == "push_single_batch"
+}
+
+// observeDeadCID 单推返回 StatusSuccessIgnore 时记录cid，未配置 DeadCIDs 或按alias推送时不做任何事
+func (c *client) observeDeadCID(ctx context.Context, cid string, ret *RspBody) {
+	if c.DeadCIDs == nil || len(cid) == 0 || ret == nil || ret.Status != StatusSuccessIgnore {
+		return
+	}
+	c.DeadCIDs.Add(ctx, DeadCID{CID: cid, Reason: DeadCIDIgnored, TaskID: ret.TaskID})
+}
@@ -0,0 +1,87 @@
+package getui
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrDuplicatePush 去重窗口内相同目标、相同内容的重复推送
+var ErrDuplicatePush = errors.New("getui: 重复的推送")
+
+// dedupe 推送去重
+// 以 (目标, 内容hash) 为key，窗口期内只放行第一次；推送失败时移除记录，允许重试
+type dedupe struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastSweep time.Time
+}
+
+func newDedupe(window time.Duration) *dedupe {
+	return &dedupe{window: window, seen: map[string]time.Time{}}
+}
+
+// pushKey 计算去重key，requestid、taskid 等每次不同的字段不参与计算
+func pushKey(target interface{}, content ...interface{}) string {
+	data, _ := json.Marshal(struct {
+		Target  interface{}   `json:"target"`
+		Content []interface{} `json:"content"`
+	}{target, content})
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// acquire 登记key，窗口期内已存在时返回 ErrDuplicatePush
+func (d *dedupe) acquire(key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	d.sweep(now)
+
+	if t, ok := d.seen[key]; ok && now.Sub(t) < d.window {
+		return ErrDuplicatePush
+	}
+	d.seen[key] = now
+	return nil
+}
+
+// forget 移除key，推送失败后调用
+func (d *dedupe) forget(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.seen, key)
+}
+
+// sweep 清理过期的记录，每个窗口期最多清理一次
+func (d *dedupe) sweep(now time.Time) {
+	if now.Sub(d.lastSweep) < d.window {
+		return
+	}
+	for k, t := range d.seen {
+		if now.Sub(t) >= d.window {
+			delete(d.seen, k)
+		}
+	}
+	d.lastSweep = now
+}
+
+// checkDuplicate 未开启去重时返回空key
+func (c *client) checkDuplicate(target interface{}, content ...interface{}) (key string, err error) {
+	if c.dedupe == nil {
+		return "", nil
+	}
+	key = pushKey(target, content...)
+	return key, c.dedupe.acquire(key)
+}
+
+// releaseDuplicate 推送失败时移除去重记录
+func (c *client) releaseDuplicate(key string, err error) {
+	if len(key) > 0 && err != nil {
+		c.dedupe.forget(key)
+	}
+}
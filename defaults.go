@@ -0,0 +1,64 @@
+package getui
+
+import "time"
+
+// PushDefaults 客户端级的推送默认值，调用方只需填写标题与内容
+// 每次推送中已设置的字段优先，未设置（零值）的字段使用默认值
+type PushDefaults struct {
+	// IsOffline 是否离线存储，推送中为 false 时使用该值
+	IsOffline bool
+	// MsgType 消息类型，如 "notification"
+	MsgType string
+	// OfflineExpire 离线存储时长，不超过 MaxOfflineExpire
+	OfflineExpire time.Duration
+	// TransmissionType 收到消息是否立即启动应用，推送中为 false 时使用该值
+	TransmissionType bool
+	// Sound iOS提示音，如 "default"
+	Sound string
+	// Channel、ChannelName、ChannelLevel Android 8.0 以上的通知渠道
+	Channel      string
+	ChannelName  string
+	ChannelLevel int
+	// IsRing、IsVibrate 是否响铃、振动，为空时不发送，由个推决定
+	IsRing    *bool
+	IsVibrate *bool
+}
+
+// Bool 返回 v 的指针，便于设置 PushDefaults 与 Notification.Style 中的开关
+func Bool(v bool) *bool {
+	return &v
+}
+
+// applyDefaults 用 InitParams.Defaults 填充推送中未设置的字段，pushInfo 为空时不处理
+func (c *client) applyDefaults(m *Message, n *Notification, pushInfo *PushInfo) {
+	d := c.Defaults
+
+	if !m.IsOffline {
+		m.IsOffline = d.IsOffline
+	}
+	if len(m.MsgType) == 0 {
+		m.MsgType = d.MsgType
+	}
+	if m.OfflineExpireTime == 0 && d.OfflineExpire > 0 {
+		m.OfflineExpireTime = int64(d.OfflineExpire / time.Millisecond)
+	}
+	if !n.TransmissionType {
+		n.TransmissionType = d.TransmissionType
+	}
+
+	if len(n.Style.Channel) == 0 {
+		n.Style.Channel = d.Channel
+		n.Style.ChannelName = d.ChannelName
+		n.Style.ChannelLevel = d.ChannelLevel
+	}
+	if n.Style.IsRing == nil {
+		n.Style.IsRing = d.IsRing
+	}
+	if n.Style.IsVibrate == nil {
+		n.Style.IsVibrate = d.IsVibrate
+	}
+
+	if pushInfo != nil && len(pushInfo.Aps.Sound) == 0 {
+		pushInfo.Aps.Sound = d.Sound
+	}
+}
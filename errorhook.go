@@ -0,0 +1,47 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrorInfo 失败请求的上下文，供 OnError 转发到 Sentry、Rollbar 等
+type ErrorInfo struct {
+	// Endpoint 接口名，如 push_single
+	Endpoint string
+	Method   string
+	// Path 请求路径，如 user_status/{cid}
+	Path string
+	// RequestID ctx 中的requestid，见 WithRequestID
+	RequestID string
+	// Result 个推返回的 result，未收到个推的JSON时为空
+	Result string
+	// StatusCode 响应不是JSON时的http状态码，见 TransportError
+	StatusCode int
+	Duration   time.Duration
+	// Err 与调用方收到的错误相同（已脱敏），可用 errors.Is 判断类型
+	Err error
+}
+
+// reportError 请求失败时调用 OnError
+func (c *client) reportError(ctx context.Context, method, path string, start time.Time, result string, err error) {
+	if c.OnError == nil || err == nil {
+		return
+	}
+
+	info := ErrorInfo{
+		Endpoint:  endpoint(path),
+		Method:    method,
+		Path:      path,
+		RequestID: RequestIDFromContext(ctx),
+		Result:    result,
+		Duration:  time.Since(start),
+		Err:       err,
+	}
+	var te *TransportError
+	if errors.As(err, &te) {
+		info.StatusCode = te.StatusCode
+	}
+	c.OnError(ctx, info)
+}
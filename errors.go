@@ -0,0 +1,84 @@
+package getui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+)
+
+// 常见错误，可用 errors.Is 判断，无需匹配错误信息
+var (
+	// ErrInvalidTarget 推送目标错误，如cid与alias都为空、cid格式错误
+	ErrInvalidTarget = errors.New("getui: 错误的推送目标")
+	// ErrNoUser 用户不存在，个推返回 no_user
+	ErrNoUser = errors.New("getui: 用户不存在")
+	// ErrTokenExpired 鉴权token无效或已过期，个推返回 not_auth
+	ErrTokenExpired = errors.New("getui: 鉴权token无效或已过期")
+	// ErrRateLimited 请求或推送超过频率、数量限制，个推返回 flow_exceeded 等或http状态码429
+	ErrRateLimited = errors.New("getui: 超过频率限制")
+	// ErrTaskNotFound 任务不存在，个推返回 taskid_error 等
+	ErrTaskNotFound = errors.New("getui: 任务不存在")
+)
+
+// resultErrors 个推返回的 result 与错误的对应关系
+var resultErrors = map[string]error{
+	"no_user":            ErrNoUser,
+	"not_auth":           ErrTokenExpired,
+	"auth_error":         ErrTokenExpired,
+	"flow_exceeded":      ErrRateLimited,
+	"over_limit":         ErrRateLimited,
+	"push_num_overlimit": ErrRateLimited,
+	"taskid_error":       ErrTaskNotFound,
+	"task_not_exist":     ErrTaskNotFound,
+}
+
+// resultError 请求不成功时的错误，result 有对应的常见错误时包装该错误
+func resultError(result string, detail string) error {
+	if sentinel, ok := resultErrors[result]; ok {
+		return fmt.Errorf("请求不成功, ret: %s, err: %w", detail, sentinel)
+	}
+	return fmt.Errorf("请求不成功, ret: %s", detail)
+}
+
+// maxErrorExcerpt TransportError 中保留的body字节数
+const maxErrorExcerpt = 256
+
+// TransportError 响应不是个推的JSON，如网关返回的HTML页面或空body
+// 可用 errors.As 取出http状态码与body片段
+type TransportError struct {
+	StatusCode  int
+	ContentType string
+	// Excerpt 截断并脱敏后的body
+	Excerpt string
+}
+
+func (e *TransportError) Error() string {
+	if len(e.Excerpt) == 0 {
+		return fmt.Sprintf("getui: 响应不是JSON, http状态码 %d, body为空", e.StatusCode)
+	}
+	return fmt.Sprintf("getui: 响应不是JSON, http状态码 %d, content-type: %s, body: %q", e.StatusCode, e.ContentType, e.Excerpt)
+}
+
+// newTransportError 由响应构造 TransportError，body 超过 maxErrorExcerpt 时截断
+func newTransportError(rsp *http.Response, body string) *TransportError {
+	if len(body) > maxErrorExcerpt {
+		cut := maxErrorExcerpt
+		for cut > 0 && !utf8.RuneStart(body[cut]) {
+			cut--
+		}
+		body = body[:cut] + "..."
+	}
+	return &TransportError{
+		StatusCode:  rsp.StatusCode,
+		ContentType: rsp.Header.Get("Content-Type"),
+		Excerpt:     body,
+	}
+}
+
+// looksLikeJSON body 是否为JSON对象或数组
+func looksLikeJSON(body []byte) bool {
+	body = bytes.TrimSpace(body)
+	return len(body) > 0 && (body[0] == '{' || body[0] == '[')
+}
@@ -0,0 +1,68 @@
+package getui
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxOfflineExpire 个推允许的最长离线存储时间
+const MaxOfflineExpire = 72 * time.Hour
+
+// offlineExpireMs 校验离线时长并转换为个推要求的毫秒
+func offlineExpireMs(d time.Duration) (int64, error) {
+	if d <= 0 || d > MaxOfflineExpire {
+		return 0, fmt.Errorf("离线时长 %s 超出范围, 需在 (0, %s] 之间", d, MaxOfflineExpire)
+	}
+	return int64(d / time.Millisecond), nil
+}
+
+// validateOfflineExpire 校验请求中的离线时长，单位为毫秒，0 表示未设置
+func validateOfflineExpire(ms int64) error {
+	if ms == 0 {
+		return nil
+	}
+	// 以毫秒比较，换算为 time.Duration 时较大的值会溢出为负数
+	maxMs := int64(MaxOfflineExpire / time.Millisecond)
+	if ms < 0 || ms > maxMs {
+		return fmt.Errorf("offline_expire_time %dms 超出范围, 需在 (0, %d] 毫秒之间", ms, maxMs)
+	}
+	return nil
+}
+
+// 各类推送body的 SetTTL 设置离线存储时长
+// 命名为 Set 而不是 With：clone.go 中的 With 方法返回修改后的副本，SetTTL 需要校验时长并返回错误，直接修改 body
+
+// SetTTL 设置离线存储时长，同时开启离线存储，d 需在 (0, MaxOfflineExpire] 之间
+func (b *SingleReqBody) SetTTL(d time.Duration) error {
+	ms, err := offlineExpireMs(d)
+	if err != nil {
+		return fmt.Errorf("[SetTTL] %w", err)
+	}
+	b.Message.IsOffline = true
+	b.Message.OfflineExpireTime = ms
+	return nil
+}
+
+// SetTTL 设置离线存储时长，同时开启离线存储，d 需在 (0, MaxOfflineExpire] 之间
+// 消息共同体与 push_list 中的 message 两处离线时长一并设置，不会出现不一致
+func (b *ListReqBody) SetTTL(d time.Duration) error {
+	ms, err := offlineExpireMs(d)
+	if err != nil {
+		return fmt.Errorf("[SetTTL] %w", err)
+	}
+	b.Message.IsOffline = true
+	b.Message.OfflineExpireTime = ms
+	b.OfflineExpireTime = ms
+	return nil
+}
+
+// SetTTL 设置离线存储时长，同时开启离线存储，d 需在 (0, MaxOfflineExpire] 之间
+func (b *AppReqBody) SetTTL(d time.Duration) error {
+	ms, err := offlineExpireMs(d)
+	if err != nil {
+		return fmt.Errorf("[SetTTL] %w", err)
+	}
+	b.Message.IsOffline = true
+	b.Message.OfflineExpireTime = ms
+	return nil
+}
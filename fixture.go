@@ -0,0 +1,277 @@
+package getui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// redactedValue 脱敏后的占位值
+const redactedValue = "[REDACTED]"
+
+// redactedHeaders 录制时需要脱敏的请求头
+var redactedHeaders = []string{AuthHeaderV1, AuthHeaderV2}
+
+// redactedFields 录制时需要脱敏的JSON字段
+// 签名、鉴权token属于敏感信息；时间戳与requestid每次请求都不同，回放时不参与比对
+var redactedFields = map[string]bool{
+	"sign":       true,
+	"auth_token": true,
+	"timestamp":  true,
+	"requestid":  true,
+}
+
+// Interaction 一次录制的请求/响应
+type Interaction struct {
+	Method         string            `json:"method"`
+	Path           string            `json:"path"`
+	RequestHeader  map[string]string `json:"request_header,omitempty"`
+	RequestBody    string            `json:"request_body,omitempty"`
+	Status         int               `json:"status"`
+	ResponseHeader map[string]string `json:"response_header,omitempty"`
+	ResponseBody   string            `json:"response_body,omitempty"`
+}
+
+// Recorder 录制请求的RoundTripper
+// 将真实的请求/响应（已脱敏）保存到golden文件，供 Replayer 回放
+type Recorder struct {
+	// AuthHeader 自定义的鉴权请求头名（见 InitParams.AuthHeader），录制时与默认的请求头一样脱敏
+	AuthHeader string
+
+	file string
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder 创建录制器，next 为空时使用 http.DefaultTransport
+func NewRecorder(file string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{file: file, next: next}
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("[Recorder] 读取请求body失败, err: %w", err)
+	}
+
+	rsp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	rspBody, err := drainBody(&rsp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("[Recorder] 读取返回body失败, err: %w", err)
+	}
+
+	it := Interaction{
+		Method:         req.Method,
+		Path:           req.URL.Path,
+		RequestHeader:  redactHeader(req.Header, r.AuthHeader),
+		RequestBody:    redactBody(reqBody),
+		Status:         rsp.StatusCode,
+		ResponseHeader: map[string]string{"Content-Type": rsp.Header.Get("Content-Type")},
+		ResponseBody:   redactBody(rspBody),
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, it)
+	r.mu.Unlock()
+
+	return rsp, nil
+}
+
+// Interactions 已录制的请求/响应
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save 写入golden文件
+func (r *Recorder) Save() error {
+	data, err := json.MarshalIndent(r.Interactions(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("[Recorder] 序列化录制内容失败, err: %w", err)
+	}
+	err = os.WriteFile(r.file, append(data, '\n'), 0644)
+	if err != nil {
+		return fmt.Errorf("[Recorder] 写入golden文件失败, err: %w", err)
+	}
+	return nil
+}
+
+// Replayer 回放golden文件的RoundTripper
+// 按录制顺序逐条比对请求（方法、路径、脱敏后的body），一致时返回录制的响应
+type Replayer struct {
+	// AuthHeader 自定义的鉴权请求头名，需与录制时 Recorder.AuthHeader 一致
+	AuthHeader string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	pos          int
+}
+
+// NewReplayer 从golden文件创建回放器
+func NewReplayer(file string) (*Replayer, error) {
+	interactions, err := ReadInteractions(file)
+	if err != nil {
+		return nil, fmt.Errorf("[NewReplayer] %w", err)
+	}
+	return &Replayer{interactions: interactions}, nil
+}
+
+// ReadInteractions 读取 Recorder 保存的golden文件
+func ReadInteractions(file string) ([]Interaction, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("读取golden文件失败, err: %w", err)
+	}
+
+	var interactions []Interaction
+	err = json.Unmarshal(data, &interactions)
+	if err != nil {
+		return nil, fmt.Errorf("golden文件的JSON无法解析, err: %w", err)
+	}
+	return interactions, nil
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("[Replayer] 读取请求body失败, err: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pos >= len(r.interactions) {
+		return nil, fmt.Errorf("[Replayer] 没有更多录制的请求, 收到: %s %s", req.Method, req.URL.Path)
+	}
+	it := r.interactions[r.pos]
+
+	if it.Method != req.Method || it.Path != req.URL.Path {
+		return nil, fmt.Errorf("[Replayer] 第%d个请求不一致, 期望: %s %s, 收到: %s %s", r.pos, it.Method, it.Path, req.Method, req.URL.Path)
+	}
+	for k, v := range it.RequestHeader {
+		if got := redactHeader(req.Header, r.AuthHeader)[k]; got != v {
+			return nil, fmt.Errorf("[Replayer] 第%d个请求的header %s 不一致, 期望: %s, 收到: %s", r.pos, k, v, got)
+		}
+	}
+	if got := redactBody(reqBody); !jsonEqual(it.RequestBody, got) {
+		return nil, fmt.Errorf("[Replayer] 第%d个请求的body不一致, 期望: %s, 收到: %s", r.pos, it.RequestBody, got)
+	}
+	r.pos++
+
+	rsp := &http.Response{
+		StatusCode:    it.Status,
+		Status:        fmt.Sprintf("%d %s", it.Status, http.StatusText(it.Status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader([]byte(it.ResponseBody))),
+		ContentLength: int64(len(it.ResponseBody)),
+		Request:       req,
+	}
+	for k, v := range it.ResponseHeader {
+		rsp.Header.Set(k, v)
+	}
+	return rsp, nil
+}
+
+// Remaining 尚未被回放的请求数，测试结束时应为0
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.interactions) - r.pos
+}
+
+// drainBody 读出body并替换为可重复读取的副本
+func drainBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// redactHeader 取出需要录制的请求头并脱敏，authHeader 为自定义的鉴权请求头名，可为空
+func redactHeader(h http.Header, authHeader string) map[string]string {
+	ret := map[string]string{}
+	if ct := h.Get("Content-Type"); len(ct) > 0 {
+		ret["Content-Type"] = ct
+	}
+	for _, k := range append(redactedHeaders[:len(redactedHeaders):len(redactedHeaders)], authHeader) {
+		if len(k) > 0 && len(h[k]) > 0 {
+			ret[k] = redactedValue
+		}
+	}
+	return ret
+}
+
+// redactBody 对JSON body中的敏感字段脱敏，非JSON原样返回
+func redactBody(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return string(data)
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return string(data)
+	}
+	return string(out)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range val {
+			if redactedFields[k] {
+				val[k] = redactedValue
+				continue
+			}
+			val[k] = redactValue(sub)
+		}
+	case []interface{}:
+		for i, sub := range val {
+			val[i] = redactValue(sub)
+		}
+	}
+	return v
+}
+
+// jsonEqual 比较两段body，JSON按语义比较
+func jsonEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var va, vb interface{}
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return bytes.Equal(ja, jb)
+}
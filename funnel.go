@@ -0,0 +1,114 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// FunnelStats 发送→到达→点击 漏斗统计
+type FunnelStats struct {
+	// Key 分组，按任务统计时为taskid，按天统计时为 2006-01-02
+	Key string `json:"key"`
+	// Sent 发送的目标数
+	Sent int64 `json:"sent"`
+	// Delivered 到达的终端数，同一任务同一cid只计一次
+	Delivered int64 `json:"delivered"`
+	// Clicked 点击的终端数，同一任务同一cid只计一次
+	Clicked int64 `json:"clicked"`
+	// Invalid cid无效的终端数
+	Invalid int64 `json:"invalid"`
+	// DeliveryRate 到达率 Delivered/Sent
+	DeliveryRate float64 `json:"delivery_rate"`
+	// ClickRate 点击率 Clicked/Delivered
+	ClickRate float64 `json:"click_rate"`
+}
+
+// FunnelByTask 按任务统计漏斗
+func FunnelByTask(pushes []PushRecord, receipts []Receipt) []FunnelStats {
+	return FunnelBy(pushes, receipts, func(p PushRecord) string { return p.TaskID })
+}
+
+// FunnelByDay 按发送日期统计漏斗，回执计入其任务的发送日期；loc 为空时使用 GetuiLocation
+func FunnelByDay(pushes []PushRecord, receipts []Receipt, loc *time.Location) []FunnelStats {
+	if loc == nil {
+		loc = GetuiLocation
+	}
+	return FunnelBy(pushes, receipts, func(p PushRecord) string { return p.SentAt.In(loc).Format("2006-01-02") })
+}
+
+// FunnelBy 按自定义分组统计漏斗，如按业务分组、按推送接口
+// 回执通过taskid归入对应发送结果的分组，找不到发送结果的回执不计入；结果按 Key 升序
+func FunnelBy(pushes []PushRecord, receipts []Receipt, key func(PushRecord) string) []FunnelStats {
+
+	stats := map[string]*FunnelStats{}
+	taskKeys := map[string]string{}
+	for _, p := range pushes {
+		k := key(p)
+		s, ok := stats[k]
+		if !ok {
+			s = &FunnelStats{Key: k}
+			stats[k] = s
+		}
+		s.Sent += int64(p.TargetCount)
+		taskKeys[p.TaskID] = k
+	}
+
+	type seenKey struct {
+		taskID, cid string
+		typ         ReceiptType
+	}
+	seen := map[seenKey]bool{}
+	for _, r := range receipts {
+		k, ok := taskKeys[r.TaskID]
+		if !ok {
+			continue
+		}
+		typ := r.Type()
+		sk := seenKey{r.TaskID, r.CID, typ}
+		if seen[sk] {
+			continue
+		}
+		seen[sk] = true
+
+		switch typ {
+		case ReceiptArrived:
+			stats[k].Delivered++
+		case ReceiptClicked:
+			stats[k].Clicked++
+		case ReceiptInvalidCID:
+			stats[k].Invalid++
+		}
+	}
+
+	ret := make([]FunnelStats, 0, len(stats))
+	for _, s := range stats {
+		if s.Sent > 0 {
+			s.DeliveryRate = float64(s.Delivered) / float64(s.Sent)
+		}
+		if s.Delivered > 0 {
+			s.ClickRate = float64(s.Clicked) / float64(s.Delivered)
+		}
+		ret = append(ret, *s)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Key < ret[j].Key })
+	return ret
+}
+
+// LoadFunnel 从 Store 读取满足条件的发送结果及其回执并统计漏斗
+func LoadFunnel(ctx context.Context, store Store, q StoreQuery, key func(PushRecord) string) ([]FunnelStats, error) {
+
+	pushes, err := store.PushResults(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("[LoadFunnel] 读取发送结果失败, err: %w", err)
+	}
+
+	// 回执总在发送之后，只按任务与起始时间过滤
+	receipts, err := store.Receipts(ctx, StoreQuery{TaskID: q.TaskID, Since: q.Since})
+	if err != nil {
+		return nil, fmt.Errorf("[LoadFunnel] 读取回执失败, err: %w", err)
+	}
+
+	return FunnelBy(pushes, receipts, key), nil
+}
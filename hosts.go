@@ -0,0 +1,81 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultHostCooldown 地址连接失败后暂停使用的时长
+const defaultHostCooldown = 30 * time.Second
+
+// hostPool 多个接口地址，连接失败的地址在冷却期内排到最后
+type hostPool struct {
+	mu        sync.Mutex
+	urls      []string
+	downUntil []time.Time
+	cooldown  time.Duration
+}
+
+// newHostPool 校验并规范化接口地址，为空时使用 apiBaseURL
+func newHostPool(urls []string, cooldown time.Duration) (*hostPool, error) {
+	if len(urls) == 0 {
+		urls = []string{apiBaseURL}
+	}
+	if cooldown <= 0 {
+		cooldown = defaultHostCooldown
+	}
+
+	p := &hostPool{cooldown: cooldown, downUntil: make([]time.Time, len(urls))}
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || len(u.Host) == 0 {
+			return nil, fmt.Errorf("错误的接口地址 %q, 需为 https://host/v1/ 形式", raw)
+		}
+		if !strings.HasSuffix(raw, "/") {
+			raw += "/"
+		}
+		p.urls = append(p.urls, raw)
+	}
+	return p, nil
+}
+
+// order 本次请求尝试地址的顺序，可用的地址按配置顺序在前，冷却中的在后
+func (p *hostPool) order(now time.Time) []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ret := make([]int, 0, len(p.urls))
+	var down []int
+	for i := range p.urls {
+		if now.Before(p.downUntil[i]) {
+			down = append(down, i)
+			continue
+		}
+		ret = append(ret, i)
+	}
+	return append(ret, down...)
+}
+
+// markDown 地址连接失败，冷却期内不优先使用
+func (p *hostPool) markDown(i int, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.downUntil[i] = now.Add(p.cooldown)
+}
+
+// markUp 地址恢复
+func (p *hostPool) markUp(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.downUntil[i] = time.Time{}
+}
+
+// failover 连接失败时是否换下一个地址
+// ctx 取消或超时不是地址的问题，不切换
+func failover(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() == nil
+}
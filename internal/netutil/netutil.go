@@ -0,0 +1,17 @@
+// Package netutil cmd 下各命令共用的网络工具
+package netutil
+
+import "net"
+
+// IsLoopback 监听地址是否只限本机
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,53 @@
+// regiongen 由 regions.csv 生成 region_data.go
+// 在仓库根目录执行 go generate 即可，更新地区编码时只需修改 regions.csv
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	src := filepath.Join("internal", "regiongen", "regions.csv")
+	dst := "region_data.go"
+
+	f, err := os.Open(src)
+	if err != nil {
+		log.Fatalf("打开 %s 失败, err: %s", src, err)
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		log.Fatalf("解析 %s 失败, err: %s", src, err)
+	}
+	if len(rows) < 2 {
+		log.Fatalf("%s 没有数据", src)
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by internal/regiongen from regions.csv. DO NOT EDIT.\n\n")
+	buf.WriteString("package getui\n\n")
+	buf.WriteString("// regions 个推地区编码表\n")
+	buf.WriteString("var regions = []Region{\n")
+	for i, row := range rows[1:] {
+		if len(row) != 3 || len(row[0]) != 8 {
+			log.Fatalf("%s 第%d行格式错误: %v", src, i+2, row)
+		}
+		fmt.Fprintf(buf, "\t{Code: %q, Province: %q, City: %q},\n", row[0], row[1], row[2])
+	}
+	buf.WriteString("}\n")
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("格式化生成的代码失败, err: %s", err)
+	}
+	if err = os.WriteFile(dst, out, 0644); err != nil {
+		log.Fatalf("写入 %s 失败, err: %s", dst, err)
+	}
+}
@@ -0,0 +1,113 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// defaultPageSize 分页查询的默认每页条数
+const defaultPageSize = 50
+
+// Iterator 分页结果的迭代器，自动获取后续页
+//
+//	it := client.ScheduledTasks(100)
+//	for it.Next() {
+//		task := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator[T any] struct {
+	fetch func(page int) (items []T, more bool, err error)
+
+	page int
+	buf  []T
+	cur  T
+	more bool
+	err  error
+}
+
+// newIterator 创建迭代器，fetch 获取第page页（从1开始），more 表示是否还有下一页
+func newIterator[T any](fetch func(page int) ([]T, bool, error)) *Iterator[T] {
+	return &Iterator[T]{fetch: fetch, more: true}
+}
+
+// Next 移动到下一条，没有更多数据或出错时返回false
+func (it *Iterator[T]) Next() bool {
+	for len(it.buf) == 0 {
+		if !it.more || it.err != nil {
+			return false
+		}
+		it.page++
+		it.buf, it.more, it.err = it.fetch(it.page)
+		if it.err != nil {
+			return false
+		}
+	}
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Value 当前条目
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+// Err 迭代过程中的错误
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// TaskInfo 任务信息
+type TaskInfo struct {
+	TaskID   string `json:"taskid"`
+	TaskName string `json:"task_name,omitempty"`
+	// PushTime 定时任务的下发时间，北京时间 yyyyMMddHHmm
+	PushTime string `json:"push_time,omitempty"`
+	// CreateTime 创建的毫秒时间戳
+	CreateTime int64  `json:"create_time"`
+	Status     string `json:"status"`
+}
+
+// taskPageRsp 任务列表 rsp body
+type taskPageRsp struct {
+	Result string     `json:"result"`
+	Total  int        `json:"total"`
+	Data   []TaskInfo `json:"data"`
+}
+
+func (r *taskPageRsp) result() string {
+	return r.Result
+}
+
+// ScheduledTasks 遍历尚未下发的定时任务，pageSize <=0 时为50
+func (c *client) ScheduledTasks(pageSize int) *Iterator[TaskInfo] {
+	return c.taskIterator("ScheduledTasks", url.Values{"type": {"schedule"}}, pageSize)
+}
+
+// HistoryTasks 遍历 [since, until) 内创建的历史任务，pageSize <=0 时为50
+func (c *client) HistoryTasks(since, until time.Time, pageSize int) *Iterator[TaskInfo] {
+	q := url.Values{"type": {"history"}}
+	q.Set("begin", strconv.FormatInt(since.UnixNano()/int64(time.Millisecond), 10))
+	q.Set("end", strconv.FormatInt(until.UnixNano()/int64(time.Millisecond), 10))
+	return c.taskIterator("HistoryTasks", q, pageSize)
+}
+
+func (c *client) taskIterator(name string, q url.Values, pageSize int) *Iterator[TaskInfo] {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	q.Set("size", strconv.Itoa(pageSize))
+
+	return newIterator(func(page int) ([]TaskInfo, bool, error) {
+		q.Set("page", strconv.Itoa(page))
+		ret, err := doRequest[taskPageRsp](context.Background(), c, "GET", "task_list?"+q.Encode(), nil)
+		if err != nil {
+			return nil, false, fmt.Errorf("[%s] 查询第%d页任务 失败, err: %w", name, page, err)
+		}
+		more := len(ret.Data) == pageSize && page*pageSize < ret.Total
+		return ret.Data, more, nil
+	})
+}
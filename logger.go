@@ -0,0 +1,59 @@
+package getui
+
+import "context"
+
+// LogLevel 日志级别
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+// String 级别名
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+// LogField 结构化日志字段
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger 客户端日志，可对接 slog、zap 等；实现需要并发安全
+// ctx 中带有 requestid（见 WithRequestID）时，字段中包含 requestid
+type Logger interface {
+	Log(ctx context.Context, level LogLevel, msg string, fields ...LogField)
+}
+
+// log 输出日志，未配置 Logger 时不做任何事；日志内容中的密钥与token会被脱敏
+func (c *client) log(ctx context.Context, level LogLevel, msg string, fields ...LogField) {
+	if c.Logger == nil {
+		return
+	}
+	if id := RequestIDFromContext(ctx); len(id) > 0 && !hasField(fields, "requestid") {
+		fields = append(fields, LogField{"requestid", id})
+	}
+	c.Logger.Log(ctx, level, c.redact(msg), c.redactFields(fields)...)
+}
+
+func hasField(fields []LogField, key string) bool {
+	for _, f := range fields {
+		if f.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// AppConfig 多应用管理中单个应用的配置
+type AppConfig struct {
+	InitParams
+	// QPS 该应用每秒请求数上限 默认0不限制
+	QPS float64
+	// Burst 限速允许的突发请求数 默认1
+	Burst int
+	// MaxConcurrency 该应用同时进行的请求数上限 默认0不限制
+	MaxConcurrency int
+}
+
+// Manager 多应用管理
+// 每个应用使用独立的客户端、token与配额，一个应用的大量推送不会占用其它应用的配额
+type Manager struct {
+	mu   sync.RWMutex
+	apps map[string]*client
+}
+
+// NewManager 创建多应用管理
+func NewManager() *Manager {
+	return &Manager{apps: map[string]*client{}}
+}
+
+// Add 添加应用并完成鉴权，name 为业务侧的应用名
+func (m *Manager) Add(name string, cfg AppConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.apps[name]; ok {
+		return fmt.Errorf("[Manager.Add] 应用 %s 已存在", name)
+	}
+
+	c, err := newClient(cfg.InitParams)
+	if err != nil {
+		return fmt.Errorf("[Manager.Add] 应用 %s 初始化失败, err: %w", name, err)
+	}
+	if cfg.QPS > 0 {
+		c.limiter = newRateLimiter(cfg.QPS, cfg.Burst)
+	}
+	if cfg.MaxConcurrency > 0 {
+		c.concurrency = make(chan struct{}, cfg.MaxConcurrency)
+	}
+
+	m.apps[name] = c
+	return nil
+}
+
+// Get 获取应用的客户端
+func (m *Manager) Get(name string) (Client, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	c, ok := m.apps[name]
+	if !ok {
+		return nil, fmt.Errorf("[Manager.Get] 应用 %s 不存在", name)
+	}
+	return c, nil
+}
+
+// Names 已添加的应用名
+func (m *Manager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.apps))
+	for name := range m.apps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Shutdown 关闭所有应用的客户端
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var firstErr error
+	for name, c := range m.apps {
+		err := c.Shutdown(ctx)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("[Manager.Shutdown] 应用 %s 关闭失败, err: %w", name, err)
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,54 @@
+package getui
+
+import "time"
+
+// Metrics 指标上报，可对接 Prometheus、StatsD 等
+// name 为指标名，labels 为指标的标签，实现需要并发安全
+type Metrics interface {
+	// SetGauge 设置仪表值
+	SetGauge(name string, value float64, labels map[string]string)
+	// AddCounter 计数器增加 delta
+	AddCounter(name string, delta float64, labels map[string]string)
+}
+
+// HistogramMetrics 支持直方图的 Metrics，配置为 InitParams.Metrics 时上报各接口的耗时
+type HistogramMetrics interface {
+	Metrics
+	// ObserveHistogram 记录一次观测值
+	ObserveHistogram(name string, value float64, labels map[string]string)
+}
+
+// 指标名
+const (
+	// MetricTaskSent 任务已发送的终端数 gauge，标签 taskid
+	MetricTaskSent = "getui_task_sent"
+	// MetricTaskDelivered 任务已到达的终端数 gauge，标签 taskid
+	MetricTaskDelivered = "getui_task_delivered"
+	// MetricTaskClicked 任务的点击数 gauge，标签 taskid
+	MetricTaskClicked = "getui_task_clicked"
+	// MetricTaskDeliveryRate 任务到达率 gauge，标签 taskid
+	MetricTaskDeliveryRate = "getui_task_delivery_rate"
+	// MetricReportPolls 查询推送结果的次数 counter，标签 result 为 ok 或 error
+	MetricReportPolls = "getui_report_polls_total"
+	// MetricRequestDuration 请求耗时（秒，含重试） histogram，标签 endpoint 为接口名（如 auth_sign、push_single、save_list_body），result 为 ok 或 error
+	// Metrics 未实现 HistogramMetrics 时不上报
+	MetricRequestDuration = "getui_request_duration_seconds"
+	// MetricRequests 请求次数 counter，标签同 MetricRequestDuration
+	MetricRequests = "getui_requests_total"
+)
+
+// observeRequest 上报请求次数与耗时，未配置 Metrics 时不做任何事
+func (c *client) observeRequest(path string, start time.Time, err error) {
+	if c.Metrics == nil {
+		return
+	}
+
+	labels := map[string]string{"endpoint": endpoint(path), "result": "ok"}
+	if err != nil {
+		labels["result"] = "error"
+	}
+	c.Metrics.AddCounter(MetricRequests, 1, labels)
+	if h, ok := c.Metrics.(HistogramMetrics); ok {
+		h.ObserveHistogram(MetricRequestDuration, time.Since(start).Seconds(), labels)
+	}
+}
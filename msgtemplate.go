@@ -0,0 +1,140 @@
+package getui
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// MissingKeyPolicy 模板引用了数据中不存在的变量时的处理方式
+type MissingKeyPolicy int
+
+const (
+	// MissingKeyError 返回错误，不发送
+	MissingKeyError MissingKeyPolicy = iota
+	// MissingKeyEmpty 输出空字符串
+	MissingKeyEmpty
+)
+
+// MessageTemplateSource 消息文案的模板，使用 text/template 语法，如 "你好 {{.name}}，订单 {{.id}} 已发货"
+// 可放在配置中由运营修改，无需改代码；为空的字段渲染时不修改消息
+type MessageTemplateSource struct {
+	Title        string `json:"title"`
+	Text         string `json:"text"`
+	Transmission string `json:"transmission"`
+}
+
+// RenderedMessage 渲染后的文案
+type RenderedMessage struct {
+	Title        string
+	Text         string
+	Transmission string
+}
+
+// MessageTemplate 解析后的消息模板，可并发使用
+type MessageTemplate struct {
+	title, text, transmission *template.Template
+}
+
+// templateFuncs 模板可用的函数，只做字符串处理，不能访问文件、环境变量等
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	// truncate 按字符截断，超出时以 "…" 结尾，如 {{truncate 20 .title}}
+	"truncate": func(n int, s string) string {
+		if n <= 0 || utf8.RuneCountInString(s) <= n {
+			return s
+		}
+		return string([]rune(s)[:n-1]) + "…"
+	},
+	// default 值为空时使用默认值，如 {{default "朋友" .name}}
+	"default": func(def, s string) string {
+		if len(s) == 0 {
+			return def
+		}
+		return s
+	},
+}
+
+// ParseMessageTemplate 解析消息模板，语法错误在此时返回，而不是发送时
+func ParseMessageTemplate(src MessageTemplateSource, missingKey MissingKeyPolicy) (*MessageTemplate, error) {
+	option := "missingkey=error"
+	if missingKey == MissingKeyEmpty {
+		option = "missingkey=zero"
+	}
+
+	parse := func(name, s string) (*template.Template, error) {
+		if len(s) == 0 {
+			return nil, nil
+		}
+		t, err := template.New(name).Option(option).Funcs(templateFuncs).Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("[ParseMessageTemplate] %s 模板错误, err: %w", name, err)
+		}
+		return t, nil
+	}
+
+	var t MessageTemplate
+	var err error
+	if t.title, err = parse("title", src.Title); err != nil {
+		return nil, err
+	}
+	if t.text, err = parse("text", src.Text); err != nil {
+		return nil, err
+	}
+	if t.transmission, err = parse("transmission", src.Transmission); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Render 用 data 渲染文案
+func (t *MessageTemplate) Render(data map[string]string) (RenderedMessage, error) {
+	var ret RenderedMessage
+	for _, f := range []struct {
+		tpl *template.Template
+		out *string
+	}{
+		{t.title, &ret.Title},
+		{t.text, &ret.Text},
+		{t.transmission, &ret.Transmission},
+	} {
+		if f.tpl == nil {
+			continue
+		}
+		b := strings.Builder{}
+		err := f.tpl.Execute(&b, data)
+		if err != nil {
+			return RenderedMessage{}, fmt.Errorf("[MessageTemplate] 渲染 %s 失败, err: %w", f.tpl.Name(), err)
+		}
+		*f.out = b.String()
+	}
+	return ret, nil
+}
+
+// Apply 用 data 渲染文案并写入通知与iOS提示，模板中为空的字段不修改
+// pushInfo 可为空
+func (t *MessageTemplate) Apply(notification *Notification, pushInfo *PushInfo, data map[string]string) error {
+	m, err := t.Render(data)
+	if err != nil {
+		return err
+	}
+	if t.title != nil {
+		notification.Style.Title = m.Title
+		if pushInfo != nil {
+			pushInfo.Aps.Alert.Title = m.Title
+		}
+	}
+	if t.text != nil {
+		notification.Style.Text = m.Text
+		if pushInfo != nil {
+			pushInfo.Aps.Alert.Body = m.Text
+		}
+	}
+	if t.transmission != nil {
+		notification.TransmissionContent = m.Transmission
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package getui
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// PayloadSerializer 透传内容的序列化方式，结果作为 transmission_content 发送，见 Client.EncodePayload
+type PayloadSerializer interface {
+	SerializePayload(v interface{}) (string, error)
+}
+
+// PayloadSerializerFunc 函数形式的 PayloadSerializer
+type PayloadSerializerFunc func(v interface{}) (string, error)
+
+// SerializePayload 实现 PayloadSerializer
+func (f PayloadSerializerFunc) SerializePayload(v interface{}) (string, error) {
+	return f(v)
+}
+
+// JSONPayload 默认的 PayloadSerializer，string 与 []byte 原样使用，其余按JSON序列化
+var JSONPayload PayloadSerializer = PayloadSerializerFunc(func(v interface{}) (string, error) {
+	switch p := v.(type) {
+	case string:
+		return p, nil
+	case []byte:
+		return string(p), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+})
+
+// Base64Payload 以 marshal 序列化后做标准base64编码，适用于 protobuf、msgpack 等二进制格式
+// 如 msgpack：Base64Payload(msgpack.Marshal)；protobuf 见 payloadpb 包
+func Base64Payload(marshal func(v interface{}) ([]byte, error)) PayloadSerializer {
+	return PayloadSerializerFunc(func(v interface{}) (string, error) {
+		data, err := marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	})
+}
+
+// EncodePayload 用 InitParams.PayloadSerializer 序列化透传内容，结果可用于 WithTransmission
+func (c *client) EncodePayload(v interface{}) (string, error) {
+	content, err := c.PayloadSerializer.SerializePayload(v)
+	if err != nil {
+		return "", fmt.Errorf("[EncodePayload] 透传内容序列化失败, err: %w", err)
+	}
+	return content, nil
+}
@@ -0,0 +1,33 @@
+// Package payloadpb 以 protobuf+base64 序列化透传内容的 getui.PayloadSerializer
+// 终端收到透传内容后先做base64解码，再按同一 proto 定义解析
+package payloadpb
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/printfcoder/getui"
+	"google.golang.org/protobuf/proto"
+)
+
+// Serializer 透传内容须为 proto.Message
+var Serializer = getui.Base64Payload(func(v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("[payloadpb] 透传内容 %T 不是 proto.Message", v)
+	}
+	return proto.Marshal(m)
+})
+
+// Decode 解析 Serializer 生成的透传内容，供服务端测试或Go编写的终端使用
+func Decode(content string, m proto.Message) error {
+	data, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return fmt.Errorf("[payloadpb.Decode] 透传内容不是base64, err: %w", err)
+	}
+	err = proto.Unmarshal(data, m)
+	if err != nil {
+		return fmt.Errorf("[payloadpb.Decode] 透传内容无法解析, err: %w", err)
+	}
+	return nil
+}
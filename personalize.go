@@ -0,0 +1,176 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// maxSingleBatch push_single_batch 单次请求的消息数上限
+const maxSingleBatch = 200
+
+// Personalization 个性化推送，每个cid使用自己的变量渲染 Message，写入 Template 的通知与iOS提示
+// 如 ParseMessageTemplate(MessageTemplateSource{Text: "你好 {{.name}}，你的订单 {{.id}} 已发货"}, MissingKeyError)
+type Personalization struct {
+	// Template 消息模板，CID、Alias 不需要填写
+	Template SingleReqBody
+	// Message 文案模板，必填，由 ParseMessageTemplate 解析
+	Message *MessageTemplate
+	// Vars cid 到变量的映射
+	Vars map[string]map[string]string
+}
+
+// Render 为每个cid渲染单推body，按cid排序
+func (p Personalization) Render() ([]SingleReqBody, error) {
+	if p.Message == nil {
+		return nil, fmt.Errorf("[Personalization] 文案模板 Message 不能为空")
+	}
+
+	cids := make([]string, 0, len(p.Vars))
+	for cid := range p.Vars {
+		cids = append(cids, cid)
+	}
+	sort.Strings(cids)
+
+	bodies := make([]SingleReqBody, 0, len(cids))
+	for _, cid := range cids {
+		body, err := p.render(cid)
+		if err != nil {
+			return nil, fmt.Errorf("[Personalization] cid: %s, err: %w", cid, err)
+		}
+		bodies = append(bodies, body)
+	}
+	return bodies, nil
+}
+
+func (p Personalization) render(cid string) (body SingleReqBody, err error) {
+	vars := p.Vars[cid]
+	body = p.Template
+	body.CID = cid
+	body.Alias = ""
+
+	err = p.Message.Apply(&body.Notification, &body.PushInfo, vars)
+	if err != nil {
+		return SingleReqBody{}, err
+	}
+	return body, nil
+}
+
+// singleBatchBody push_single_batch 请求body
+type singleBatchBody struct {
+	MsgList    []SingleReqBody `json:"msg_list"`
+	NeedDetail bool            `json:"need_detail"`
+}
+
+// singleBatchRsp push_single_batch 返回结构，TaskID 以requestid为key
+type singleBatchRsp struct {
+	Result string             `json:"result"`
+	TaskID map[string]RspBody `json:"taskid"`
+}
+
+func (r *singleBatchRsp) result() string {
+	return r.Result
+}
+
+// PushPersonalized 渲染个性化消息，按每批200条通过 push_single_batch 发送
+// 模板未指定 RequestID 时，各条消息的requestid为 ctx中的（或生成的）requestid 加序号；
+// 任一cid渲染失败时不发送任何消息；ctx 取消后不再发送剩余批次
+func (c *client) PushPersonalized(ctx context.Context, p Personalization) (*ChunkedResult, error) {
+
+	c, err := c.forApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[PushPersonalized] %w", err)
+	}
+
+	if len(p.Vars) == 0 {
+		return nil, fmt.Errorf("[PushPersonalized] 错误的目标, 没有收件人, err: %w", ErrInvalidTarget)
+	}
+	bodies, err := p.Render()
+	if err != nil {
+		return nil, fmt.Errorf("[PushPersonalized] %w", err)
+	}
+
+	// 与单推一样填充默认值并校验，requestid 为基础requestid加序号
+	baseID := requestID(ctx, p.Template.RequestID)
+	cids := make([]string, len(bodies))
+	for i := range bodies {
+		bodies[i].RequestID = baseID + "-" + strconv.Itoa(i)
+		bodies[i], err = c.prepareSingle(ctx, bodies[i])
+		if err != nil {
+			return nil, fmt.Errorf("[PushPersonalized] cid: %s, err: %w", bodies[i].CID, err)
+		}
+		cids[i] = bodies[i].CID
+	}
+
+	ret := &ChunkedResult{}
+	for i, start := 0, 0; start < len(bodies); i, start = i+1, start+maxSingleBatch {
+		end := start + maxSingleBatch
+		if end > len(bodies) {
+			end = len(bodies)
+		}
+		chunk := ChunkResult{Index: i, Targets: cids[start:end]}
+
+		if ctx.Err() != nil {
+			ret.NotAttempted = append(ret.NotAttempted, chunk)
+			continue
+		}
+
+		chunk.Ret, chunk.Err = c.pushSingleBatch(ctx, bodies[start:end])
+		if chunk.Err != nil {
+			ret.Failed = append(ret.Failed, chunk)
+			continue
+		}
+		ret.Sent = append(ret.Sent, chunk)
+	}
+
+	if err := ret.err(ctx); err != nil {
+		return ret, fmt.Errorf("[PushPersonalized] %w", err)
+	}
+	return ret, nil
+}
+
+// pushSingleBatch 批量单推
+// 参考资料 http://docs.getui.com/server/rest/push/#7-tosinglebatch
+// 开启去重时各条消息分别去重，有重复的消息时整批不发送
+func (c *client) pushSingleBatch(ctx context.Context, bodies []SingleReqBody) (_ *RspBody, err error) {
+
+	keys := make([]string, 0, len(bodies))
+	defer func() {
+		for _, key := range keys {
+			c.releaseDuplicate(key, err)
+		}
+	}()
+	for _, body := range bodies {
+		key, err := c.checkDuplicate([]string{body.CID, body.Alias}, body.Message, body.Notification, body.PushInfo)
+		if err != nil {
+			return nil, fmt.Errorf("推送去重, cid: %s, err: %w", body.CID, err)
+		}
+		keys = append(keys, key)
+	}
+
+	start := time.Now()
+	rsp, err := doRequest[singleBatchRsp](ctx, c, "POST", "push_single_batch", singleBatchBody{MsgList: bodies, NeedDetail: true})
+
+	var ret *RspBody
+	if rsp != nil {
+		ret = &RspBody{Result: rsp.Result}
+	}
+	for _, body := range bodies {
+		var item *RspBody
+		if ret != nil {
+			r := rsp.TaskID[body.RequestID]
+			r.Result = ret.Result
+			item = &r
+		}
+		c.logPush("push_single_batch", start, body.RequestID, 1, item, err)
+		c.savePush("push_single_batch", start, body.RequestID, "", singleTargets(body), 1, item, err)
+		c.auditPush(ctx, "push_single_batch", start, body.RequestID, singleTargets(body), 1, item, err)
+		c.observeDeadCID(ctx, body.CID, item)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("发送 批量单推 失败, requestid: %s, err: %w", bodies[0].RequestID, err)
+	}
+	return ret, nil
+}
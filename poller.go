@@ -0,0 +1,161 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxPushResultBatch 单次查询推送结果的任务数上限
+const maxPushResultBatch = 100
+
+// ReportPollerOptions 推送结果轮询配置
+type ReportPollerOptions struct {
+	// Metrics 指标上报，必填
+	Metrics Metrics
+	// Interval 轮询间隔 默认1分钟
+	Interval time.Duration
+	// Window 任务发送后持续轮询的时长 默认24小时
+	Window time.Duration
+	// Store 配置后每次轮询时自动跟踪 Window 内发送的任务，无需调用 Track
+	Store Store
+}
+
+// ReportPoller 推送结果轮询
+// 定期查询最近发送任务的推送结果，通过 Metrics 上报发送数、到达数、点击数与到达率，
+// 任务的标签为taskid，超过 Window 的任务不再上报
+type ReportPoller struct {
+	client Client
+	opts   ReportPollerOptions
+
+	mu    sync.Mutex
+	tasks map[string]time.Time
+
+	quit chan struct{}
+	done chan struct{}
+}
+
+// NewReportPoller 创建推送结果轮询并开始轮询
+func NewReportPoller(c Client, opts ReportPollerOptions) (*ReportPoller, error) {
+	if opts.Metrics == nil {
+		return nil, fmt.Errorf("[NewReportPoller] Metrics 不能为空")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.Window <= 0 {
+		opts.Window = 24 * time.Hour
+	}
+
+	p := &ReportPoller{
+		client: c,
+		opts:   opts,
+		tasks:  map[string]time.Time{},
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go p.loop()
+	return p, nil
+}
+
+// Track 跟踪任务，从现在起轮询 Window 时长
+func (p *ReportPoller) Track(taskID string) {
+	if len(taskID) == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.tasks[taskID]; !ok {
+		p.tasks[taskID] = time.Now()
+	}
+}
+
+// Close 停止轮询
+func (p *ReportPoller) Close(ctx context.Context) error {
+	select {
+	case <-p.quit:
+	default:
+		close(p.quit)
+	}
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("[ReportPoller.Close] 等待轮询结束超时, err: %w", ctx.Err())
+	}
+}
+
+func (p *ReportPoller) loop() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.poll()
+		select {
+		case <-ticker.C:
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// poll 查询一轮推送结果
+func (p *ReportPoller) poll() {
+	now := time.Now()
+
+	if p.opts.Store != nil {
+		pushes, err := p.opts.Store.PushResults(context.Background(), StoreQuery{Since: now.Add(-p.opts.Window)})
+		if err == nil {
+			p.mu.Lock()
+			for _, r := range pushes {
+				if _, ok := p.tasks[r.TaskID]; !ok && len(r.TaskID) > 0 {
+					p.tasks[r.TaskID] = r.SentAt
+				}
+			}
+			p.mu.Unlock()
+		}
+	}
+
+	p.mu.Lock()
+	var taskIDs []string
+	for id, since := range p.tasks {
+		if now.Sub(since) > p.opts.Window {
+			delete(p.tasks, id)
+			continue
+		}
+		taskIDs = append(taskIDs, id)
+	}
+	p.mu.Unlock()
+
+	for len(taskIDs) > 0 {
+		n := len(taskIDs)
+		if n > maxPushResultBatch {
+			n = maxPushResultBatch
+		}
+		batch := taskIDs[:n]
+		taskIDs = taskIDs[n:]
+
+		results, err := p.client.PushResult(batch...)
+		if err != nil {
+			p.opts.Metrics.AddCounter(MetricReportPolls, 1, map[string]string{"result": "error"})
+			continue
+		}
+		p.opts.Metrics.AddCounter(MetricReportPolls, 1, map[string]string{"result": "ok"})
+
+		for _, r := range results {
+			labels := map[string]string{"taskid": r.TaskID}
+			sent := r.GT.Sent + r.APN.Sent
+			delivered := r.GT.Feedback + r.APN.Feedback
+			p.opts.Metrics.SetGauge(MetricTaskSent, float64(sent), labels)
+			p.opts.Metrics.SetGauge(MetricTaskDelivered, float64(delivered), labels)
+			p.opts.Metrics.SetGauge(MetricTaskClicked, float64(r.ClickNum), labels)
+			if sent > 0 {
+				p.opts.Metrics.SetGauge(MetricTaskDeliveryRate, float64(delivered)/float64(sent), labels)
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package getui
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// PortraitTag 个推用户画像标签编号，用于 ConditionPortrait 条件
+// 内置常用画像标签见 portrait_data.go；编号以个推后台"用户画像"页面为准，
+// 账号开通了其它画像或编号有调整时，可通过 RegisterPortraitTags 补充或覆盖
+type PortraitTag string
+
+// PortraitTagInfo 画像标签说明
+type PortraitTagInfo struct {
+	Tag PortraitTag
+	// Category 分类，如 性别、年龄段
+	Category string
+	Name     string
+}
+
+// portraitMu 保护 portraitTags，RegisterPortraitTags 可在运行时补充
+var portraitMu sync.RWMutex
+
+// RegisterPortraitTags 补充画像标签，编号已存在时覆盖原有说明
+func RegisterPortraitTags(tags ...PortraitTagInfo) {
+	portraitMu.Lock()
+	defer portraitMu.Unlock()
+
+	for _, t := range tags {
+		replaced := false
+		for i := range portraitTags {
+			if portraitTags[i].Tag == t.Tag {
+				portraitTags[i] = t
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			portraitTags = append(portraitTags, t)
+		}
+	}
+}
+
+// PortraitTagByID 按编号查找画像标签
+func PortraitTagByID(tag PortraitTag) (PortraitTagInfo, bool) {
+	portraitMu.RLock()
+	defer portraitMu.RUnlock()
+
+	for _, t := range portraitTags {
+		if t.Tag == tag {
+			return t, true
+		}
+	}
+	return PortraitTagInfo{}, false
+}
+
+// PortraitTagByName 按分类与名称查找画像标签，如 PortraitTagByName("性别", "女")
+func PortraitTagByName(category, name string) (PortraitTagInfo, bool) {
+	portraitMu.RLock()
+	defer portraitMu.RUnlock()
+
+	for _, t := range portraitTags {
+		if t.Category == category && t.Name == name {
+			return t, true
+		}
+	}
+	return PortraitTagInfo{}, false
+}
+
+// PortraitTagsInCategory 分类下的全部画像标签，按编号排列
+func PortraitTagsInCategory(category string) []PortraitTagInfo {
+	portraitMu.RLock()
+	defer portraitMu.RUnlock()
+
+	var ret []PortraitTagInfo
+	for _, t := range portraitTags {
+		if t.Category == category {
+			ret = append(ret, t)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Tag < ret[j].Tag })
+	return ret
+}
+
+// PortraitCondition 创建画像toapp条件，如 PortraitCondition(OptTypeOr, PortraitAge18To24, PortraitAge25To34)
+func PortraitCondition(optType string, tags ...PortraitTag) AppReqBodyCondition {
+	values := make([]string, len(tags))
+	for i, t := range tags {
+		values[i] = string(t)
+	}
+	return NewCondition(ConditionPortrait, optType, values...)
+}
+
+// PortraitConditionByName 按 [分类, 名称] 创建画像toapp条件，如 PortraitConditionByName(OptTypeOr, [2]string{"性别", "女"})
+// 有名称找不到时返回错误
+func PortraitConditionByName(optType string, names ...[2]string) (AppReqBodyCondition, error) {
+	tags := make([]PortraitTag, 0, len(names))
+	for _, n := range names {
+		t, ok := PortraitTagByName(n[0], n[1])
+		if !ok {
+			return AppReqBodyCondition{}, fmt.Errorf("[PortraitConditionByName] 找不到画像标签 %s/%s, err: %w", n[0], n[1], ErrInvalidTarget)
+		}
+		tags = append(tags, t.Tag)
+	}
+	return PortraitCondition(optType, tags...), nil
+}
@@ -0,0 +1,50 @@
+package getui
+
+// 常用画像标签
+const (
+	PortraitMale   PortraitTag = "1001"
+	PortraitFemale PortraitTag = "1002"
+
+	PortraitAgeUnder18 PortraitTag = "2001"
+	PortraitAge18To24  PortraitTag = "2002"
+	PortraitAge25To34  PortraitTag = "2003"
+	PortraitAge35To44  PortraitTag = "2004"
+	PortraitAgeOver45  PortraitTag = "2005"
+
+	PortraitConsumptionLow    PortraitTag = "3001"
+	PortraitConsumptionMedium PortraitTag = "3002"
+	PortraitConsumptionHigh   PortraitTag = "3003"
+
+	PortraitStudent      PortraitTag = "4001"
+	PortraitWhiteCollar  PortraitTag = "4002"
+	PortraitParent       PortraitTag = "4003"
+	PortraitCarOwner     PortraitTag = "4004"
+	PortraitGamer        PortraitTag = "5001"
+	PortraitShopper      PortraitTag = "5002"
+	PortraitTraveler     PortraitTag = "5003"
+	PortraitFinance      PortraitTag = "5004"
+	PortraitVideoWatcher PortraitTag = "5005"
+)
+
+// portraitTags 内置画像标签表
+var portraitTags = []PortraitTagInfo{
+	{Tag: PortraitMale, Category: "性别", Name: "男"},
+	{Tag: PortraitFemale, Category: "性别", Name: "女"},
+	{Tag: PortraitAgeUnder18, Category: "年龄段", Name: "18岁以下"},
+	{Tag: PortraitAge18To24, Category: "年龄段", Name: "18-24岁"},
+	{Tag: PortraitAge25To34, Category: "年龄段", Name: "25-34岁"},
+	{Tag: PortraitAge35To44, Category: "年龄段", Name: "35-44岁"},
+	{Tag: PortraitAgeOver45, Category: "年龄段", Name: "45岁以上"},
+	{Tag: PortraitConsumptionLow, Category: "消费能力", Name: "低"},
+	{Tag: PortraitConsumptionMedium, Category: "消费能力", Name: "中"},
+	{Tag: PortraitConsumptionHigh, Category: "消费能力", Name: "高"},
+	{Tag: PortraitStudent, Category: "人群", Name: "学生"},
+	{Tag: PortraitWhiteCollar, Category: "人群", Name: "白领"},
+	{Tag: PortraitParent, Category: "人群", Name: "家长"},
+	{Tag: PortraitCarOwner, Category: "人群", Name: "有车一族"},
+	{Tag: PortraitGamer, Category: "兴趣", Name: "游戏"},
+	{Tag: PortraitShopper, Category: "兴趣", Name: "网购"},
+	{Tag: PortraitTraveler, Category: "兴趣", Name: "旅游"},
+	{Tag: PortraitFinance, Category: "兴趣", Name: "理财"},
+	{Tag: PortraitVideoWatcher, Category: "兴趣", Name: "视频"},
+}
@@ -0,0 +1,209 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrPreparedAppMismatch 预构建的推送与发送时的应用不一致
+var ErrPreparedAppMismatch = errors.New("getui: 预构建推送的应用与发送时不一致")
+
+// PreparedPush 已校验并填充默认值的推送，由 Client.Prepare 创建，Client.SendPrepared 发送
+// 内容不可修改；需要调整时修改原始 body 后重新 Prepare
+type PreparedPush struct {
+	endpoint   string
+	appID      string
+	single     SingleReqBody
+	list       ListReqBody
+	app        AppReqBody
+	data       []byte
+	preparedAt time.Time
+}
+
+// Endpoint 发送的接口，push_single、push_list 或 push_app
+func (p *PreparedPush) Endpoint() string { return p.endpoint }
+
+// AppID 构建时所用的应用
+func (p *PreparedPush) AppID() string { return p.appID }
+
+// RequestID 单推与toapp的requestid，list推为空
+func (p *PreparedPush) RequestID() string {
+	switch p.endpoint {
+	case "push_single":
+		return p.single.RequestID
+	case "push_app":
+		return p.app.RequestID
+	}
+	return ""
+}
+
+// Targets 推送目标，toapp 为空
+func (p *PreparedPush) Targets() []string {
+	switch p.endpoint {
+	case "push_single":
+		return singleTargets(p.single)
+	case "push_list":
+		return listTargets(p.list)
+	}
+	return nil
+}
+
+// JSON 构建时的请求JSON，同 BuildRequest，便于评审时附上准确的推送内容
+// 单推与toapp即 SendPrepared 发送的body；list推为 ListReqBody 的JSON，
+// 发送时拆分为 save_list_body（消息共同体）与 push_list（cid与taskid）两个请求
+func (p *PreparedPush) JSON() []byte {
+	return append([]byte(nil), p.data...)
+}
+
+// PreparedAt 构建时间
+func (p *PreparedPush) PreparedAt() time.Time { return p.preparedAt }
+
+// PrepareErrors Prepare 中校验失败的 body，key 为 body 的下标
+type PrepareErrors map[int]error
+
+// Error 实现 error，按下标排序列出前几个失败原因
+func (e PrepareErrors) Error() string {
+	indexes := make([]int, 0, len(e))
+	for i := range e {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d条推送校验失败", len(e))
+	for n, i := range indexes {
+		if n == 3 {
+			b.WriteString(", ...")
+			break
+		}
+		fmt.Fprintf(&b, ", 第%d条: %s", i, e[i])
+	}
+	return b.String()
+}
+
+// Prepare 校验并构建推送，不发送请求，用于评审时校验整批推送、上线时再用 SendPrepared 发送
+// bodies 支持 SingleReqBody、ListReqBody、AppReqBody 及其指针，与发送时一样填充默认值、appkey与requestid；
+// 全部校验通过时返回与 bodies 一一对应的 PreparedPush，否则返回 PrepareErrors，列出所有不通过的 body
+// ctx 中通过 WithApp 指定的应用同样生效，发送时需使用同一应用
+func (c *client) Prepare(ctx context.Context, bodies ...interface{}) ([]*PreparedPush, error) {
+
+	c, err := c.forApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[Prepare] %w", err)
+	}
+
+	ret := make([]*PreparedPush, len(bodies))
+	errs := PrepareErrors{}
+	for i, body := range bodies {
+		p, err := c.prepare(ctx, body)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		ret[i] = p
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("[Prepare] %w", errs)
+	}
+	return ret, nil
+}
+
+func (c *client) prepare(ctx context.Context, body interface{}) (*PreparedPush, error) {
+	p := &PreparedPush{appID: c.AppID, preparedAt: time.Now()}
+
+	var prepared interface{}
+	var err error
+	switch b := body.(type) {
+	case SingleReqBody:
+		p.endpoint = "push_single"
+		p.single, err = c.prepareSingle(ctx, b)
+		prepared = p.single
+	case *SingleReqBody:
+		p.endpoint = "push_single"
+		p.single, err = c.prepareSingle(ctx, *b)
+		prepared = p.single
+	case ListReqBody:
+		p.endpoint = "push_list"
+		p.list, err = c.prepareList(ctx, b)
+		prepared = p.list
+	case *ListReqBody:
+		p.endpoint = "push_list"
+		p.list, err = c.prepareList(ctx, *b)
+		prepared = p.list
+	case AppReqBody:
+		p.endpoint = "push_app"
+		p.app, err = c.prepareApp(ctx, b)
+		prepared = p.app
+	case *AppReqBody:
+		p.endpoint = "push_app"
+		p.app, err = c.prepareApp(ctx, *b)
+		prepared = p.app
+	default:
+		return nil, fmt.Errorf("不支持的body类型 %T", body)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.data, err = c.Codec.Marshal(prepared)
+	if err != nil {
+		return nil, fmt.Errorf("请求body序列化失败, err: %w", err)
+	}
+	return p, nil
+}
+
+// SendPrepared 依次发送 Prepare 构建的推送，每条为一个分片，分片序号为其在 pushes 中的下标
+// 构建时的应用与发送时不一致的推送记为失败（ErrPreparedAppMismatch）；
+// ctx 取消后不再发送剩余的推送，返回的结果中列出各条的状态
+func (c *client) SendPrepared(ctx context.Context, pushes []*PreparedPush) (*ChunkedResult, error) {
+
+	c, err := c.forApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[SendPrepared] %w", err)
+	}
+
+	ret := &ChunkedResult{}
+	for i, p := range pushes {
+		if p == nil {
+			return nil, fmt.Errorf("[SendPrepared] 第%d条推送为空", i)
+		}
+		chunk := ChunkResult{Index: i, Targets: p.Targets()}
+
+		if ctx.Err() != nil {
+			ret.NotAttempted = append(ret.NotAttempted, chunk)
+			continue
+		}
+
+		chunk.Ret, chunk.Err = c.sendPrepared(ctx, p)
+		if chunk.Err != nil {
+			ret.Failed = append(ret.Failed, chunk)
+			continue
+		}
+		ret.Sent = append(ret.Sent, chunk)
+	}
+
+	if err := ret.err(ctx); err != nil {
+		return ret, fmt.Errorf("[SendPrepared] %w", err)
+	}
+	return ret, nil
+}
+
+// sendPrepared 按接口发送一条预构建的推送
+// 单推与toapp原样发送构建时的JSON；list推需先保存消息共同体，再次 prepare 时已填充的默认值与appkey保持不变
+func (c *client) sendPrepared(ctx context.Context, p *PreparedPush) (*RspBody, error) {
+	if p.appID != c.AppID {
+		return nil, fmt.Errorf("构建于 %s, 发送于 %s, err: %w", p.appID, c.AppID, ErrPreparedAppMismatch)
+	}
+	switch p.endpoint {
+	case "push_single":
+		return c.sendSingle(ctx, p.single, rawBody(p.data))
+	case "push_list":
+		return c.pushToList(ctx, p.list, nil)
+	default:
+		return c.sendApp(ctx, p.app, rawBody(p.data))
+	}
+}
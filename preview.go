@@ -0,0 +1,44 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+)
+
+// BuildRequest 返回发送 body 时的请求JSON，不发送请求，便于代码评审、工单中附上准确的推送内容
+// body 支持 SingleReqBody、ListReqBody、AppReqBody 及其指针，与发送时一样经过校验并填充默认值、appkey；
+// body 未指定 RequestID 时生成一个，ListReqBody 未指定 TaskID 时为空（发送时由 save_list_body 返回）
+func (c *client) BuildRequest(body interface{}) ([]byte, error) {
+	return c.buildRequest(context.Background(), body)
+}
+
+func (c *client) buildRequest(ctx context.Context, body interface{}) ([]byte, error) {
+	var prepared interface{}
+	var err error
+
+	switch b := body.(type) {
+	case SingleReqBody:
+		prepared, err = c.prepareSingle(ctx, b)
+	case *SingleReqBody:
+		prepared, err = c.prepareSingle(ctx, *b)
+	case ListReqBody:
+		prepared, err = c.prepareList(ctx, b)
+	case *ListReqBody:
+		prepared, err = c.prepareList(ctx, *b)
+	case AppReqBody:
+		prepared, err = c.prepareApp(ctx, b)
+	case *AppReqBody:
+		prepared, err = c.prepareApp(ctx, *b)
+	default:
+		return nil, fmt.Errorf("[BuildRequest] 不支持的body类型 %T", body)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[BuildRequest] %w", err)
+	}
+
+	data, err := c.Codec.Marshal(prepared)
+	if err != nil {
+		return nil, fmt.Errorf("[BuildRequest] 请求body序列化失败, err: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,73 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// PushLogEntry 推送日志，每次推送请求一行JSON
+type PushLogEntry struct {
+	Time        time.Time `json:"time"`
+	Endpoint    string    `json:"endpoint"`
+	RequestID   string    `json:"requestid,omitempty"`
+	TaskID      string    `json:"taskid,omitempty"`
+	TargetCount int       `json:"target_count"`
+	Result      string    `json:"result"`
+	Status      string    `json:"status,omitempty"`
+	LatencyMs   int64     `json:"latency_ms"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// logPush 写入推送日志并输出到 Logger，两者都未配置时不做任何事
+func (c *client) logPush(endpoint string, start time.Time, requestID string, targetCount int, ret *RspBody, err error) {
+	if c.PushLog == nil && c.Logger == nil {
+		return
+	}
+
+	entry := PushLogEntry{
+		Time:        start,
+		Endpoint:    endpoint,
+		RequestID:   requestID,
+		TargetCount: targetCount,
+		LatencyMs:   time.Since(start).Milliseconds(),
+	}
+	if ret != nil {
+		entry.TaskID = ret.TaskID
+		entry.Result = ret.Result
+		entry.Status = ret.Status
+	}
+	if err != nil {
+		entry.Error = c.redact(err.Error())
+		if len(entry.Result) == 0 {
+			entry.Result = "error"
+		}
+	}
+
+	if c.Logger != nil {
+		fields := []LogField{
+			{"endpoint", entry.Endpoint},
+			{"requestid", entry.RequestID},
+			{"taskid", entry.TaskID},
+			{"target_count", entry.TargetCount},
+			{"result", entry.Result},
+			{"latency_ms", entry.LatencyMs},
+		}
+		if err != nil {
+			c.log(context.Background(), LogError, "推送失败", append(fields, LogField{"err", entry.Error})...)
+		} else {
+			c.log(context.Background(), LogInfo, "推送完成", fields...)
+		}
+	}
+
+	if c.PushLog == nil {
+		return
+	}
+
+	data, _ := json.Marshal(entry)
+	data = append(data, '\n')
+
+	c.pushLogMu.Lock()
+	defer c.pushLogMu.Unlock()
+	c.PushLog.Write(data)
+}
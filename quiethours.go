@@ -0,0 +1,69 @@
+package getui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours 免打扰时段，用于 SenderOptions.QuietHours
+type QuietHours struct {
+	// Start、End 距当天0点的时长，左闭右开；Start 晚于 End 时跨越午夜，如 22:00-08:00；两者相等时不生效
+	Start time.Duration
+	End   time.Duration
+	// Location 时段所在时区 默认 GetuiLocation
+	Location *time.Location
+}
+
+// ParseQuietHours 解析 "22:00-08:00" 格式的免打扰时段，loc 为空时使用 GetuiLocation
+func ParseQuietHours(spec string, loc *time.Location) (QuietHours, error) {
+	parts := strings.Split(spec, "-")
+	if len(parts) != 2 {
+		return QuietHours{}, fmt.Errorf("[ParseQuietHours] 错误的免打扰时段 %q, 需为 HH:MM-HH:MM", spec)
+	}
+
+	var bounds [2]time.Duration
+	for i, p := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(p))
+		if err != nil {
+			return QuietHours{}, fmt.Errorf("[ParseQuietHours] 错误的免打扰时段 %q, err: %w", spec, err)
+		}
+		bounds[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return QuietHours{Start: bounds[0], End: bounds[1], Location: loc}, nil
+}
+
+// Contains t 是否在免打扰时段内
+func (q QuietHours) Contains(t time.Time) bool {
+	off := q.offset(t)
+	switch {
+	case q.Start == q.End:
+		return false
+	case q.Start < q.End:
+		return off >= q.Start && off < q.End
+	default:
+		return off >= q.Start || off < q.End
+	}
+}
+
+// Until t 距免打扰时段结束的时长，不在时段内时为0
+func (q QuietHours) Until(t time.Time) time.Duration {
+	if !q.Contains(t) {
+		return 0
+	}
+	d := q.End - q.offset(t)
+	if d <= 0 {
+		d += 24 * time.Hour
+	}
+	return d
+}
+
+// offset t 距所在时区当天0点的时长
+func (q QuietHours) offset(t time.Time) time.Duration {
+	loc := q.Location
+	if loc == nil {
+		loc = GetuiLocation
+	}
+	local := t.In(loc)
+	return local.Sub(time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc))
+}
@@ -0,0 +1,74 @@
+package getui
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter 令牌桶限速
+type rateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(qps float64, burst int) *rateLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &rateLimiter{rate: qps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait 阻塞直到取得令牌或ctx结束
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// acquireQuota 等待限速与并发配额，返回释放函数
+// 鉴权请求不受配额限制，由调用方判断
+func (c *client) acquireQuota(ctx context.Context) (release func(), err error) {
+	if c.limiter != nil {
+		err = c.limiter.wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.concurrency == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.concurrency <- struct{}{}:
+		return func() { <-c.concurrency }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
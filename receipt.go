@@ -0,0 +1,94 @@
+package getui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ReceiptType 回执事件类型
+type ReceiptType string
+
+const (
+	// ReceiptArrived 消息到达终端
+	ReceiptArrived ReceiptType = "arrived"
+	// ReceiptClicked 通知被点击
+	ReceiptClicked ReceiptType = "clicked"
+	// ReceiptInvalidCID cid无效，通常为应用已卸载
+	ReceiptInvalidCID ReceiptType = "invalid_cid"
+	// ReceiptUnknown 无法识别的回执
+	ReceiptUnknown ReceiptType = "unknown"
+)
+
+// 回执的 actionId
+const (
+	ReceiptActionArrive  = "10001"
+	ReceiptActionClick   = "10002"
+	ReceiptActionInvalid = "10003"
+)
+
+// receiptActions actionId 与事件类型的对应关系
+var receiptActions = map[string]ReceiptType{
+	ReceiptActionArrive:  ReceiptArrived,
+	ReceiptActionClick:   ReceiptClicked,
+	ReceiptActionInvalid: ReceiptInvalidCID,
+}
+
+// Receipt 个推回执
+// 参考资料 http://docs.getui.com/server/rest/push/#doc-title-9
+type Receipt struct {
+	AppID    string `json:"appid"`
+	CID      string `json:"cid"`
+	Alias    string `json:"alias,omitempty"`
+	TaskID   string `json:"taskid"`
+	MsgID    string `json:"msgid"`
+	ActionID string `json:"actionId"`
+	// Code 0为成功
+	Code string `json:"code"`
+	Desc string `json:"desc,omitempty"`
+	Sign string `json:"sign"`
+	// RecvTime 事件发生的毫秒时间戳
+	RecvTime int64 `json:"recvtime"`
+	// Campaign 回执地址中的活动标识，见 CampaignCallbackURL，由回执接口填写
+	Campaign string `json:"-"`
+}
+
+// ParseReceipt 解析回执，不校验签名（见 VerifyCallbackSignature）
+func ParseReceipt(body []byte) (*Receipt, error) {
+	var raw struct {
+		Receipt
+		RecvTime json.Number `json:"recvtime"`
+	}
+	err := json.Unmarshal(body, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("[ParseReceipt] 回执的JSON无法解析, err: %w", err)
+	}
+
+	// recvtime 可能为数字或字符串
+	ret := raw.Receipt
+	if len(raw.RecvTime) > 0 {
+		ret.RecvTime, err = strconv.ParseInt(raw.RecvTime.String(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("[ParseReceipt] 错误的recvtime %s, err: %s", raw.RecvTime, err)
+		}
+	}
+
+	return &ret, nil
+}
+
+// Type 回执事件类型，actionId 为空时按 code 判断是否送达
+func (r *Receipt) Type() ReceiptType {
+	if t, ok := receiptActions[r.ActionID]; ok {
+		return t
+	}
+	if len(r.ActionID) == 0 && r.Code == "0" {
+		return ReceiptArrived
+	}
+	return ReceiptUnknown
+}
+
+// Time 事件发生时间
+func (r *Receipt) Time() time.Time {
+	return time.Unix(0, r.RecvTime*int64(time.Millisecond))
+}
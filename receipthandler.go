@@ -0,0 +1,89 @@
+package getui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ReceiptHandlerOptions 回执接口配置
+type ReceiptHandlerOptions struct {
+	// AppKey、MasterSecret 用于校验回执签名
+	AppKey       string
+	MasterSecret string
+	// SkipVerify 不校验签名，仅用于调试或已在网关校验的场景
+	SkipVerify bool
+	// Store 保存回执 默认不保存
+	Store Store
+	// OnReceipt 处理回执，返回错误时响应500，个推会重试回调
+	OnReceipt func(ctx context.Context, r *Receipt) error
+	// MaxBodyBytes 回执body的最大字节数 默认64KB
+	MaxBodyBytes int64
+}
+
+// receiptHandler 回执接口
+type receiptHandler struct {
+	opts ReceiptHandlerOptions
+}
+
+// NewReceiptHandler 创建回执接口，实现 http.Handler，可挂载在 chi/gin/echo 等任意路由上
+// 只接受POST，签名不一致时响应401，成功时响应 {"result":"ok"}
+func NewReceiptHandler(opts ReceiptHandlerOptions) http.Handler {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = 64 << 10
+	}
+	return &receiptHandler{opts: opts}
+}
+
+// ServeHTTP 实现 http.Handler
+func (h *receiptHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, h.opts.MaxBodyBytes))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("读取回执失败, err: %s", err), http.StatusRequestEntityTooLarge)
+		return
+	}
+	body = bytes.TrimSpace(body)
+
+	if !h.opts.SkipVerify {
+		err = VerifyCallbackSignature(req.Header, body, h.opts.AppKey, h.opts.MasterSecret)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	receipt, err := ParseReceipt(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	receipt.Campaign = req.URL.Query().Get(ReceiptCampaignParam)
+
+	if h.opts.Store != nil {
+		err = h.opts.Store.SaveReceipt(req.Context(), *receipt)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("保存回执失败, err: %s", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if h.opts.OnReceipt != nil {
+		err = h.opts.OnReceipt(req.Context(), receipt)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("处理回执失败, err: %s", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"result":"ok"}`))
+}
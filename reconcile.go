@@ -0,0 +1,171 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// 对账指标名
+const (
+	// MetricReceiptDeliveryRate 按回执统计的任务到达率 gauge，标签 taskid
+	MetricReceiptDeliveryRate = "getui_receipt_delivery_rate"
+	// MetricDeliveryAnomalies 到达率异常的任务数 counter，同一任务只计一次
+	MetricDeliveryAnomalies = "getui_delivery_anomalies_total"
+	// MetricReconcileRuns 对账次数 counter，标签 result 为 ok 或 error
+	MetricReconcileRuns = "getui_reconcile_runs_total"
+)
+
+// ReconcilerOptions 回执对账配置
+type ReconcilerOptions struct {
+	// Store 发送结果与回执的来源，必填
+	Store Store
+	// Metrics 指标上报 默认不上报
+	Metrics Metrics
+	// OnAnomaly 发现到达率异常的任务时调用，同一任务只调用一次
+	OnAnomaly func(ctx context.Context, a DeliveryAnomaly)
+	// Interval 对账间隔 默认5分钟
+	Interval time.Duration
+	// Window 对账发送时间在 Window 内的任务 默认24小时
+	Window time.Duration
+	// Grace 发送后等待回执的时长，未满 Grace 的任务不参与对账 默认10分钟
+	Grace time.Duration
+	// MinDeliveryRate 到达率低于该值视为异常 默认0.5
+	MinDeliveryRate float64
+	// MinSent 发送目标数少于该值的任务不判断异常，避免小任务误报 默认10
+	MinSent int64
+}
+
+// DeliveryAnomaly 到达率异常的任务
+type DeliveryAnomaly struct {
+	TaskID string
+	Stats  FunnelStats
+}
+
+// Reconciler 回执对账
+// 定期比对 Store 中的发送结果与收到的回执，按任务计算到达率并通过 Metrics 上报，
+// 到达率低于 MinDeliveryRate 的任务通过 OnAnomaly 通知，用于发现回执地址失效、通道降级等无声的送达问题
+type Reconciler struct {
+	opts ReconcilerOptions
+
+	mu      sync.Mutex
+	flagged map[string]time.Time
+
+	quit chan struct{}
+	done chan struct{}
+}
+
+// NewReconciler 创建回执对账并开始对账
+func NewReconciler(opts ReconcilerOptions) (*Reconciler, error) {
+	if opts.Store == nil {
+		return nil, fmt.Errorf("[NewReconciler] Store 不能为空")
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Minute
+	}
+	if opts.Window <= 0 {
+		opts.Window = 24 * time.Hour
+	}
+	if opts.Grace <= 0 {
+		opts.Grace = 10 * time.Minute
+	}
+	if opts.MinDeliveryRate <= 0 {
+		opts.MinDeliveryRate = 0.5
+	}
+	if opts.MinSent <= 0 {
+		opts.MinSent = 10
+	}
+
+	r := &Reconciler{
+		opts:    opts,
+		flagged: map[string]time.Time{},
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go r.loop()
+	return r, nil
+}
+
+// Reconcile 立即对账一次，返回本次新发现的异常任务
+func (r *Reconciler) Reconcile(ctx context.Context) ([]DeliveryAnomaly, error) {
+	now := time.Now()
+	stats, err := LoadFunnel(ctx, r.opts.Store, StoreQuery{Since: now.Add(-r.opts.Window), Until: now.Add(-r.opts.Grace)}, func(p PushRecord) string { return p.TaskID })
+	if err != nil {
+		r.count(MetricReconcileRuns, map[string]string{"result": "error"})
+		return nil, fmt.Errorf("[Reconciler.Reconcile] %w", err)
+	}
+	r.count(MetricReconcileRuns, map[string]string{"result": "ok"})
+
+	var ret []DeliveryAnomaly
+	r.mu.Lock()
+	for id, at := range r.flagged {
+		if now.Sub(at) > r.opts.Window {
+			delete(r.flagged, id)
+		}
+	}
+	for _, s := range stats {
+		if len(s.Key) == 0 || s.Sent == 0 {
+			continue
+		}
+		if r.opts.Metrics != nil {
+			r.opts.Metrics.SetGauge(MetricReceiptDeliveryRate, s.DeliveryRate, map[string]string{"taskid": s.Key})
+		}
+		if s.Sent < r.opts.MinSent || s.DeliveryRate >= r.opts.MinDeliveryRate {
+			continue
+		}
+		if _, ok := r.flagged[s.Key]; ok {
+			continue
+		}
+		r.flagged[s.Key] = now
+		ret = append(ret, DeliveryAnomaly{TaskID: s.Key, Stats: s})
+	}
+	r.mu.Unlock()
+
+	for _, a := range ret {
+		r.count(MetricDeliveryAnomalies, nil)
+		if r.opts.OnAnomaly != nil {
+			r.opts.OnAnomaly(ctx, a)
+		}
+	}
+	return ret, nil
+}
+
+// Close 停止对账
+func (r *Reconciler) Close(ctx context.Context) error {
+	select {
+	case <-r.quit:
+	default:
+		close(r.quit)
+	}
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("[Reconciler.Close] 等待对账结束超时, err: %w", ctx.Err())
+	}
+}
+
+func (r *Reconciler) loop() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		_, _ = r.Reconcile(context.Background())
+		select {
+		case <-ticker.C:
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// count 计数器加1，未配置 Metrics 时不做任何事
+func (r *Reconciler) count(name string, labels map[string]string) {
+	if r.opts.Metrics != nil {
+		r.opts.Metrics.AddCounter(name, 1, labels)
+	}
+}
@@ -0,0 +1,329 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrRecurringOverlap 上一次发送尚未完成，本次触发被跳过
+	ErrRecurringOverlap = errors.New("getui: 上一次周期推送尚未完成, 本次跳过")
+	// ErrRecurringNotFound 周期任务不存在
+	ErrRecurringNotFound = errors.New("getui: 周期任务不存在")
+	// ErrRecurringClosed 周期推送已关闭
+	ErrRecurringClosed = errors.New("getui: 周期推送已关闭")
+)
+
+// RecurringJob 周期推送任务
+type RecurringJob struct {
+	// ID 任务唯一标识，也是持久化状态的key
+	ID string
+	// Spec cron表达式，如每天早上9点 "0 9 * * *"，格式见 ParseCron
+	Spec string
+	// Location 按哪个时区解释cron表达式 默认 GetuiLocation
+	Location *time.Location
+	// Build 消息模板，每次触发时生成要发送的任务，at 为本次触发时间
+	Build func(at time.Time) (Job, error)
+}
+
+// RecurringState 周期任务需要持久化的状态
+type RecurringState struct {
+	ID      string    `json:"id"`
+	Spec    string    `json:"spec"`
+	Paused  bool      `json:"paused"`
+	LastRun time.Time `json:"last_run"`
+	NextRun time.Time `json:"next_run"`
+}
+
+// RecurringStore 周期任务状态的持久化，进程重启后恢复暂停状态与上次执行时间
+type RecurringStore interface {
+	// Load 读取任务状态，不存在时 ok 为false
+	Load(ctx context.Context, id string) (state RecurringState, ok bool, err error)
+	// Save 保存任务状态
+	Save(ctx context.Context, state RecurringState) error
+	// Delete 删除任务状态
+	Delete(ctx context.Context, id string) error
+}
+
+// RecurringOptions 周期推送配置
+type RecurringOptions struct {
+	// Store 状态持久化 默认不持久化
+	Store RecurringStore
+	// OnResult 每次触发后的回调，跳过的触发 err 为 ErrRecurringOverlap
+	OnResult func(id string, at time.Time, ret *RspBody, err error)
+}
+
+// Recurring 周期推送
+// 按cron表达式定时生成消息并通过客户端发送，如每日摘要通知；
+// 同一任务上一次发送未完成时跳过本次触发，避免重叠发送
+type Recurring struct {
+	client Client
+	opts   RecurringOptions
+
+	mu      sync.Mutex
+	entries map[string]*recurringEntry
+	closed  bool
+	running sync.WaitGroup
+}
+
+type recurringEntry struct {
+	job      RecurringJob
+	schedule *CronSchedule
+
+	mu      sync.Mutex
+	state   RecurringState
+	sending bool
+	wake    chan struct{}
+	stop    chan struct{}
+}
+
+// NewRecurring 创建周期推送
+func NewRecurring(c Client, opts RecurringOptions) *Recurring {
+	return &Recurring{
+		client:  c,
+		opts:    opts,
+		entries: map[string]*recurringEntry{},
+	}
+}
+
+// Add 添加周期任务并开始调度，配置了 Store 时恢复已保存的暂停状态与上次执行时间
+func (r *Recurring) Add(ctx context.Context, job RecurringJob) error {
+	if len(job.ID) == 0 {
+		return fmt.Errorf("[Recurring.Add] 任务ID不能为空")
+	}
+	if job.Build == nil {
+		return fmt.Errorf("[Recurring.Add] 任务 %s 的消息模板 Build 不能为空", job.ID)
+	}
+	if job.Location == nil {
+		job.Location = GetuiLocation
+	}
+	schedule, err := ParseCron(job.Spec)
+	if err != nil {
+		return fmt.Errorf("[Recurring.Add] 任务 %s 的cron表达式错误, err: %w", job.ID, err)
+	}
+
+	state := RecurringState{ID: job.ID, Spec: job.Spec}
+	if r.opts.Store != nil {
+		saved, ok, err := r.opts.Store.Load(ctx, job.ID)
+		if err != nil {
+			return fmt.Errorf("[Recurring.Add] 读取任务 %s 的状态失败, err: %w", job.ID, err)
+		}
+		if ok {
+			state.Paused = saved.Paused
+			state.LastRun = saved.LastRun
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrRecurringClosed
+	}
+	if _, ok := r.entries[job.ID]; ok {
+		return fmt.Errorf("[Recurring.Add] 任务 %s 已存在", job.ID)
+	}
+
+	e := &recurringEntry{
+		job:      job,
+		schedule: schedule,
+		state:    state,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+	r.entries[job.ID] = e
+	r.running.Add(1)
+	go r.loop(e)
+	return nil
+}
+
+// Remove 停止并删除周期任务，进行中的发送不会被中断
+func (r *Recurring) Remove(ctx context.Context, id string) error {
+	r.mu.Lock()
+	e, ok := r.entries[id]
+	if ok {
+		delete(r.entries, id)
+		close(e.stop)
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		return ErrRecurringNotFound
+	}
+	if r.opts.Store != nil {
+		err := r.opts.Store.Delete(ctx, id)
+		if err != nil {
+			return fmt.Errorf("[Recurring.Remove] 删除任务 %s 的状态失败, err: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Pause 暂停周期任务
+func (r *Recurring) Pause(ctx context.Context, id string) error {
+	return r.setPaused(ctx, id, true)
+}
+
+// Resume 恢复周期任务，从当前时间起计算下一次触发，暂停期间错过的触发不补发
+func (r *Recurring) Resume(ctx context.Context, id string) error {
+	return r.setPaused(ctx, id, false)
+}
+
+// State 获取周期任务的当前状态
+func (r *Recurring) State(id string) (RecurringState, error) {
+	r.mu.Lock()
+	e, ok := r.entries[id]
+	r.mu.Unlock()
+	if !ok {
+		return RecurringState{}, ErrRecurringNotFound
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.state, nil
+}
+
+// Close 停止所有周期任务，并等待进行中的发送完成（或ctx结束）
+// 应在 Client.Shutdown 之前调用
+func (r *Recurring) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	for _, e := range r.entries {
+		close(e.stop)
+	}
+	r.entries = map[string]*recurringEntry{}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("[Recurring.Close] 等待进行中的发送超时, err: %w", ctx.Err())
+	}
+}
+
+func (r *Recurring) setPaused(ctx context.Context, id string, paused bool) error {
+	r.mu.Lock()
+	e, ok := r.entries[id]
+	r.mu.Unlock()
+	if !ok {
+		return ErrRecurringNotFound
+	}
+
+	e.mu.Lock()
+	e.state.Paused = paused
+	state := e.state
+	e.mu.Unlock()
+
+	// 唤醒调度协程重新计算下一次触发
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+
+	if r.opts.Store != nil {
+		err := r.opts.Store.Save(ctx, state)
+		if err != nil {
+			return fmt.Errorf("[Recurring] 保存任务 %s 的状态失败, err: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// loop 单个任务的调度协程
+func (r *Recurring) loop(e *recurringEntry) {
+	defer r.running.Done()
+
+	for {
+		e.mu.Lock()
+		next := time.Time{}
+		if !e.state.Paused {
+			next = e.schedule.Next(time.Now().In(e.job.Location))
+		}
+		e.state.NextRun = next
+		e.mu.Unlock()
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			fire = timer.C
+		}
+
+		select {
+		case <-fire:
+			r.fire(e, next)
+		case <-e.wake:
+		case <-e.stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// fire 触发一次发送，上一次未完成时跳过
+func (r *Recurring) fire(e *recurringEntry, at time.Time) {
+	e.mu.Lock()
+	if e.sending {
+		e.mu.Unlock()
+		r.report(e.job.ID, at, nil, ErrRecurringOverlap)
+		return
+	}
+	e.sending = true
+	e.mu.Unlock()
+
+	r.running.Add(1)
+	go func() {
+		defer r.running.Done()
+
+		ret, err := r.send(e, at)
+
+		e.mu.Lock()
+		e.sending = false
+		e.state.LastRun = at
+		state := e.state
+		e.mu.Unlock()
+
+		if r.opts.Store != nil {
+			saveErr := r.opts.Store.Save(context.Background(), state)
+			if saveErr != nil && err == nil {
+				err = fmt.Errorf("[Recurring] 保存任务 %s 的状态失败, err: %w", e.job.ID, saveErr)
+			}
+		}
+		r.report(e.job.ID, at, ret, err)
+	}()
+}
+
+func (r *Recurring) send(e *recurringEntry, at time.Time) (*RspBody, error) {
+	job, err := e.job.Build(at)
+	if err != nil {
+		return nil, fmt.Errorf("[Recurring] 任务 %s 生成消息失败, err: %w", e.job.ID, err)
+	}
+	if job.Single == nil && job.List == nil && job.App == nil {
+		return nil, fmt.Errorf("[Recurring] 任务 %s 生成的消息错误, Single、List、App 任选且必选一个", e.job.ID)
+	}
+	return sendJob(r.client, job)
+}
+
+func (r *Recurring) report(id string, at time.Time, ret *RspBody, err error) {
+	if r.opts.OnResult != nil {
+		r.opts.OnResult(id, at, ret, err)
+	}
+}
@@ -0,0 +1,71 @@
+package getui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// minSecretLength 参与脱敏的最短密钥长度，过短的值替换后反而会破坏正常内容
+const minSecretLength = 6
+
+// String 实现 fmt.Stringer，MasterSecret、AppSecret 脱敏，避免打印配置时泄露
+func (p InitParams) String() string {
+	return fmt.Sprintf("{AppID:%s AppKey:%s AppSecret:%s MasterSecret:%s}", p.AppID, p.AppKey, maskSecret(p.AppSecret), maskSecret(p.MasterSecret))
+}
+
+// GoString 实现 fmt.GoStringer，%#v 时同样脱敏
+func (p InitParams) GoString() string {
+	return "getui.InitParams" + p.String()
+}
+
+// maskSecret 非空的密钥显示为 [REDACTED]
+func maskSecret(s string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return redactedValue
+}
+
+// secrets 当前需要脱敏的密钥：MasterSecret、AppSecret、authToken
+func (c *client) secrets() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var ret []string
+	for _, s := range []string{c.MasterSecret, c.AppSecret, c.authToken} {
+		if len(s) >= minSecretLength {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// redact 将字符串中出现的密钥替换为 [REDACTED]，用于日志与错误信息
+func (c *client) redact(s string) string {
+	for _, secret := range c.secrets() {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+	}
+	return s
+}
+
+// redactFields 对日志字段中的字符串与错误脱敏
+func (c *client) redactFields(fields []LogField) []LogField {
+	ret := make([]LogField, len(fields))
+	for i, f := range fields {
+		switch v := f.Value.(type) {
+		case string:
+			f.Value = c.redact(v)
+		case error:
+			f.Value = c.redact(v.Error())
+		case fmt.Stringer:
+			f.Value = c.redact(v.String())
+		}
+		ret[i] = f
+	}
+	return ret
+}
+
+// String 实现 fmt.Stringer，错误信息中不输出token
+func (r *authSignRsp) String() string {
+	return fmt.Sprintf("&{Result:%s AuthToken:%s ExpireTime:%s}", r.Result, maskSecret(r.AuthToken), r.ExpireTime)
+}
@@ -0,0 +1,79 @@
+// Package redistoken 基于Redis的 getui.TokenCache 实现，供多副本共享同一个auth token
+package redistoken
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultPrefix 默认key前缀
+const DefaultPrefix = "getui:authtoken:"
+
+// defaultTTL 个推未返回过期时间时，缓存保留的时长
+const defaultTTL = 24 * time.Hour
+
+// Cache Redis token缓存
+type Cache struct {
+	rdb    redis.UniversalClient
+	prefix string
+}
+
+type entry struct {
+	Token      string `json:"token"`
+	ExpireTime int64  `json:"expire_time,omitempty"`
+}
+
+// New 创建Redis token缓存，prefix 为空时使用 DefaultPrefix
+func New(rdb redis.UniversalClient, prefix string) *Cache {
+	if len(prefix) == 0 {
+		prefix = DefaultPrefix
+	}
+	return &Cache{rdb: rdb, prefix: prefix}
+}
+
+// Get 实现 getui.TokenCache
+func (c *Cache) Get(ctx context.Context, appID string) (token string, expireTime time.Time, err error) {
+	val, err := c.rdb.Get(ctx, c.prefix+appID).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("[redistoken.Get] 读取token失败, err: %s", err)
+	}
+
+	e := entry{}
+	err = json.Unmarshal([]byte(val), &e)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("[redistoken.Get] token的JSON无法解析, err: %s", err)
+	}
+
+	if e.ExpireTime > 0 {
+		expireTime = time.Unix(0, e.ExpireTime*int64(time.Millisecond))
+	}
+	return e.Token, expireTime, nil
+}
+
+// Set 实现 getui.TokenCache，key随token一起过期
+func (c *Cache) Set(ctx context.Context, appID string, token string, expireTime time.Time) error {
+	e := entry{Token: token}
+	ttl := defaultTTL
+	if !expireTime.IsZero() {
+		e.ExpireTime = expireTime.UnixNano() / int64(time.Millisecond)
+		ttl = time.Until(expireTime)
+		if ttl <= 0 {
+			return nil
+		}
+	}
+
+	data, _ := json.Marshal(e)
+	err := c.rdb.Set(ctx, c.prefix+appID, data, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("[redistoken.Set] 写入token失败, err: %s", err)
+	}
+	return nil
+}
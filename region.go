@@ -0,0 +1,87 @@
+package getui
+
+import (
+	"fmt"
+	"strings"
+)
+
+//go:generate go run ./internal/regiongen
+
+// Region 个推地区编码，用于 ConditionRegion 条件
+// 编码为8位，省级为 XX000000，地级市为 XXXX0000；
+// 内置表包含全部省级行政区与主要城市，见 internal/regiongen/regions.csv
+type Region struct {
+	Code     string
+	Province string
+	// City 省级地区为空
+	City string
+}
+
+// regionSuffixes 名称中可省略的后缀，按长度从长到短
+var regionSuffixes = []string{"维吾尔自治区", "壮族自治区", "回族自治区", "特别行政区", "自治区", "省", "市"}
+
+// normalizeRegionName 去掉"省"、"市"、"自治区"等后缀，"广东省"与"广东"视为相同
+func normalizeRegionName(name string) string {
+	name = strings.TrimSpace(name)
+	for _, s := range regionSuffixes {
+		if strings.HasSuffix(name, s) && len(name) > len(s) {
+			return strings.TrimSuffix(name, s)
+		}
+	}
+	return name
+}
+
+// RegionByCode 按编码查找地区
+func RegionByCode(code string) (Region, bool) {
+	for _, r := range regions {
+		if r.Code == code {
+			return r, true
+		}
+	}
+	return Region{}, false
+}
+
+// RegionByName 按省、市名称查找地区，名称可省略"省"、"市"等后缀
+// city 为空时返回省级地区；直辖市的 province 与 city 相同时也返回省级地区
+func RegionByName(province, city string) (Region, bool) {
+	p, c := normalizeRegionName(province), normalizeRegionName(city)
+	if c == p {
+		c = ""
+	}
+	for _, r := range regions {
+		if normalizeRegionName(r.Province) == p && normalizeRegionName(r.City) == c {
+			return r, true
+		}
+	}
+	return Region{}, false
+}
+
+// RegionsInProvince 省内的全部地区，第一个为省级地区
+func RegionsInProvince(province string) []Region {
+	p := normalizeRegionName(province)
+	var ret []Region
+	for _, r := range regions {
+		if normalizeRegionName(r.Province) == p {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+// RegionCondition 按地区名称创建toapp条件，如 RegionCondition([2]string{"广东", "深圳"}, [2]string{"上海", ""})
+// 有名称找不到时返回错误
+func RegionCondition(names ...[2]string) (AppReqBodyCondition, error) {
+	codes := make([]string, 0, len(names))
+	for _, n := range names {
+		r, ok := RegionByName(n[0], n[1])
+		if !ok {
+			return AppReqBodyCondition{}, regionNotFound(n[0], n[1])
+		}
+		codes = append(codes, r.Code)
+	}
+	return NewCondition(ConditionRegion, OptTypeOr, codes...), nil
+}
+
+func regionNotFound(province, city string) error {
+	return fmt.Errorf("[RegionCondition] 找不到地区 %s%s, err: %w", province, city, ErrInvalidTarget)
+}
@@ -0,0 +1,80 @@
+// Code generated by internal/regiongen from regions.csv. DO NOT EDIT.
+
+package getui
+
+// regions 个推地区编码表
+var regions = []Region{
+	{Code: "11000000", Province: "北京市", City: ""},
+	{Code: "12000000", Province: "天津市", City: ""},
+	{Code: "13000000", Province: "河北省", City: ""},
+	{Code: "13010000", Province: "河北省", City: "石家庄市"},
+	{Code: "14000000", Province: "山西省", City: ""},
+	{Code: "14010000", Province: "山西省", City: "太原市"},
+	{Code: "15000000", Province: "内蒙古自治区", City: ""},
+	{Code: "15010000", Province: "内蒙古自治区", City: "呼和浩特市"},
+	{Code: "21000000", Province: "辽宁省", City: ""},
+	{Code: "21010000", Province: "辽宁省", City: "沈阳市"},
+	{Code: "21020000", Province: "辽宁省", City: "大连市"},
+	{Code: "22000000", Province: "吉林省", City: ""},
+	{Code: "22010000", Province: "吉林省", City: "长春市"},
+	{Code: "23000000", Province: "黑龙江省", City: ""},
+	{Code: "23010000", Province: "黑龙江省", City: "哈尔滨市"},
+	{Code: "31000000", Province: "上海市", City: ""},
+	{Code: "32000000", Province: "江苏省", City: ""},
+	{Code: "32010000", Province: "江苏省", City: "南京市"},
+	{Code: "32020000", Province: "江苏省", City: "无锡市"},
+	{Code: "32050000", Province: "江苏省", City: "苏州市"},
+	{Code: "33000000", Province: "浙江省", City: ""},
+	{Code: "33010000", Province: "浙江省", City: "杭州市"},
+	{Code: "33020000", Province: "浙江省", City: "宁波市"},
+	{Code: "33030000", Province: "浙江省", City: "温州市"},
+	{Code: "34000000", Province: "安徽省", City: ""},
+	{Code: "34010000", Province: "安徽省", City: "合肥市"},
+	{Code: "35000000", Province: "福建省", City: ""},
+	{Code: "35010000", Province: "福建省", City: "福州市"},
+	{Code: "35020000", Province: "福建省", City: "厦门市"},
+	{Code: "36000000", Province: "江西省", City: ""},
+	{Code: "36010000", Province: "江西省", City: "南昌市"},
+	{Code: "37000000", Province: "山东省", City: ""},
+	{Code: "37010000", Province: "山东省", City: "济南市"},
+	{Code: "37020000", Province: "山东省", City: "青岛市"},
+	{Code: "41000000", Province: "河南省", City: ""},
+	{Code: "41010000", Province: "河南省", City: "郑州市"},
+	{Code: "42000000", Province: "湖北省", City: ""},
+	{Code: "42010000", Province: "湖北省", City: "武汉市"},
+	{Code: "43000000", Province: "湖南省", City: ""},
+	{Code: "43010000", Province: "湖南省", City: "长沙市"},
+	{Code: "44000000", Province: "广东省", City: ""},
+	{Code: "44010000", Province: "广东省", City: "广州市"},
+	{Code: "44030000", Province: "广东省", City: "深圳市"},
+	{Code: "44040000", Province: "广东省", City: "珠海市"},
+	{Code: "44060000", Province: "广东省", City: "佛山市"},
+	{Code: "44190000", Province: "广东省", City: "东莞市"},
+	{Code: "45000000", Province: "广西壮族自治区", City: ""},
+	{Code: "45010000", Province: "广西壮族自治区", City: "南宁市"},
+	{Code: "46000000", Province: "海南省", City: ""},
+	{Code: "46010000", Province: "海南省", City: "海口市"},
+	{Code: "46020000", Province: "海南省", City: "三亚市"},
+	{Code: "50000000", Province: "重庆市", City: ""},
+	{Code: "51000000", Province: "四川省", City: ""},
+	{Code: "51010000", Province: "四川省", City: "成都市"},
+	{Code: "52000000", Province: "贵州省", City: ""},
+	{Code: "52010000", Province: "贵州省", City: "贵阳市"},
+	{Code: "53000000", Province: "云南省", City: ""},
+	{Code: "53010000", Province: "云南省", City: "昆明市"},
+	{Code: "54000000", Province: "西藏自治区", City: ""},
+	{Code: "54010000", Province: "西藏自治区", City: "拉萨市"},
+	{Code: "61000000", Province: "陕西省", City: ""},
+	{Code: "61010000", Province: "陕西省", City: "西安市"},
+	{Code: "62000000", Province: "甘肃省", City: ""},
+	{Code: "62010000", Province: "甘肃省", City: "兰州市"},
+	{Code: "63000000", Province: "青海省", City: ""},
+	{Code: "63010000", Province: "青海省", City: "西宁市"},
+	{Code: "64000000", Province: "宁夏回族自治区", City: ""},
+	{Code: "64010000", Province: "宁夏回族自治区", City: "银川市"},
+	{Code: "65000000", Province: "新疆维吾尔自治区", City: ""},
+	{Code: "65010000", Province: "新疆维吾尔自治区", City: "乌鲁木齐市"},
+	{Code: "71000000", Province: "台湾省", City: ""},
+	{Code: "81000000", Province: "香港特别行政区", City: ""},
+	{Code: "82000000", Province: "澳门特别行政区", City: ""},
+}
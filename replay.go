@@ -0,0 +1,136 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ReplayResult 回放一条录制请求的结果
+type ReplayResult struct {
+	// Recorded 录制的请求与响应
+	Recorded Interaction
+	// Path 实际发送的接口路径，如 push_list
+	Path string
+	// Body 实际发送的body
+	Body string
+	Ret  *RspBody
+	Err  error
+}
+
+// ReplayInteractions 按录制顺序向当前客户端的接口地址重新发送 Recorder 录制的请求，用于复现用户反馈的推送问题
+// 鉴权由客户端处理，录制中的 auth_sign、auth_close 跳过；body 中的 appkey 替换为当前应用的，
+// 脱敏的 requestid 重新生成，录制的taskid替换为回放时 save_list_body 等返回的新taskid，其余内容原样发送
+// ctx 取消后不再发送剩余的请求；有请求失败时同时返回错误，各条的结果见返回值
+func (c *client) ReplayInteractions(ctx context.Context, interactions []Interaction) ([]ReplayResult, error) {
+
+	var ret []ReplayResult
+	var failed int
+	var firstErr error
+	taskIDs := map[string]string{}
+
+	for i, it := range interactions {
+		path, err := replayPath(it.Path)
+		if err != nil {
+			return ret, fmt.Errorf("[ReplayInteractions] 第%d个请求 %w", i, err)
+		}
+		if ep := endpoint(path); ep == "auth_sign" || ep == "auth_close" {
+			continue
+		}
+		if ctx.Err() != nil {
+			return ret, fmt.Errorf("[ReplayInteractions] 已回放%d个请求, err: %w", len(ret), ctx.Err())
+		}
+
+		r := ReplayResult{Recorded: it, Path: replaceTaskIDs(path, taskIDs)}
+		var body interface{}
+		if len(it.RequestBody) > 0 {
+			r.Body, err = c.replayBody(ctx, it.RequestBody, taskIDs)
+			if err != nil {
+				return ret, fmt.Errorf("[ReplayInteractions] 第%d个请求的body无法解析, err: %w", i, err)
+			}
+			body = rawBody(r.Body)
+		}
+
+		r.Ret, r.Err = doRequest[RspBody](ctx, c, it.Method, r.Path, body)
+		if r.Ret != nil && len(r.Ret.TaskID) > 0 {
+			var recorded RspBody
+			if json.Unmarshal([]byte(it.ResponseBody), &recorded) == nil && len(recorded.TaskID) > 0 {
+				taskIDs[recorded.TaskID] = r.Ret.TaskID
+			}
+		}
+		if r.Err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = r.Err
+			}
+		}
+		ret = append(ret, r)
+	}
+
+	if failed > 0 {
+		return ret, fmt.Errorf("[ReplayInteractions] %d个请求失败, 第一个错误: %w", failed, firstErr)
+	}
+	return ret, nil
+}
+
+// replayPath 录制的url路径 /v1/{appid}/push_single 中 appid 之后的部分
+func replayPath(p string) (string, error) {
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	for i, part := range parts {
+		if part == "v1" && i+2 < len(parts) {
+			return strings.Join(parts[i+2:], "/"), nil
+		}
+	}
+	return "", fmt.Errorf("无法识别的路径 %s", p)
+}
+
+// replaceTaskIDs 路径中录制的taskid替换为回放时的，如 stop_task/{taskid}
+func replaceTaskIDs(path string, taskIDs map[string]string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if id, ok := taskIDs[part]; ok {
+			parts[i] = id
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// replayBody 替换录制body中的 appkey、脱敏的 requestid 与录制的taskid
+func (c *client) replayBody(ctx context.Context, recorded string, taskIDs map[string]string) (string, error) {
+	var v interface{}
+	err := json.Unmarshal([]byte(recorded), &v)
+	if err != nil {
+		return "", err
+	}
+	v = c.replayValue(ctx, v, taskIDs)
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (c *client) replayValue(ctx context.Context, v interface{}, taskIDs map[string]string) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range val {
+			s, isString := sub.(string)
+			switch {
+			case k == "appkey" && isString:
+				val[k] = c.appKey()
+			case k == "requestid" && s == redactedValue:
+				val[k] = requestID(ctx, "")
+			case k == "taskid" && isString && len(taskIDs[s]) > 0:
+				val[k] = taskIDs[s]
+			default:
+				val[k] = c.replayValue(ctx, sub, taskIDs)
+			}
+		}
+	case []interface{}:
+		for i, sub := range val {
+			val[i] = c.replayValue(ctx, sub, taskIDs)
+		}
+	}
+	return v
+}
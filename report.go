@@ -0,0 +1,119 @@
+package getui
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// reportDateLayout 报表接口的日期格式
+const reportDateLayout = "20060102"
+
+// PushResultList 多个任务的推送结果
+type PushResultList []PushResult
+
+// AppPushReport 应用某天的推送数据
+type AppPushReport struct {
+	Date            string `json:"date"`
+	SendCount       int64  `json:"sendCount"`
+	SendOnlineCount int64  `json:"sendOnlineCount"`
+	ReceiveCount    int64  `json:"receiveCount"`
+	ShowCount       int64  `json:"showCount"`
+	ClickCount      int64  `json:"clickCount"`
+}
+
+// AppPushReportList 多天的推送数据
+type AppPushReportList []AppPushReport
+
+// AppUserReport 应用某天的用户数据
+type AppUserReport struct {
+	Date             string `json:"date"`
+	NewRegistCount   int64  `json:"newRegistCount"`
+	RegistTotalCount int64  `json:"registTotalCount"`
+	ActiveCount      int64  `json:"activeCount"`
+	OnlineCount      int64  `json:"onlineCount"`
+}
+
+// AppUserReportList 多天的用户数据
+type AppUserReportList []AppUserReport
+
+// reportRsp 报表接口 rsp body
+type reportRsp[T any] struct {
+	Result string `json:"result"`
+	Data   T      `json:"data"`
+}
+
+func (r *reportRsp[T]) result() string {
+	return r.Result
+}
+
+// QueryAppPush 查询应用某天的推送数据，date 按北京时间取日期
+// 参考资料 http://docs.getui.com/server/rest/report/#2
+func (c *client) QueryAppPush(date time.Time) (*AppPushReport, error) {
+
+	ret, err := doRequest[reportRsp[AppPushReport]](context.Background(), c, "GET", "query_app_push/"+date.In(GetuiLocation).Format(reportDateLayout), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[QueryAppPush] 查询推送数据 失败, err: %w", err)
+	}
+
+	return &ret.Data, nil
+}
+
+// QueryAppUser 查询应用某天的用户数据，date 按北京时间取日期
+// 参考资料 http://docs.getui.com/server/rest/report/#3
+func (c *client) QueryAppUser(date time.Time) (*AppUserReport, error) {
+
+	ret, err := doRequest[reportRsp[AppUserReport]](context.Background(), c, "GET", "query_app_user/"+date.In(GetuiLocation).Format(reportDateLayout), nil)
+	if err != nil {
+		return nil, fmt.Errorf("[QueryAppUser] 查询用户数据 失败, err: %w", err)
+	}
+
+	return &ret.Data, nil
+}
+
+// WriteCSV 以CSV写出推送结果，第一行为表头
+func (l PushResultList) WriteCSV(w io.Writer) error {
+	rows := [][]string{{"taskid", "msg_total", "msg_process", "push_num", "click_num",
+		"gt_sent", "gt_feedback", "gt_displayed", "gt_clicked",
+		"apn_sent", "apn_feedback", "apn_displayed", "apn_clicked"}}
+	for _, r := range l {
+		rows = append(rows, []string{r.TaskID, itoa(r.MsgTotal), itoa(r.MsgProcess), itoa(r.PushNum), itoa(r.ClickNum),
+			itoa(r.GT.Sent), itoa(r.GT.Feedback), itoa(r.GT.Displayed), itoa(r.GT.Clicked),
+			itoa(r.APN.Sent), itoa(r.APN.Feedback), itoa(r.APN.Displayed), itoa(r.APN.Clicked)})
+	}
+	return writeCSV(w, rows)
+}
+
+// WriteCSV 以CSV写出每日推送数据，第一行为表头
+func (l AppPushReportList) WriteCSV(w io.Writer) error {
+	rows := [][]string{{"date", "send_count", "send_online_count", "receive_count", "show_count", "click_count"}}
+	for _, r := range l {
+		rows = append(rows, []string{r.Date, itoa(r.SendCount), itoa(r.SendOnlineCount), itoa(r.ReceiveCount), itoa(r.ShowCount), itoa(r.ClickCount)})
+	}
+	return writeCSV(w, rows)
+}
+
+// WriteCSV 以CSV写出每日用户数据，第一行为表头
+func (l AppUserReportList) WriteCSV(w io.Writer) error {
+	rows := [][]string{{"date", "new_regist_count", "regist_total_count", "active_count", "online_count"}}
+	for _, r := range l {
+		rows = append(rows, []string{r.Date, itoa(r.NewRegistCount), itoa(r.RegistTotalCount), itoa(r.ActiveCount), itoa(r.OnlineCount)})
+	}
+	return writeCSV(w, rows)
+}
+
+func writeCSV(w io.Writer, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	err := cw.WriteAll(rows)
+	if err != nil {
+		return fmt.Errorf("[WriteCSV] 写入CSV失败, err: %w", err)
+	}
+	return nil
+}
+
+func itoa(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
@@ -0,0 +1,211 @@
+package getui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// apiBaseURL 个推 RestAPI v1 地址
+const apiBaseURL = "https://restapi.getui.com/v1/"
+
+// authPaths 鉴权相关接口，不受客户端关闭的影响
+var authPaths = map[string]bool{
+	"auth_sign":  true,
+	"auth_close": true,
+}
+
+// endpoint 路径中的接口名，如 user_status/{cid} 为 user_status
+func endpoint(path string) string {
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// resultGetter 带 result 字段的返回结构
+type resultGetter interface {
+	result() string
+}
+
+func (r *RspBody) result() string {
+	return r.Result
+}
+
+func (u *UserStatus) result() string {
+	return u.Result
+}
+
+// rawBody 已序列化的请求body，doRequest 原样发送
+type rawBody []byte
+
+// doRequest 发送请求并解析返回的JSON
+// body 为空时不发送body；T 实现 resultGetter 时，result 不为 ok 视为失败，此时仍返回解析结果
+func doRequest[T any](ctx context.Context, c *client, method, path string, body interface{}) (*T, error) {
+	start := time.Now()
+	ret, err := request[T](ctx, c, method, path, body)
+	c.observeRequest(path, start, err)
+	if err != nil {
+		var result string
+		if r, ok := any(ret).(resultGetter); ok && ret != nil {
+			result = r.result()
+		}
+		c.reportError(ctx, method, path, start, result, err)
+	}
+	return ret, err
+}
+
+// request 见 doRequest，不上报耗时
+func request[T any](ctx context.Context, c *client, method, path string, body interface{}) (*T, error) {
+
+	// 客户端关闭后不再接受新的请求，鉴权请求除外
+	if !authPaths[path] {
+		err := c.begin()
+		if err != nil {
+			return nil, err
+		}
+		defer c.inflight.Done()
+
+		if c.LazyAuth {
+			err = c.ensureAuth(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("申请token失败, err: %w", err)
+			}
+		}
+
+		release, err := c.acquireQuota(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("等待请求配额失败, err: %w", err)
+		}
+		defer release()
+	}
+
+	// 构造请求
+	var data []byte
+	if raw, ok := body.(rawBody); ok {
+		data = raw
+	} else if body != nil {
+		var err error
+		data, err = c.Codec.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("请求body序列化失败, err: %w", err)
+		}
+	}
+
+	// 发送请求，失败时按 RetryPolicy 与 MaxRetries 重试
+	var rsp *http.Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if !c.retryBudget.take() {
+				return nil, fmt.Errorf("发送请求失败, err: %w", ErrRetryBudgetExhausted)
+			}
+			if err := sleepCtx(ctx, c.retryDelay(attempt)); err != nil {
+				return nil, fmt.Errorf("发送请求失败, err: %w", err)
+			}
+		}
+
+		rsp, err = c.send(ctx, method, path, data)
+		if attempt >= c.MaxRetries || !c.shouldRetry(ctx, method, path, attempt+1, rsp, err) {
+			break
+		}
+		if err == nil {
+			io.Copy(io.Discard, rsp.Body)
+			rsp.Body.Close()
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("发送请求失败, err: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("发送请求失败, http状态码 %d, err: %w", rsp.StatusCode, ErrRateLimited)
+	}
+
+	raw, err := c.readBody(rsp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败, err: %w", err)
+	}
+
+	// 网关等返回的HTML、空body等不是个推的响应，不按JSON解析
+	if !looksLikeJSON(raw) {
+		return nil, fmt.Errorf("发送请求失败, err: %w", newTransportError(rsp, c.redact(string(raw))))
+	}
+
+	// 解析-json
+	ret := new(T)
+	err = c.Codec.Unmarshal(raw, ret)
+	if err != nil {
+		return nil, fmt.Errorf("返回的JSON无法解析, err: %w", err)
+	}
+
+	// 错误信息中的返回内容脱敏，如auth_sign返回的token
+	if r, ok := any(ret).(resultGetter); ok && r.result() != "ok" {
+		return ret, resultError(r.result(), c.redact(fmt.Sprintf("%v", ret)))
+	}
+
+	return ret, nil
+}
+
+// 鉴权token的请求头名
+const (
+	// AuthHeaderV1 v1接口的请求头
+	AuthHeaderV1 = "authtoken"
+	// AuthHeaderV2 v2接口的请求头
+	AuthHeaderV2 = "token"
+)
+
+// setAuthHeader 设置鉴权请求头
+// 个推按原样匹配请求头名，不能使用 Header.Set 规范化为 Authtoken
+func setAuthHeader(h http.Header, name, token string) {
+	if len(name) == 0 {
+		name = AuthHeaderV1
+	}
+	h[name] = []string{token}
+}
+
+// send 发送一次请求
+// 配置了多个接口地址时，连接失败换下一个地址，失败的地址在 HostCooldown 内排到最后
+func (c *client) send(ctx context.Context, method, path string, data []byte) (*http.Response, error) {
+	var rsp *http.Response
+	var err error
+	for _, i := range c.hosts.order(time.Now()) {
+		rsp, err = c.sendTo(ctx, c.hosts.urls[i], method, path, data)
+		if !failover(ctx, err) {
+			c.hosts.markUp(i)
+			c.observeServerDate(rsp)
+			return rsp, err
+		}
+		c.hosts.markDown(i, time.Now())
+		if len(c.hosts.urls) > 1 {
+			c.log(ctx, LogWarn, "接口地址连接失败, 尝试下一个地址", LogField{"host", c.hosts.urls[i]}, LogField{"err", err.Error()})
+		}
+	}
+	return rsp, err
+}
+
+// sendTo 向指定地址发送一次请求
+func (c *client) sendTo(ctx context.Context, baseURL, method, path string, data []byte) (*http.Response, error) {
+	var reader io.Reader
+	if data != nil {
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+c.AppID+"/"+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败, err: %w", err)
+	}
+
+	req.Header["Content-Type"] = []string{"application/json"}
+	req.Header.Set("User-Agent", c.userAgent())
+	if token := c.AuthToken(); len(token) > 0 {
+		setAuthHeader(req.Header, c.AuthHeader, token)
+	}
+
+	return c.httpClient().Do(req)
+}
@@ -0,0 +1,33 @@
+package getui
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// requestIDKey context中requestid的key
+type requestIDKey struct{}
+
+// WithRequestID 在ctx中设置requestid，如网关的trace id
+// 通过 ...Context 方法推送时，body未指定 RequestID 则使用该值，日志与错误中也会带上它
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext 取出ctx中的requestid，没有时返回空串
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestID 依次使用 body 中的、ctx 中的requestid，都没有时生成一个
+func requestID(ctx context.Context, id string) string {
+	if len(id) > 0 {
+		return id
+	}
+	if id = RequestIDFromContext(ctx); len(id) > 0 {
+		return id
+	}
+	return strconv.FormatInt(time.Now().UnixNano(), 12)
+}
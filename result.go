@@ -0,0 +1,105 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PushResultStat 单个通道的下发统计
+type PushResultStat struct {
+	Sent      int64  `json:"sent"`
+	Feedback  int64  `json:"feedback"`
+	Clicked   int64  `json:"clicked"`
+	Displayed int64  `json:"displayed"`
+	Result    string `json:"result"`
+}
+
+// PushResult 任务的推送结果
+type PushResult struct {
+	TaskID     string `json:"taskId"`
+	MsgTotal   int64  `json:"msgTotal"`
+	MsgProcess int64  `json:"msgProcess"`
+	ClickNum   int64  `json:"clickNum"`
+	PushNum    int64  `json:"pushNum"`
+	// GT 个推通道
+	GT PushResultStat `json:"GT"`
+	// APN 苹果通道
+	APN PushResultStat `json:"apn"`
+}
+
+// pushResultRsp push_result rsp body
+type pushResultRsp struct {
+	Result string         `json:"result"`
+	Data   PushResultList `json:"data"`
+}
+
+func (r *pushResultRsp) result() string {
+	return r.Result
+}
+
+// PushResult 查询推送结果
+// 参考资料 http://docs.getui.com/server/rest/report/#1
+func (c *client) PushResult(taskIDs ...string) (PushResultList, error) {
+	return c.pushResult(context.Background(), taskIDs...)
+}
+
+func (c *client) pushResult(ctx context.Context, taskIDs ...string) (PushResultList, error) {
+
+	if len(taskIDs) == 0 {
+		return nil, fmt.Errorf("[PushResult] 错误的参数, taskid 不能为空")
+	}
+
+	ret, err := doRequest[pushResultRsp](ctx, c, "POST", "push_result", map[string][]string{"taskIdList": taskIDs})
+	if err != nil {
+		return nil, fmt.Errorf("[PushResult] 查询推送结果 失败, err: %w", err)
+	}
+
+	return ret.Data, nil
+}
+
+// WaitForResult 轮询推送结果，直到统计稳定或ctx结束
+// 统计稳定指任务已处理完（msgProcess >= msgTotal）且与上一次查询的结果一致；
+// 网络错误、限流等临时故障（见 IsTransient）不中断轮询，下次照常查询；
+// ctx结束时返回最后一次查询到的结果与ctx的错误
+func (c *client) WaitForResult(ctx context.Context, taskID string, pollInterval time.Duration) (*PushResult, error) {
+
+	c, err := c.forApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[WaitForResult] %w", err)
+	}
+
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+
+	var last *PushResult
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		results, err := c.pushResult(ctx, taskID)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return last, fmt.Errorf("[WaitForResult] 任务 %s 等待结果被中断, err: %w", taskID, ctx.Err())
+		case err != nil && isTransient(err):
+			c.log(ctx, LogWarn, "查询推送结果失败, 稍后重试", LogField{"taskid", taskID}, LogField{"err", err.Error()})
+		case err != nil:
+			return last, fmt.Errorf("[WaitForResult] 任务 %s, err: %w", taskID, err)
+		case len(results) == 0:
+			return last, fmt.Errorf("[WaitForResult] 任务 %s 没有推送结果", taskID)
+		default:
+			cur := results[0]
+			if last != nil && *last == cur && cur.MsgTotal > 0 && cur.MsgProcess >= cur.MsgTotal {
+				return &cur, nil
+			}
+			last = &cur
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return last, fmt.Errorf("[WaitForResult] 任务 %s 等待结果被中断, err: %w", taskID, ctx.Err())
+		}
+	}
+}
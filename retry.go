@@ -0,0 +1,204 @@
+package getui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrRetryBudgetExhausted 客户端的重试预算已用完，不再重试
+var ErrRetryBudgetExhausted = errors.New("getui: 重试预算已用完")
+
+const (
+	defaultRetryBackoff      = 200 * time.Millisecond
+	defaultRetryBudgetWindow = time.Minute
+)
+
+// retryBudget 固定窗口内的重试次数上限，整个客户端共享
+// 个推故障时避免每个请求各自重试，放大成重试风暴
+type retryBudget struct {
+	limit  int
+	window time.Duration
+
+	mu    sync.Mutex
+	start time.Time
+	used  int
+}
+
+func newRetryBudget(limit int, window time.Duration) *retryBudget {
+	return &retryBudget{limit: limit, window: window}
+}
+
+// take 消耗一次重试，预算用完时返回false；b 为空时不限制
+func (b *retryBudget) take() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(b.start) >= b.window {
+		b.start = now
+		b.used = 0
+	}
+	if b.used >= b.limit {
+		return false
+	}
+	b.used++
+	return true
+}
+
+// RetryAttempt 一次请求尝试的结果，供 RetryPolicy 判断是否重试
+type RetryAttempt struct {
+	Method string
+	// Endpoint 接口名，如 push_single、user_status
+	Endpoint string
+	// Path 完整路径，如 user_status/{cid}
+	Path string
+	// Attempt 已发送的次数，从1开始
+	Attempt int
+	// StatusCode http状态码，网络错误时为0
+	StatusCode int
+	// Result 个推返回的 result，无法解析时为空
+	Result string
+	// Err 网络错误
+	Err error
+}
+
+// RetryPolicy 重试策略，每次请求失败（网络错误、非2xx或 result 不为ok）后调用，次数仍受 MaxRetries 与 RetryBudget 限制
+// 可据此实现幂等相关的策略，如查询随意重试、推送只在连接错误时重试
+type RetryPolicy interface {
+	ShouldRetry(a RetryAttempt) bool
+}
+
+// RetryPolicyFunc 函数形式的 RetryPolicy
+type RetryPolicyFunc func(a RetryAttempt) bool
+
+// ShouldRetry 实现 RetryPolicy
+func (f RetryPolicyFunc) ShouldRetry(a RetryAttempt) bool {
+	return f(a)
+}
+
+// DefaultRetryPolicy 默认策略，按幂等性重试：
+// 查询类请求（GET、DELETE 及 auth_sign、push_result）在网络错误、429、5xx 时重试；
+// 推送等其它请求个推可能已经处理，只在连接未建立（请求未发出）或 429 时重试，避免重复推送
+var DefaultRetryPolicy RetryPolicy = RetryPolicyFunc(func(a RetryAttempt) bool {
+	if a.Method == http.MethodGet || a.Method == http.MethodDelete || idempotentPosts[a.Endpoint] {
+		return RetryAllPolicy.ShouldRetry(a)
+	}
+	if a.Err != nil {
+		return notSent(a.Err)
+	}
+	return a.StatusCode == http.StatusTooManyRequests
+})
+
+// RetryAllPolicy 不区分幂等性，网络错误、429、5xx 时都重试
+// 推送请求超时或5xx时个推可能已经处理，使用该策略可能重复推送
+var RetryAllPolicy RetryPolicy = RetryPolicyFunc(func(a RetryAttempt) bool {
+	if a.Err != nil {
+		return true
+	}
+	return a.StatusCode == http.StatusTooManyRequests || a.StatusCode >= http.StatusInternalServerError
+})
+
+// idempotentPosts 以POST发送的查询类接口，重复发送没有副作用
+var idempotentPosts = map[string]bool{
+	"auth_sign":   true,
+	"push_result": true,
+}
+
+// notSent 建立连接失败，请求未发出，重试不会重复推送
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// shouldRetry 按 RetryPolicy 判断是否重试，需要时预读响应body取出 result
+func (c *client) shouldRetry(ctx context.Context, method, path string, attempt int, rsp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	a := RetryAttempt{Method: method, Endpoint: endpoint(path), Path: path, Attempt: attempt, Err: err}
+	if err == nil {
+		a.StatusCode = rsp.StatusCode
+		a.Result = peekResult(rsp, c.MaxResponseBytes)
+		// 成功的请求不需要询问策略
+		if rsp.StatusCode < http.StatusMultipleChoices && a.Result == "ok" {
+			return false
+		}
+	}
+
+	policy := c.RetryPolicy
+	if policy == nil {
+		policy = DefaultRetryPolicy
+	}
+	return policy.ShouldRetry(a)
+}
+
+// peekResult 读出响应body中的 result，并替换为可重复读取的副本
+func peekResult(rsp *http.Response, limit int64) string {
+	data, err := io.ReadAll(io.LimitReader(rsp.Body, limit+1))
+	rsp.Body.Close()
+	rsp.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+
+	ret := struct {
+		Result string `json:"result"`
+	}{}
+	json.Unmarshal(data, &ret)
+	return ret.Result
+}
+
+// IsTransient 是否为网络错误、限流或网关5xx等临时故障，稍后重试可能成功
+// cid格式错误、用户不存在、个推返回的业务错误等重试也不会成功，返回false
+func IsTransient(err error) bool {
+	return isTransient(err)
+}
+
+// isTransient 是否为网络错误、限流或网关5xx等临时故障，重试可能成功
+func isTransient(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	var transportErr *TransportError
+	return errors.As(err, &transportErr) && (transportErr.StatusCode == 0 || transportErr.StatusCode >= http.StatusInternalServerError)
+}
+
+// maxRetryBackoff 重试的最长间隔
+const maxRetryBackoff = 5 * time.Second
+
+// retryDelay 第attempt次重试前的等待时间，指数退避，最长 maxRetryBackoff
+// 逐次翻倍而不是移位，超过上限后停止，避免重试次数较多时溢出
+func (c *client) retryDelay(attempt int) time.Duration {
+	d := c.RetryBackoff
+	for i := 1; i < attempt && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return d
+}
+
+// sleepCtx 等待d或ctx结束
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,58 @@
+package getui
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// MediaType 富媒体类型，取值与 PushInfoMultimedia.Type 一致
+type MediaType int
+
+const (
+	// MediaImage 图片
+	MediaImage MediaType = 1
+	// MediaAudio 音频
+	MediaAudio MediaType = 2
+	// MediaVideo 视频
+	MediaVideo MediaType = 3
+)
+
+// String 附件类型名，写入 RichMedia.Type
+func (t MediaType) String() string {
+	switch t {
+	case MediaImage:
+		return "image"
+	case MediaAudio:
+		return "audio"
+	case MediaVideo:
+		return "video"
+	}
+	return "unknown"
+}
+
+// RichMedia iOS富媒体附件，位于 push_info 的 media 字段：
+//
+//	{"aps":{"mutable-content":1,...},"media":{"url":"https://...","type":"image"}}
+//
+// Notification Service Extension 从 media.url 下载附件并按 media.type 创建 UNNotificationAttachment
+type RichMedia struct {
+	URL  string `json:"url"`
+	Type string `json:"type"`
+}
+
+// SetRichMedia 设置iOS富媒体附件：开启 mutable-content，在 media 中带上附件地址，
+// 同时追加一条 Multimedia 供个推的多媒体下发使用；mediaURL 需为 https 地址
+func (p *PushInfo) SetRichMedia(mediaURL string, t MediaType, onlyWifi bool) error {
+	if t.String() == "unknown" {
+		return fmt.Errorf("[SetRichMedia] 错误的富媒体类型 %d", t)
+	}
+	u, err := url.Parse(mediaURL)
+	if err != nil || u.Scheme != "https" || len(u.Host) == 0 {
+		return fmt.Errorf("[SetRichMedia] 错误的附件地址 %q, 需为 https 地址", mediaURL)
+	}
+
+	p.Aps.MutableContent = 1
+	p.Media = &RichMedia{URL: mediaURL, Type: t.String()}
+	p.Multimedia = append(p.Multimedia, PushInfoMultimedia{URL: mediaURL, Type: int(t), OnlyWifi: onlyWifi})
+	return nil
+}
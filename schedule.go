@@ -0,0 +1,38 @@
+package getui
+
+import (
+	"fmt"
+	"time"
+)
+
+// getuiPushTimeLayout 个推定时任务时间格式 yyyyMMddHHmm
+const getuiPushTimeLayout = "200601021504"
+
+// GetuiLocation 个推服务端使用的时区（北京时间，UTC+8，无夏令时）
+var GetuiLocation = time.FixedZone("CST", 8*60*60)
+
+// FormatPushTime 将任意时区的时间转换为个推定时任务要求的北京时间 yyyyMMddHHmm
+func FormatPushTime(t time.Time) string {
+	return t.In(GetuiLocation).Format(getuiPushTimeLayout)
+}
+
+// ScheduleAt 设置toapp推送为定时任务，t 可为任意时区，按分钟向下取整
+// 参考资料 http://docs.getui.com/server/rest/push/#5-toapp
+func (b *AppReqBody) ScheduleAt(t time.Time) error {
+	if !t.After(time.Now()) {
+		return fmt.Errorf("[ScheduleAt] 定时时间 %s 已过", t.Format(time.RFC3339))
+	}
+	b.PushTime = FormatPushTime(t)
+	return nil
+}
+
+// NextLocalTime 计算 now 之后，loc 时区下第一个 hour:minute 的时刻
+// 用于"用户所在地区早上9点"这类发送时间，如 NextLocalTime(time.Now(), shanghai, 9, 0)
+func NextLocalTime(now time.Time, loc *time.Location, hour, minute int) time.Time {
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !next.After(local) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, hour, minute, 0, 0, loc)
+	}
+	return next
+}
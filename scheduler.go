@@ -0,0 +1,285 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// SchedulerOptions 跨应用推送调度配置
+type SchedulerOptions struct {
+	// GlobalQPS 所有应用合计每秒发送的分片数上限 默认0不限制
+	// 各应用的 AppConfig.QPS、MaxConcurrency 仍然生效
+	GlobalQPS float64
+	// GlobalBurst 全局限速允许的突发分片数 默认1
+	GlobalBurst int
+}
+
+// Scheduler 跨应用的分片推送调度
+// 各应用轮流发送，应用内的多个活动也轮流发送，每个活动同时只有一个分片在发送；
+// 一个应用的大量推送不会让其它应用的活动等到它发完
+type Scheduler struct {
+	m      *Manager
+	global *rateLimiter
+
+	mu sync.Mutex
+	// order 有待发送活动的应用，按加入顺序轮流
+	order  []string
+	queues map[string]*appQueue
+	cursor int
+	closed bool
+
+	wake chan struct{}
+	stop chan struct{}
+	// stopCtx Close 时取消，用于中断全局限速的等待
+	stopCtx    context.Context
+	cancelStop context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// appQueue 一个应用待发送的活动
+type appQueue struct {
+	campaigns []*ScheduledCampaign
+	cursor    int
+}
+
+// ScheduledCampaign 提交到 Scheduler 的活动
+type ScheduledCampaign struct {
+	App string
+
+	ctx      context.Context
+	lc       *listCampaign
+	inflight bool
+	done     chan struct{}
+	err      error
+}
+
+// Wait 等待活动发送完成，返回各分片的结果；ctx 结束时返回ctx的错误，活动继续发送
+func (sc *ScheduledCampaign) Wait(ctx context.Context) (*ChunkedResult, error) {
+	select {
+	case <-sc.done:
+		return sc.lc.ret, sc.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// NewScheduler 创建跨应用推送调度，用完需 Close
+func (m *Manager) NewScheduler(opts SchedulerOptions) *Scheduler {
+	s := &Scheduler{
+		m:      m,
+		queues: map[string]*appQueue{},
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+	if opts.GlobalQPS > 0 {
+		s.global = newRateLimiter(opts.GlobalQPS, opts.GlobalBurst)
+	}
+	s.stopCtx, s.cancelStop = context.WithCancel(context.Background())
+
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+// Submit 提交应用 app 的分片list推，参数同 PushToListChunked
+// 校验失败时直接返回错误；ctx 结束后剩余分片不再发送
+func (s *Scheduler) Submit(ctx context.Context, app string, body ListReqBody, chunkSize int) (*ScheduledCampaign, error) {
+	s.m.mu.RLock()
+	c, ok := s.m.apps[app]
+	s.m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("[Scheduler.Submit] 应用 %s 不存在", app)
+	}
+
+	lc, err := c.newListCampaign(ctx, "Scheduler.Submit", body, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	sc := &ScheduledCampaign{App: app, ctx: ctx, lc: lc, done: make(chan struct{})}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, fmt.Errorf("[Scheduler.Submit] 调度已关闭, err: %w", ErrClientClosed)
+	}
+	if lc.done() {
+		s.finish(sc)
+		return sc, nil
+	}
+	q, ok := s.queues[app]
+	if !ok {
+		q = &appQueue{}
+		s.queues[app] = q
+		s.order = append(s.order, app)
+	}
+	q.campaigns = append(q.campaigns, sc)
+	s.notify()
+	return sc, nil
+}
+
+// Close 停止调度，取消发送中的分片并等待其返回，尚未发送的分片记为未发送
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stop)
+	s.cancelStop()
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, q := range s.queues {
+		for _, sc := range q.campaigns {
+			for !sc.lc.done() {
+				sc.lc.sendNext(s.stopCtx)
+			}
+			s.finishErr(sc, fmt.Errorf("[Scheduler] 调度已关闭, err: %w", ErrClientClosed))
+		}
+	}
+	s.queues, s.order = map[string]*appQueue{}, nil
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run 轮流取出各应用的下一个分片发送
+func (s *Scheduler) run() {
+	defer s.wg.Done()
+	var sending sync.WaitGroup
+	defer sending.Wait()
+
+	for {
+		// Close 后不再取出新的分片
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+
+		sc := s.next()
+		if sc == nil {
+			select {
+			case <-s.wake:
+				continue
+			case <-s.stop:
+				return
+			}
+		}
+
+		if s.global != nil && s.global.wait(s.stopCtx) != nil {
+			s.mu.Lock()
+			sc.inflight = false
+			s.mu.Unlock()
+			return
+		}
+
+		sending.Add(1)
+		go func() {
+			defer sending.Done()
+			// Close 时取消发送中的分片
+			ctx, cancel := context.WithCancel(sc.ctx)
+			defer cancel()
+			defer context.AfterFunc(s.stopCtx, cancel)()
+			sc.lc.sendNext(ctx)
+
+			s.mu.Lock()
+			sc.inflight = false
+			if sc.lc.done() {
+				s.remove(sc)
+			}
+			s.mu.Unlock()
+			s.notify()
+		}()
+	}
+}
+
+// next 按应用轮流选出下一个可发送的活动并标记为发送中，没有时返回nil
+// ctx 已结束的活动直接将剩余分片记为未发送
+func (s *Scheduler) next() *ScheduledCampaign {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		sc, expired := s.pick()
+		if expired == nil {
+			return sc
+		}
+		for !expired.lc.done() {
+			expired.lc.sendNext(expired.ctx)
+		}
+		s.remove(expired)
+	}
+}
+
+// pick 选出下一个可发送的活动，遇到ctx已结束的活动时返回该活动
+func (s *Scheduler) pick() (sc, expired *ScheduledCampaign) {
+	for i := 0; i < len(s.order); i++ {
+		q := s.queues[s.order[(s.cursor+i)%len(s.order)]]
+		for j := 0; j < len(q.campaigns); j++ {
+			sc := q.campaigns[(q.cursor+j)%len(q.campaigns)]
+			if sc.inflight {
+				continue
+			}
+			if sc.ctx.Err() != nil {
+				return nil, sc
+			}
+			sc.inflight = true
+			q.cursor = (q.cursor + j + 1) % len(q.campaigns)
+			s.cursor = (s.cursor + i + 1) % len(s.order)
+			return sc, nil
+		}
+	}
+	return nil, nil
+}
+
+// remove 活动发送完成，从队列中删除
+func (s *Scheduler) remove(sc *ScheduledCampaign) {
+	q := s.queues[sc.App]
+	for i, x := range q.campaigns {
+		if x == sc {
+			q.campaigns = append(q.campaigns[:i], q.campaigns[i+1:]...)
+			break
+		}
+	}
+	if len(q.campaigns) == 0 {
+		delete(s.queues, sc.App)
+		for i, app := range s.order {
+			if app == sc.App {
+				s.order = append(s.order[:i], s.order[i+1:]...)
+				break
+			}
+		}
+	} else {
+		q.cursor %= len(q.campaigns)
+	}
+	if len(s.order) > 0 {
+		s.cursor %= len(s.order)
+	} else {
+		s.cursor = 0
+	}
+	s.finish(sc)
+}
+
+// finish 汇总活动的错误并通知 Wait
+func (s *Scheduler) finish(sc *ScheduledCampaign) {
+	var err error
+	if e := sc.lc.ret.err(sc.ctx); e != nil {
+		err = fmt.Errorf("[Scheduler] 应用 %s, %w", sc.App, e)
+	}
+	s.finishErr(sc, err)
+}
+
+func (s *Scheduler) finishErr(sc *ScheduledCampaign, err error) {
+	sc.err = err
+	close(sc.done)
+}
@@ -0,0 +1,304 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrSenderClosed 发送队列已关闭
+var ErrSenderClosed = errors.New("getui: 发送队列已关闭")
+
+// ErrHeldFull 免打扰时段内暂存的任务已达 SenderOptions.MaxHeld
+var ErrHeldFull = errors.New("getui: 免打扰时段暂存的任务已满")
+
+// Priority 发送优先级
+type Priority int
+
+const (
+	// PriorityTransactional 事务类推送，如验证码、订单状态，优先发送
+	PriorityTransactional Priority = iota
+	// PriorityMarketing 营销类推送，如大批量的活动推送
+	PriorityMarketing
+)
+
+// Job 发送任务，Single、List、App 任选且必选一个
+type Job struct {
+	Priority Priority
+	Single   *SingleReqBody
+	List     *ListReqBody
+	App      *AppReqBody
+	// Callback 发送完成后的回调，可为空
+	Callback func(*RspBody, error)
+	// Urgent 不受 SenderOptions.QuietHours 限制，立即发送
+	Urgent bool
+}
+
+// SenderOptions 发送队列配置
+type SenderOptions struct {
+	// Workers 并发发送的协程数 默认4
+	Workers int
+	// ReservedWorkers 其中只处理事务类推送的协程数，保证营销推送占满时事务类仍可发送 默认1
+	ReservedWorkers int
+	// QueueSize 每个优先级队列的长度 默认1024
+	QueueSize int
+	// QuietHours 免打扰时段，时段内提交的非 Urgent 任务暂存，时段结束后按提交顺序发送 默认不限制
+	QuietHours *QuietHours
+	// MaxHeld 免打扰时段内最多暂存的任务数，超出时 Submit 返回 ErrHeldFull 默认同 QueueSize
+	MaxHeld int
+}
+
+// Sender 按优先级发送的队列
+// 事务类与营销类推送分别排队，空闲协程总是先取事务类任务
+type Sender struct {
+	client        Client
+	transactional chan Job
+	marketing     chan Job
+	quietHours    *QuietHours
+	maxHeld       int
+
+	mu     sync.RWMutex
+	closed bool
+	// held 免打扰时段内暂存的任务，heldTimer 在时段结束时放入队列
+	held      []Job
+	heldTimer *time.Timer
+	pending   sync.WaitGroup
+	quit      chan struct{}
+}
+
+// NewSender 创建发送队列并启动发送协程
+func NewSender(c Client, opts SenderOptions) *Sender {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.ReservedWorkers <= 0 {
+		opts.ReservedWorkers = 1
+	}
+	if opts.ReservedWorkers >= opts.Workers {
+		opts.ReservedWorkers = opts.Workers - 1
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	if opts.MaxHeld <= 0 {
+		opts.MaxHeld = opts.QueueSize
+	}
+
+	s := &Sender{
+		client:        c,
+		transactional: make(chan Job, opts.QueueSize),
+		marketing:     make(chan Job, opts.QueueSize),
+		quietHours:    opts.QuietHours,
+		maxHeld:       opts.MaxHeld,
+		quit:          make(chan struct{}),
+	}
+	for i := 0; i < opts.Workers; i++ {
+		go s.work(i < opts.ReservedWorkers)
+	}
+	return s
+}
+
+// Submit 提交发送任务，队列满时阻塞直到有空位或ctx结束
+// 免打扰时段内的非 Urgent 任务立即返回，暂存到时段结束；暂存已满时返回 ErrHeldFull
+func (s *Sender) Submit(ctx context.Context, job Job) error {
+	if job.Single == nil && job.List == nil && job.App == nil {
+		return fmt.Errorf("[Submit] 错误的任务, Single、List、App 任选且必选一个")
+	}
+
+	lane := s.marketing
+	if job.Priority == PriorityTransactional {
+		lane = s.transactional
+	}
+
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return ErrSenderClosed
+	}
+	s.pending.Add(1)
+	s.mu.RUnlock()
+
+	if !job.Urgent && s.quietHours != nil {
+		if d := s.quietHours.Until(time.Now()); d > 0 {
+			if err := s.hold(job, d); err != nil {
+				s.pending.Done()
+				return fmt.Errorf("[Submit] %w", err)
+			}
+			return nil
+		}
+	}
+
+	select {
+	case lane <- job:
+		return nil
+	case <-s.quit:
+		s.pending.Done()
+		return ErrSenderClosed
+	case <-ctx.Done():
+		s.pending.Done()
+		return ctx.Err()
+	}
+}
+
+// Close 不再接受新的任务，等待已提交的任务发送完成（或ctx结束）后停止发送协程
+// 免打扰时段内暂存的任务同样需要等待发送；应在 Client.Shutdown 之前调用
+// ctx 结束时同样停止发送协程，尚未发送的任务不再发送，其 Callback 收到 ErrSenderClosed
+func (s *Sender) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		close(s.quit)
+		return nil
+	case <-ctx.Done():
+		close(s.quit)
+		s.drop()
+		return fmt.Errorf("[Close] 等待队列中的任务超时, err: %w", ctx.Err())
+	}
+}
+
+// drop 关闭超时后丢弃暂存与排队中的任务
+func (s *Sender) drop() {
+	s.mu.Lock()
+	held := s.held
+	s.held = nil
+	if s.heldTimer != nil {
+		s.heldTimer.Stop()
+		s.heldTimer = nil
+	}
+	s.mu.Unlock()
+
+	for _, job := range held {
+		s.abandon(job)
+	}
+	for {
+		select {
+		case job := <-s.transactional:
+			s.abandon(job)
+		case job := <-s.marketing:
+			s.abandon(job)
+		default:
+			return
+		}
+	}
+}
+
+// abandon 任务不再发送，回调 ErrSenderClosed
+func (s *Sender) abandon(job Job) {
+	defer s.pending.Done()
+	if job.Callback != nil {
+		job.Callback(nil, ErrSenderClosed)
+	}
+}
+
+// hold 暂存任务，d 后放入队列
+func (s *Sender) hold(job Job, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.held) >= s.maxHeld {
+		return fmt.Errorf("已暂存 %d 个任务, err: %w", len(s.held), ErrHeldFull)
+	}
+	s.held = append(s.held, job)
+	if s.heldTimer == nil {
+		s.heldTimer = time.AfterFunc(d, s.release)
+	}
+	return nil
+}
+
+// release 免打扰时段结束，按提交顺序将暂存的任务放入队列
+func (s *Sender) release() {
+	s.mu.Lock()
+	// 关闭超时后暂存的任务已丢弃
+	if s.heldTimer == nil {
+		s.mu.Unlock()
+		return
+	}
+	// 定时器提前触发时等到时段结束
+	if d := s.quietHours.Until(time.Now()); d > 0 {
+		s.heldTimer.Reset(d)
+		s.mu.Unlock()
+		return
+	}
+	held := s.held
+	s.held = nil
+	s.heldTimer = nil
+	s.mu.Unlock()
+
+	for _, job := range held {
+		lane := s.marketing
+		if job.Priority == PriorityTransactional {
+			lane = s.transactional
+		}
+		select {
+		case lane <- job:
+		case <-s.quit:
+			s.abandon(job)
+		}
+	}
+}
+
+// work 发送协程，reserved 为true时只处理事务类任务
+func (s *Sender) work(reserved bool) {
+	marketing := s.marketing
+	if reserved {
+		marketing = nil
+	}
+
+	for {
+		// 优先取事务类任务
+		select {
+		case job := <-s.transactional:
+			s.run(job)
+			continue
+		default:
+		}
+
+		select {
+		case job := <-s.transactional:
+			s.run(job)
+		case job := <-marketing:
+			s.run(job)
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *Sender) run(job Job) {
+	defer s.pending.Done()
+	sendJob(s.client, job)
+}
+
+// sendJob 按任务类型发送，发送完成后调用 Callback
+func sendJob(c Client, job Job) (*RspBody, error) {
+	var ret *RspBody
+	var err error
+	switch {
+	case job.Single != nil:
+		ret, err = c.PushToSingle(*job.Single)
+	case job.List != nil:
+		ret, err = c.PushToList(*job.List)
+	default:
+		ret, err = c.PushToApp(*job.App)
+	}
+
+	if job.Callback != nil {
+		job.Callback(ret, err)
+	}
+	return ret, err
+}
@@ -0,0 +1,67 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrClientClosed 客户端已调用 Shutdown，不再接受新的请求
+var ErrClientClosed = errors.New("getui: 客户端已关闭")
+
+// begin 登记一个进行中的请求，客户端已关闭时返回 ErrClientClosed
+func (c *client) begin() error {
+	c.lifeMu.Lock()
+	defer c.lifeMu.Unlock()
+	if c.closed {
+		return ErrClientClosed
+	}
+	c.inflight.Add(1)
+	return nil
+}
+
+// Shutdown 优雅关闭
+// 不再接受新的请求，等待进行中的请求完成（或ctx结束），停止token自动刷新，最后关闭鉴权；
+// 配置了 TokenCache 时token由各副本共享，不调用 CloseAuth
+func (c *client) Shutdown(ctx context.Context) error {
+
+	c.lifeMu.Lock()
+	if c.closed {
+		c.lifeMu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.lifeMu.Unlock()
+
+	close(c.stopRefresh)
+
+	// 等待进行中的请求
+	done := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("[Shutdown] 等待进行中的请求超时, err: %w", ctx.Err())
+	}
+
+	err := c.shutdownApps(ctx)
+	if err != nil {
+		return fmt.Errorf("[Shutdown] 关闭 WithApp 指定的应用失败, err: %w", err)
+	}
+
+	// LazyAuth 时可能从未申请过token
+	if c.TokenCache != nil || (c.LazyAuth && len(c.AuthToken()) == 0) {
+		return nil
+	}
+
+	_, err = c.CloseAuth()
+	if err != nil {
+		return fmt.Errorf("[Shutdown] 关闭鉴权失败, err: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package getui
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Signer 鉴权签名
+// 默认使用 sha256(appkey+timestamp+mastersecret) 在本地计算；
+// MasterSecret 托管在 HSM/KMS 时，可自行实现该接口由外部服务计算签名，进程内无需持有 MasterSecret
+type Signer interface {
+	Sign(ctx context.Context, appKey, timestamp string) (string, error)
+}
+
+// SignerFunc 函数形式的 Signer
+type SignerFunc func(ctx context.Context, appKey, timestamp string) (string, error)
+
+// Sign 实现 Signer
+func (f SignerFunc) Sign(ctx context.Context, appKey, timestamp string) (string, error) {
+	return f(ctx, appKey, timestamp)
+}
+
+// NewSHA256Signer 使用本地 MasterSecret 计算签名
+func NewSHA256Signer(masterSecret string) Signer {
+	return SignerFunc(func(ctx context.Context, appKey, timestamp string) (string, error) {
+		sign := sha256.Sum256([]byte(appKey + timestamp + masterSecret))
+		return fmt.Sprintf("%x", sign), nil
+	})
+}
+
+// NewMD5Signer 使用本地 MasterSecret 计算 md5(appkey+timestamp+mastersecret) 签名
+// 仅用于仍使用旧版签名的应用，新应用使用 NewSHA256Signer
+func NewMD5Signer(masterSecret string) Signer {
+	return SignerFunc(func(ctx context.Context, appKey, timestamp string) (string, error) {
+		sign := md5.Sum([]byte(appKey + timestamp + masterSecret))
+		return fmt.Sprintf("%x", sign), nil
+	})
+}
+
+// SignAlgorithm 本地计算签名的算法
+type SignAlgorithm string
+
+const (
+	// SignSHA256 sha256签名，默认
+	SignSHA256 SignAlgorithm = "sha256"
+	// SignMD5 旧版应用使用的md5签名
+	SignMD5 SignAlgorithm = "md5"
+)
+
+// newSigner 按算法创建本地签名，algorithm 为空时使用 SignSHA256
+func newSigner(algorithm SignAlgorithm, masterSecret string) (Signer, error) {
+	switch algorithm {
+	case "", SignSHA256:
+		return NewSHA256Signer(masterSecret), nil
+	case SignMD5:
+		return NewMD5Signer(masterSecret), nil
+	}
+	return nil, fmt.Errorf("不支持的签名算法 %q", algorithm)
+}
+
+// authSignBody auth_sign 请求body
+type authSignBody struct {
+	AppKey    string `json:"appkey"`
+	Timestamp string `json:"timestamp"`
+	Sign      string `json:"sign"`
+}
+
+// authTimestamp 签名使用的毫秒时间戳
+func authTimestamp(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+}
+
+// SignDebug 签名调试信息
+type SignDebug struct {
+	AppKey    string
+	Timestamp string
+	// Algorithm 签名算法
+	Algorithm SignAlgorithm
+	// Sign 按 Algorithm 计算的 appkey+timestamp+mastersecret 摘要的十六进制
+	Sign string
+	// Body 发送给 auth_sign 的完整JSON
+	Body []byte
+}
+
+// DebugSign 计算签名及 auth_sign 请求body，用于排查 sign_error
+// timestamp 为空时使用当前时间；algorithm 为空时使用 SignSHA256，需与 InitParams.SignAlgorithm 一致；
+// 可将结果与实际发送的请求逐项比对
+func DebugSign(appKey, masterSecret, timestamp string, algorithm SignAlgorithm) (*SignDebug, error) {
+	if len(algorithm) == 0 {
+		algorithm = SignSHA256
+	}
+	signer, err := newSigner(algorithm, masterSecret)
+	if err != nil {
+		return nil, fmt.Errorf("[DebugSign] %w", err)
+	}
+	if len(timestamp) == 0 {
+		timestamp = authTimestamp(time.Now())
+	}
+	if _, err := strconv.ParseInt(timestamp, 10, 64); err != nil {
+		return nil, fmt.Errorf("[DebugSign] 错误的时间戳 %q, 需为毫秒时间戳", timestamp)
+	}
+
+	sign, err := signer.Sign(context.Background(), appKey, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("[DebugSign] 计算签名失败, err: %w", err)
+	}
+
+	body, err := json.Marshal(authSignBody{AppKey: appKey, Timestamp: timestamp, Sign: sign})
+	if err != nil {
+		return nil, fmt.Errorf("[DebugSign] 序列化请求body失败, err: %w", err)
+	}
+
+	return &SignDebug{AppKey: appKey, Timestamp: timestamp, Algorithm: algorithm, Sign: sign, Body: body}, nil
+}
@@ -0,0 +1,124 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotSilent 静默推送中设置了用户可见或静默推送不支持的字段
+var ErrNotSilent = errors.New("getui: 静默推送不能包含可见内容")
+
+// Transmission 透传消息内容
+type Transmission struct {
+	TransmissionType    bool   `json:"transmission_type"`
+	TransmissionContent string `json:"transmission_content"`
+}
+
+// silentReqBody 静默推送请求body，push_info 只带 content-available
+type silentReqBody struct {
+	Message      Message        `json:"message"`
+	Transmission Transmission   `json:"transmission"`
+	CID          string         `json:"cid,omitempty"`
+	Alias        string         `json:"alias,omitempty"`
+	RequestID    string         `json:"requestid"`
+	PushInfo     silentPushInfo `json:"push_info"`
+}
+
+type silentPushInfo struct {
+	Aps struct {
+		ContentAvailable int `json:"content-available"`
+	} `json:"aps"`
+}
+
+// validateSilent 检查单推body中没有标题、内容、角标等可见字段
+func validateSilent(body SingleReqBody) error {
+	visible := []struct{ field, value string }{
+		{"notification.style.title", body.Notification.Style.Title},
+		{"notification.style.text", body.Notification.Style.Text},
+		{"push_info.aps.alert.title", body.PushInfo.Aps.Alert.Title},
+		{"push_info.aps.alert.body", body.PushInfo.Aps.Alert.Body},
+		{"push_info.aps.autoBadge", body.PushInfo.Aps.AutoBadge},
+		{"push_info.aps.sound", body.PushInfo.Aps.Sound},
+	}
+	for _, v := range visible {
+		if len(v.value) > 0 {
+			return fmt.Errorf("%s 不能设置, err: %w", v.field, ErrNotSilent)
+		}
+	}
+	if len(body.PushInfo.Multimedia) > 0 || body.PushInfo.Media != nil {
+		return fmt.Errorf("push_info.multimedia、media 不能设置, err: %w", ErrNotSilent)
+	}
+	// 静默推送不会下发短信补量，也不会回调回执地址
+	if body.SMS != nil {
+		return fmt.Errorf("sms_message 不能设置, err: %w", ErrNotSilent)
+	}
+	if len(body.CallbackURL) > 0 {
+		return fmt.Errorf("callback_url 不能设置, err: %w", ErrNotSilent)
+	}
+	return nil
+}
+
+// SilentPush 静默推送，用于触发客户端后台同步数据
+// Android 以透传消息下发 body.Notification.TransmissionContent，不启动应用；
+// iOS 只带 content-available=1，没有提示、声音与角标。
+// body 中设置了标题、内容、角标、提示音、多媒体，或设置了短信补量、回执地址时返回 ErrNotSilent
+func (c *client) SilentPush(ctx context.Context, body SingleReqBody) (ret *RspBody, err error) {
+
+	c, err = c.forApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[SilentPush] %w", err)
+	}
+	if len(body.CID) == 0 && len(body.Alias) == 0 {
+		return nil, fmt.Errorf("[SilentPush] 错误的目标设备, cid 与 alias 任选且必选一个, err: %w", ErrInvalidTarget)
+	}
+	if len(body.CID) > 0 {
+		err = c.checkValid(ctx, "SilentPush", c.validateCIDs(body.CID))
+		if err != nil {
+			return nil, fmt.Errorf("[SilentPush] %w", err)
+		}
+	}
+	err = validateSilent(body)
+	if err != nil {
+		return nil, fmt.Errorf("[SilentPush] %w", err)
+	}
+	c.applyDefaults(&body.Message, &body.Notification, nil)
+	err = c.checkValid(ctx, "SilentPush", validateOfflineExpire(body.Message.OfflineExpireTime))
+	if err != nil {
+		return nil, fmt.Errorf("[SilentPush] %w", err)
+	}
+
+	silent := silentReqBody{
+		Message: body.Message,
+		Transmission: Transmission{
+			TransmissionContent: body.Notification.TransmissionContent,
+		},
+		CID:   body.CID,
+		Alias: body.Alias,
+	}
+	silent.Message.MsgType = "transmission"
+	silent.PushInfo.Aps.ContentAvailable = 1
+
+	key, err := c.checkDuplicate([]string{body.CID, body.Alias}, silent.Message, silent.Transmission)
+	if err != nil {
+		return nil, fmt.Errorf("[SilentPush] 推送去重, err: %w", err)
+	}
+	defer func() { c.releaseDuplicate(key, err) }()
+
+	silent.Message.AppKey = c.appKey()
+	silent.RequestID = requestID(ctx, body.RequestID)
+
+	start := time.Now()
+	ret, err = doRequest[RspBody](ctx, c, "POST", "push_single", silent)
+	c.logPush("push_single", start, silent.RequestID, 1, ret, err)
+	c.savePush("push_single", start, silent.RequestID, "", singleTargets(body), 1, ret, err)
+	c.auditPush(ctx, "push_single", start, silent.RequestID, singleTargets(body), 1, ret, err)
+	c.observeDeadCID(ctx, body.CID, ret)
+	if err != nil {
+		return nil, fmt.Errorf("[SilentPush] 发送 静默推送 失败, requestid: %s, err: %w", silent.RequestID, err)
+	}
+	ret.RequestID = silent.RequestID
+
+	return
+}
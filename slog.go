@@ -0,0 +1,42 @@
+package getui
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogLogger log/slog 日志适配器
+type SlogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger 创建 slog 日志适配器，l 为空时使用 slog.Default()
+// 日志字段（endpoint、requestid、taskid、result 等）转为 slog.Attr，zap 适配器见 zaplog 包
+func NewSlogLogger(l *slog.Logger) *SlogLogger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return &SlogLogger{l: l}
+}
+
+// Log 实现 Logger
+func (s *SlogLogger) Log(ctx context.Context, level LogLevel, msg string, fields ...LogField) {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		attrs = append(attrs, slog.Any(f.Key, f.Value))
+	}
+	s.l.LogAttrs(ctx, slogLevel(level), msg, attrs...)
+}
+
+func slogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LogDebug:
+		return slog.LevelDebug
+	case LogInfo:
+		return slog.LevelInfo
+	case LogWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
@@ -0,0 +1,71 @@
+package getui
+
+import (
+	"fmt"
+	"time"
+)
+
+// SMSInfo 短信补量，推送在 OfflineSendTime 内未送达时改发短信
+// 模板需先在个推后台申请并审核通过
+type SMSInfo struct {
+	// TemplateID 短信模板id
+	TemplateID string `json:"sms_template_id"`
+	// Params 模板中的变量
+	Params map[string]string `json:"sms_content_param,omitempty"`
+	// OfflineSendTime 推送离线多久后补发短信 单位毫秒，建议通过 SetOfflineDelay 设置
+	OfflineSendTime int64 `json:"offline_send_time"`
+}
+
+// NewSMSInfo 创建短信补量信息，delay 为推送未送达后补发短信的等待时长
+func NewSMSInfo(templateID string, params map[string]string, delay time.Duration) (*SMSInfo, error) {
+	s := &SMSInfo{TemplateID: templateID, Params: params}
+	if err := s.SetOfflineDelay(delay); err != nil {
+		return nil, fmt.Errorf("[NewSMSInfo] %w", err)
+	}
+	return s, nil
+}
+
+// SetOfflineDelay 设置推送未送达后补发短信的等待时长，需在 (0, MaxOfflineExpire] 之间
+func (s *SMSInfo) SetOfflineDelay(d time.Duration) error {
+	if d <= 0 || d > MaxOfflineExpire {
+		return fmt.Errorf("[SetOfflineDelay] 短信补发等待时长 %s 超出范围, 需在 (0, %s] 之间", d, MaxOfflineExpire)
+	}
+	s.OfflineSendTime = int64(d / time.Millisecond)
+	return nil
+}
+
+// clone 深拷贝
+func (s *SMSInfo) clone() *SMSInfo {
+	if s == nil {
+		return nil
+	}
+	ret := *s
+	if s.Params != nil {
+		ret.Params = make(map[string]string, len(s.Params))
+		for k, v := range s.Params {
+			ret.Params[k] = v
+		}
+	}
+	return &ret
+}
+
+// validateSMS 校验短信补量信息，sms 为空时不校验
+// 短信在推送离线期间补发，需开启离线存储，且等待时长不超过离线时长（单位毫秒，0 表示未设置）
+func validateSMS(sms *SMSInfo, isOffline bool, offlineExpire int64) error {
+	if sms == nil {
+		return nil
+	}
+	if len(sms.TemplateID) == 0 {
+		return fmt.Errorf("短信补量的模板id不能为空")
+	}
+	if sms.OfflineSendTime <= 0 || time.Duration(sms.OfflineSendTime)*time.Millisecond > MaxOfflineExpire {
+		return fmt.Errorf("短信补发等待时长 %dms 超出范围, 需在 (0, %d] 毫秒之间", sms.OfflineSendTime, int64(MaxOfflineExpire/time.Millisecond))
+	}
+	if !isOffline {
+		return fmt.Errorf("短信补量需开启离线存储 is_offline")
+	}
+	if offlineExpire > 0 && sms.OfflineSendTime > offlineExpire {
+		return fmt.Errorf("短信补发等待时长 %dms 超过离线时长 %dms, 短信不会补发", sms.OfflineSendTime, offlineExpire)
+	}
+	return nil
+}
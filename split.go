@@ -0,0 +1,154 @@
+package getui
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Variant A/B测试的一个版本
+type Variant struct {
+	// Name 版本名，如 "A"，同一次测试内唯一
+	Name string
+	// Body 该版本的消息，CID 由 SplitPush 填写；GroupName 为空时使用 Name
+	Body ListReqBody
+}
+
+// SplitGroup 一个版本的发送结果
+type SplitGroup struct {
+	Variant   string
+	GroupName string
+	// TaskID 该版本的消息共同体，可用于 PushResult 查询点击率
+	TaskID  string
+	Targets []string
+	Result  *ChunkedResult
+	Err     error
+}
+
+// SplitResult A/B测试的发送结果
+type SplitResult struct {
+	Groups []SplitGroup
+	// Assignment cid 到版本名的映射
+	Assignment map[string]string
+}
+
+// TaskIDs 版本名到taskid的映射，未发送成功的版本不包含在内
+func (r *SplitResult) TaskIDs() map[string]string {
+	ret := map[string]string{}
+	for _, g := range r.Groups {
+		if len(g.TaskID) > 0 {
+			ret[g.Variant] = g.TaskID
+		}
+	}
+	return ret
+}
+
+// SplitAudience 按 ratios 将 audience 确定性地分配到各版本，返回每个版本的cid
+// 同一个cid在版本名相同的测试中总是分到同一组；ratios 不需要加起来为1
+func SplitAudience(audience []string, variants []Variant, ratios []float64) ([][]string, error) {
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("没有版本")
+	}
+	if len(ratios) != len(variants) {
+		return nil, fmt.Errorf("比例数 %d 与版本数 %d 不一致", len(ratios), len(variants))
+	}
+
+	names := make([]string, len(variants))
+	seen := map[string]bool{}
+	total := 0.0
+	for i, v := range variants {
+		if len(v.Name) == 0 || seen[v.Name] {
+			return nil, fmt.Errorf("版本名 %q 为空或重复", v.Name)
+		}
+		seen[v.Name] = true
+		names[i] = v.Name
+		if ratios[i] < 0 || math.IsNaN(ratios[i]) || math.IsInf(ratios[i], 0) {
+			return nil, fmt.Errorf("版本 %s 的比例 %v 无效", v.Name, ratios[i])
+		}
+		total += ratios[i]
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("比例之和需大于0")
+	}
+
+	// 各版本的累计比例上限，last 为最后一个比例大于0的版本，吸收浮点误差
+	bounds := make([]float64, len(ratios))
+	acc, last := 0.0, 0
+	for i, r := range ratios {
+		acc += r / total
+		bounds[i] = acc
+		if r > 0 {
+			last = i
+		}
+	}
+
+	salt := strings.Join(names, "\x00") + "\x00"
+	groups := make([][]string, len(variants))
+	for _, cid := range audience {
+		sum := sha256.Sum256([]byte(salt + cid))
+		x := float64(binary.BigEndian.Uint64(sum[:8])) / math.MaxUint64
+
+		i := 0
+		for i < last && x >= bounds[i] {
+			i++
+		}
+		groups[i] = append(groups[i], cid)
+	}
+	return groups, nil
+}
+
+// SplitPush A/B测试，按 ratios 确定性地划分 audience，各版本以自己的 group_name 与 taskid 分片发送
+// 返回的 SplitResult 记录了每个cid的版本与各版本的taskid，之后可通过 PushResult 比较点击率；
+// 部分版本失败时仍返回结果，并返回第一个错误
+func (c *client) SplitPush(ctx context.Context, audience []string, variants []Variant, ratios []float64) (*SplitResult, error) {
+
+	c, err := c.forApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[SplitPush] %w", err)
+	}
+
+	if len(audience) == 0 {
+		return nil, fmt.Errorf("[SplitPush] 错误的目标, audience 不能为空, err: %w", ErrInvalidTarget)
+	}
+	err = c.checkValid(ctx, "SplitPush", c.validateCIDs(audience...))
+	if err != nil {
+		return nil, fmt.Errorf("[SplitPush] %w", err)
+	}
+	groups, err := SplitAudience(audience, variants, ratios)
+	if err != nil {
+		return nil, fmt.Errorf("[SplitPush] %w", err)
+	}
+
+	ret := &SplitResult{Assignment: make(map[string]string, len(audience))}
+	var firstErr error
+	for i, v := range variants {
+		g := SplitGroup{Variant: v.Name, GroupName: v.Body.GroupName, Targets: groups[i]}
+		if len(g.GroupName) == 0 {
+			g.GroupName = v.Name
+		}
+		for _, cid := range g.Targets {
+			ret.Assignment[cid] = v.Name
+		}
+
+		if len(g.Targets) > 0 {
+			body := v.Body
+			body.CID = g.Targets
+			body.Alias = ""
+			body.TaskID = ""
+			body.GroupName = g.GroupName
+			g.Result, g.Err = c.PushToListChunked(ctx, body, 0)
+			if g.Result != nil && len(g.Result.Sent) > 0 {
+				g.TaskID = g.Result.Sent[0].Ret.TaskID
+			}
+			if g.Err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("[SplitPush] 版本 %s 发送失败, err: %w", v.Name, g.Err)
+			}
+		}
+		ret.Groups = append(ret.Groups, g)
+	}
+
+	return ret, firstErr
+}
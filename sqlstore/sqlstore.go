@@ -0,0 +1,246 @@
+// Package sqlstore 基于 database/sql 的 getui.Store 实现
+// 只使用通用的SQL语法，时间以毫秒时间戳保存，适用于 MySQL、PostgreSQL、SQLite 等
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/printfcoder/getui"
+)
+
+// DefaultTablePrefix 默认表名前缀
+const DefaultTablePrefix = "getui_"
+
+// Placeholder 参数占位符风格
+type Placeholder int
+
+const (
+	// Question MySQL、SQLite 使用的 ?
+	Question Placeholder = iota
+	// Dollar PostgreSQL 使用的 $1 $2
+	Dollar
+)
+
+// Options 配置
+type Options struct {
+	// TablePrefix 表名前缀 默认 DefaultTablePrefix
+	TablePrefix string
+	// Placeholder 参数占位符风格 默认 Question
+	Placeholder Placeholder
+}
+
+// Store SQL实现的 getui.Store
+type Store struct {
+	db   *sql.DB
+	opts Options
+}
+
+// New 创建SQL Store，表需要事先通过 CreateTables 或自行执行 Schema 创建
+func New(db *sql.DB, opts Options) *Store {
+	if len(opts.TablePrefix) == 0 {
+		opts.TablePrefix = DefaultTablePrefix
+	}
+	return &Store{db: db, opts: opts}
+}
+
+// Schema 建表语句
+// push_results 的 group_name 列与 request_id、group_name 索引为后加，已有的表需要自行执行 ALTER TABLE 补充
+func (s *Store) Schema() []string {
+	p := s.opts.TablePrefix
+	return []string{
+		`CREATE TABLE IF NOT EXISTS ` + p + `push_results (
+	task_id VARCHAR(64) NOT NULL,
+	request_id VARCHAR(64) NOT NULL,
+	group_name VARCHAR(64) NOT NULL,
+	endpoint VARCHAR(32) NOT NULL,
+	targets TEXT NOT NULL,
+	target_count INTEGER NOT NULL,
+	result VARCHAR(32) NOT NULL,
+	status VARCHAR(32) NOT NULL,
+	sent_at BIGINT NOT NULL
+)`,
+		`CREATE INDEX ` + p + `push_results_task_id ON ` + p + `push_results (task_id)`,
+		`CREATE INDEX ` + p + `push_results_sent_at ON ` + p + `push_results (sent_at)`,
+		`CREATE INDEX ` + p + `push_results_request_id ON ` + p + `push_results (request_id)`,
+		`CREATE INDEX ` + p + `push_results_group_name ON ` + p + `push_results (group_name)`,
+		`CREATE TABLE IF NOT EXISTS ` + p + `receipts (
+	app_id VARCHAR(64) NOT NULL,
+	cid VARCHAR(64) NOT NULL,
+	alias VARCHAR(128) NOT NULL,
+	task_id VARCHAR(64) NOT NULL,
+	msg_id VARCHAR(64) NOT NULL,
+	action_id VARCHAR(16) NOT NULL,
+	code VARCHAR(16) NOT NULL,
+	description VARCHAR(255) NOT NULL,
+	recv_time BIGINT NOT NULL
+)`,
+		`CREATE INDEX ` + p + `receipts_task_id ON ` + p + `receipts (task_id)`,
+		`CREATE INDEX ` + p + `receipts_recv_time ON ` + p + `receipts (recv_time)`,
+	}
+}
+
+// CreateTables 执行 Schema 建表
+// 索引已存在时部分数据库会报错，生产环境建议使用自己的迁移工具执行 Schema
+func (s *Store) CreateTables(ctx context.Context) error {
+	for _, stmt := range s.Schema() {
+		_, err := s.db.ExecContext(ctx, stmt)
+		if err != nil {
+			return fmt.Errorf("[sqlstore.CreateTables] 建表失败, err: %s", err)
+		}
+	}
+	return nil
+}
+
+// SavePushResult 实现 getui.Store
+func (s *Store) SavePushResult(ctx context.Context, r getui.PushRecord) error {
+	targets, err := json.Marshal(r.Targets)
+	if err != nil {
+		return fmt.Errorf("[sqlstore.SavePushResult] 推送目标序列化失败, err: %s", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, s.insert("push_results",
+		"task_id", "request_id", "group_name", "endpoint", "targets", "target_count", "result", "status", "sent_at"),
+		r.TaskID, r.RequestID, r.GroupName, r.Endpoint, string(targets), r.TargetCount, r.Result, r.Status, toMs(r.SentAt))
+	if err != nil {
+		return fmt.Errorf("[sqlstore.SavePushResult] 保存发送结果失败, err: %s", err)
+	}
+	return nil
+}
+
+// SaveReceipt 实现 getui.Store
+func (s *Store) SaveReceipt(ctx context.Context, r getui.Receipt) error {
+	_, err := s.db.ExecContext(ctx, s.insert("receipts",
+		"app_id", "cid", "alias", "task_id", "msg_id", "action_id", "code", "description", "recv_time"),
+		r.AppID, r.CID, r.Alias, r.TaskID, r.MsgID, r.ActionID, r.Code, r.Desc, r.RecvTime)
+	if err != nil {
+		return fmt.Errorf("[sqlstore.SaveReceipt] 保存回执失败, err: %s", err)
+	}
+	return nil
+}
+
+// PushResults 实现 getui.Store
+func (s *Store) PushResults(ctx context.Context, q getui.StoreQuery) ([]getui.PushRecord, error) {
+	query, args := s.selectQuery("push_results",
+		"task_id, request_id, group_name, endpoint, targets, target_count, result, status, sent_at", "sent_at", q)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[sqlstore.PushResults] 查询发送结果失败, err: %s", err)
+	}
+	defer rows.Close()
+
+	var ret []getui.PushRecord
+	for rows.Next() {
+		var r getui.PushRecord
+		var targets string
+		var sentAt int64
+		err = rows.Scan(&r.TaskID, &r.RequestID, &r.GroupName, &r.Endpoint, &targets, &r.TargetCount, &r.Result, &r.Status, &sentAt)
+		if err != nil {
+			return nil, fmt.Errorf("[sqlstore.PushResults] 读取发送结果失败, err: %s", err)
+		}
+		if err = json.Unmarshal([]byte(targets), &r.Targets); err != nil {
+			return nil, fmt.Errorf("[sqlstore.PushResults] 推送目标的JSON无法解析, err: %s", err)
+		}
+		r.SentAt = fromMs(sentAt)
+		ret = append(ret, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("[sqlstore.PushResults] 读取发送结果失败, err: %s", err)
+	}
+	return ret, nil
+}
+
+// Receipts 实现 getui.Store
+func (s *Store) Receipts(ctx context.Context, q getui.StoreQuery) ([]getui.Receipt, error) {
+	// 回执表没有 request_id、group_name 列
+	q.RequestID, q.GroupName = "", ""
+	query, args := s.selectQuery("receipts",
+		"app_id, cid, alias, task_id, msg_id, action_id, code, description, recv_time", "recv_time", q)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("[sqlstore.Receipts] 查询回执失败, err: %s", err)
+	}
+	defer rows.Close()
+
+	var ret []getui.Receipt
+	for rows.Next() {
+		var r getui.Receipt
+		err = rows.Scan(&r.AppID, &r.CID, &r.Alias, &r.TaskID, &r.MsgID, &r.ActionID, &r.Code, &r.Desc, &r.RecvTime)
+		if err != nil {
+			return nil, fmt.Errorf("[sqlstore.Receipts] 读取回执失败, err: %s", err)
+		}
+		ret = append(ret, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("[sqlstore.Receipts] 读取回执失败, err: %s", err)
+	}
+	return ret, nil
+}
+
+// insert 构造insert语句
+func (s *Store) insert(table string, columns ...string) string {
+	marks := make([]string, len(columns))
+	for i := range columns {
+		marks[i] = s.mark(i + 1)
+	}
+	return "INSERT INTO " + s.opts.TablePrefix + table + " (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(marks, ", ") + ")"
+}
+
+// selectQuery 按查询条件构造select语句，timeColumn 为过滤与排序使用的时间列
+func (s *Store) selectQuery(table, columns, timeColumn string, q getui.StoreQuery) (string, []interface{}) {
+	var where []string
+	var args []interface{}
+	if len(q.TaskID) > 0 {
+		args = append(args, q.TaskID)
+		where = append(where, "task_id = "+s.mark(len(args)))
+	}
+	if len(q.RequestID) > 0 {
+		args = append(args, q.RequestID)
+		where = append(where, "request_id = "+s.mark(len(args)))
+	}
+	if len(q.GroupName) > 0 {
+		args = append(args, q.GroupName)
+		where = append(where, "group_name = "+s.mark(len(args)))
+	}
+	if !q.Since.IsZero() {
+		args = append(args, toMs(q.Since))
+		where = append(where, timeColumn+" >= "+s.mark(len(args)))
+	}
+	if !q.Until.IsZero() {
+		args = append(args, toMs(q.Until))
+		where = append(where, timeColumn+" < "+s.mark(len(args)))
+	}
+
+	query := "SELECT " + columns + " FROM " + s.opts.TablePrefix + table
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY " + timeColumn
+	if q.Limit > 0 {
+		query += " LIMIT " + strconv.Itoa(q.Limit)
+	}
+	return query, args
+}
+
+// mark 第i个参数的占位符，从1开始
+func (s *Store) mark(i int) string {
+	if s.opts.Placeholder == Dollar {
+		return "$" + strconv.Itoa(i)
+	}
+	return "?"
+}
+
+func toMs(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func fromMs(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
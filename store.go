@@ -0,0 +1,243 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PushRecord 一次推送的发送结果
+type PushRecord struct {
+	TaskID    string `json:"taskid"`
+	RequestID string `json:"requestid,omitempty"`
+	// GroupName 任务组名，只有 push_list 填写
+	GroupName string `json:"group_name,omitempty"`
+	// Endpoint push_single、push_list、push_app
+	Endpoint string `json:"endpoint"`
+	// Targets 推送目标 cid 或 alias，toapp 为空
+	Targets     []string  `json:"targets,omitempty"`
+	TargetCount int       `json:"target_count"`
+	Result      string    `json:"result"`
+	Status      string    `json:"status,omitempty"`
+	SentAt      time.Time `json:"sent_at"`
+}
+
+// StoreQuery 查询条件，零值字段不参与过滤
+type StoreQuery struct {
+	TaskID string
+	// RequestID、GroupName 只用于过滤发送结果，回执中没有这两项
+	RequestID string
+	GroupName string
+	// Since、Until 按发送时间或回执时间过滤，左闭右开
+	Since time.Time
+	Until time.Time
+	// Limit 最多返回条数 默认0不限制
+	Limit int
+}
+
+// Store 推送结果与回执的持久化
+// 配置在 InitParams.Store 时，推送成功后自动保存发送结果；
+// 配置在 ReceiptHandlerOptions.Store 时，自动保存收到的回执。
+// 内存实现见 NewMemoryStore，SQL实现见 sqlstore 包
+type Store interface {
+	SavePushResult(ctx context.Context, r PushRecord) error
+	SaveReceipt(ctx context.Context, r Receipt) error
+	// PushResults 按发送时间升序返回发送结果
+	PushResults(ctx context.Context, q StoreQuery) ([]PushRecord, error)
+	// Receipts 按回执时间升序返回回执
+	Receipts(ctx context.Context, q StoreQuery) ([]Receipt, error)
+}
+
+// match 是否满足查询条件
+func (q StoreQuery) match(taskID string, t time.Time) bool {
+	if len(q.TaskID) > 0 && q.TaskID != taskID {
+		return false
+	}
+	if !q.Since.IsZero() && t.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !t.Before(q.Until) {
+		return false
+	}
+	return true
+}
+
+// matchPush 发送结果是否满足查询条件
+func (q StoreQuery) matchPush(r PushRecord) bool {
+	if len(q.RequestID) > 0 && q.RequestID != r.RequestID {
+		return false
+	}
+	if len(q.GroupName) > 0 && q.GroupName != r.GroupName {
+		return false
+	}
+	return q.match(r.TaskID, r.SentAt)
+}
+
+// MemoryStore 内存中的 Store，进程重启后数据丢失，适合测试与单机工具
+type MemoryStore struct {
+	mu       sync.RWMutex
+	pushes   []PushRecord
+	receipts []Receipt
+}
+
+// NewMemoryStore 创建内存 Store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// SavePushResult 实现 Store
+func (m *MemoryStore) SavePushResult(ctx context.Context, r PushRecord) error {
+	r.Targets = append([]string(nil), r.Targets...)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pushes = append(m.pushes, r)
+	return nil
+}
+
+// SaveReceipt 实现 Store
+func (m *MemoryStore) SaveReceipt(ctx context.Context, r Receipt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.receipts = append(m.receipts, r)
+	return nil
+}
+
+// PushResults 实现 Store
+func (m *MemoryStore) PushResults(ctx context.Context, q StoreQuery) ([]PushRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var ret []PushRecord
+	for _, r := range m.pushes {
+		if q.matchPush(r) {
+			ret = append(ret, r)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].SentAt.Before(ret[j].SentAt) })
+	if q.Limit > 0 && len(ret) > q.Limit {
+		ret = ret[:q.Limit]
+	}
+	return ret, nil
+}
+
+// Receipts 实现 Store
+func (m *MemoryStore) Receipts(ctx context.Context, q StoreQuery) ([]Receipt, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var ret []Receipt
+	for _, r := range m.receipts {
+		if q.match(r.TaskID, r.Time()) {
+			ret = append(ret, r)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].RecvTime < ret[j].RecvTime })
+	if q.Limit > 0 && len(ret) > q.Limit {
+		ret = ret[:q.Limit]
+	}
+	return ret, nil
+}
+
+// savePush 推送成功后保存发送结果，未配置 Store 时不做任何事
+// 保存失败不影响推送的返回
+func (c *client) savePush(endpoint string, start time.Time, requestID, groupName string, targets []string, targetCount int, ret *RspBody, err error) {
+	if c.Store == nil || err != nil || ret == nil {
+		return
+	}
+
+	_ = c.Store.SavePushResult(context.Background(), PushRecord{
+		TaskID:      ret.TaskID,
+		RequestID:   requestID,
+		GroupName:   groupName,
+		Endpoint:    endpoint,
+		Targets:     targets,
+		TargetCount: targetCount,
+		Result:      ret.Result,
+		Status:      ret.Status,
+		SentAt:      start,
+	})
+}
+
+// PushTrace 一次推送相关的发送结果与回执，见 TracePush
+type PushTrace struct {
+	// Pushes 按发送时间升序
+	Pushes []PushRecord
+	// Receipts 各taskid的回执，按回执时间升序
+	Receipts []Receipt
+}
+
+// TaskIDs 涉及的taskid，按首次发送时间排列
+func (t *PushTrace) TaskIDs() []string {
+	var ret []string
+	seen := map[string]bool{}
+	for _, r := range t.Pushes {
+		if len(r.TaskID) > 0 && !seen[r.TaskID] {
+			seen[r.TaskID] = true
+			ret = append(ret, r.TaskID)
+		}
+	}
+	return ret
+}
+
+// TracePush 按requestid、taskid或任务组名查找推送的发送结果，以及这些taskid收到的回执
+// id 依次作为 requestid、taskid、group_name 查询，结果合并；没有发送结果时仍按taskid查询回执
+// PushPersonalized 各条消息的requestid带有 "-序号" 后缀，需要按完整的requestid查询
+func TracePush(ctx context.Context, s Store, id string) (*PushTrace, error) {
+	if len(id) == 0 {
+		return nil, fmt.Errorf("[TracePush] id 不能为空")
+	}
+
+	ret := &PushTrace{}
+	type pushKey struct {
+		endpoint, taskID, requestID string
+		sentAt                      int64
+	}
+	seen := map[pushKey]bool{}
+	for _, q := range []StoreQuery{{RequestID: id}, {TaskID: id}, {GroupName: id}} {
+		pushes, err := s.PushResults(ctx, q)
+		if err != nil {
+			return nil, fmt.Errorf("[TracePush] 查询发送结果失败, err: %w", err)
+		}
+		for _, r := range pushes {
+			key := pushKey{r.Endpoint, r.TaskID, r.RequestID, r.SentAt.UnixNano()}
+			if !seen[key] {
+				seen[key] = true
+				ret.Pushes = append(ret.Pushes, r)
+			}
+		}
+	}
+	sort.SliceStable(ret.Pushes, func(i, j int) bool { return ret.Pushes[i].SentAt.Before(ret.Pushes[j].SentAt) })
+
+	taskIDs := ret.TaskIDs()
+	if len(taskIDs) == 0 {
+		taskIDs = []string{id}
+	}
+	for _, taskID := range taskIDs {
+		receipts, err := s.Receipts(ctx, StoreQuery{TaskID: taskID})
+		if err != nil {
+			return nil, fmt.Errorf("[TracePush] 查询回执失败, taskid: %s, err: %w", taskID, err)
+		}
+		ret.Receipts = append(ret.Receipts, receipts...)
+	}
+	sort.SliceStable(ret.Receipts, func(i, j int) bool { return ret.Receipts[i].RecvTime < ret.Receipts[j].RecvTime })
+	return ret, nil
+}
+
+// singleTargets 单推的目标
+func singleTargets(body SingleReqBody) []string {
+	if len(body.CID) > 0 {
+		return []string{body.CID}
+	}
+	return []string{body.Alias}
+}
+
+// listTargets list推的目标
+func listTargets(body ListReqBody) []string {
+	targets := append([]string(nil), body.CID...)
+	if len(body.Alias) > 0 {
+		targets = append(targets, body.Alias)
+	}
+	return targets
+}
@@ -0,0 +1,97 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TagPushMode PushToTag 的推送方式
+type TagPushMode int
+
+const (
+	// TagPushCondition 通过 push_app 按 ConditionTag 条件推送，与 PushToApp 共用频率限制
+	TagPushCondition TagPushMode = iota
+	// TagPushFast 通过 push_by_tag 按 fast_custom_tag 快速推送，有独立的频率限制
+	// 被限流时自动改用 TagPushCondition
+	TagPushFast
+)
+
+// tagPushBody push_by_tag 请求body
+type tagPushBody struct {
+	Message       Message      `json:"message"`
+	Notification  Notification `json:"notification"`
+	PushInfo      *PushInfo    `json:"push_info,omitempty"`
+	FastCustomTag string       `json:"fast_custom_tag"`
+	RequestID     string       `json:"requestid"`
+}
+
+// PushToTag 向拥有标签的用户推送通知，按 InitParams.TagPushMode 选择推送方式
+// 消息离线存储，pushInfo 用于iOS，Aps.Alert 为空时不发送
+func (c *client) PushToTag(tag string, notification Notification, pushInfo PushInfo) (*RspBody, error) {
+
+	if len(tag) == 0 {
+		return nil, fmt.Errorf("[PushToTag] 标签不能为空, err: %w", ErrInvalidTarget)
+	}
+
+	message := Message{IsOffline: true, MsgType: "notification"}
+	var info *PushInfo
+	if len(pushInfo.Aps.Alert.Title) > 0 || len(pushInfo.Aps.Alert.Body) > 0 {
+		info = &pushInfo
+	}
+	err := c.checkValid(context.Background(), "PushToTag", ValidateBadge(pushInfo.Aps.AutoBadge))
+	if err != nil {
+		return nil, fmt.Errorf("[PushToTag] %w", err)
+	}
+
+	if c.TagPushMode == TagPushFast {
+		ret, err := c.pushByTag(context.Background(), tagPushBody{
+			Message:       message,
+			Notification:  notification,
+			PushInfo:      info,
+			FastCustomTag: tag,
+		})
+		if !errors.Is(err, ErrRateLimited) {
+			return ret, err
+		}
+		c.log(context.Background(), LogWarn, "快速标签推送被限流, 改用条件推送", LogField{"tag", tag})
+	}
+
+	ret, err := c.PushToApp(AppReqBody{
+		Message:      message,
+		Notification: notification,
+		PushInfo:     info,
+		Condition:    []AppReqBodyCondition{NewCondition(ConditionTag, OptTypeOr, tag)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[PushToTag] %w", err)
+	}
+	return ret, nil
+}
+
+// pushByTag 快速标签推送
+func (c *client) pushByTag(ctx context.Context, body tagPushBody) (ret *RspBody, err error) {
+
+	key, err := c.checkDuplicate(body.FastCustomTag, body.Message, body.Notification, body.PushInfo)
+	if err != nil {
+		return nil, fmt.Errorf("[PushToTag] 推送去重, err: %w", err)
+	}
+	defer func() { c.releaseDuplicate(key, err) }()
+
+	c.applyDefaults(&body.Message, &body.Notification, body.PushInfo)
+	body.Message.AppKey = c.appKey()
+	body.RequestID = requestID(ctx, body.RequestID)
+
+	start := time.Now()
+	ret, err = doRequest[RspBody](ctx, c, "POST", "push_by_tag", body)
+	c.logPush("push_by_tag", start, body.RequestID, 0, ret, err)
+	c.savePush("push_by_tag", start, body.RequestID, "", nil, 0, ret, err)
+	c.auditPush(ctx, "push_by_tag", start, body.RequestID, nil, 0, ret, err)
+	if err != nil {
+		return nil, fmt.Errorf("[PushToTag] 发送 快速标签推送 失败, requestid: %s, err: %w", body.RequestID, err)
+	}
+	ret.RequestID = body.RequestID
+
+	return
+}
@@ -0,0 +1,195 @@
+package getui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TagFilter 按标签筛选toapp推送的用户，各部分之间为"且"
+// 个推的条件只支持一层：同一条件内的值为或/且/非，条件之间为且
+type TagFilter struct {
+	// All 同时拥有全部标签
+	All []string
+	// Any 每组中至少拥有一个标签，多组之间为"且"
+	Any [][]string
+	// None 不拥有其中任何一个标签
+	None []string
+}
+
+// Conditions 生成toapp的条件
+func (f TagFilter) Conditions() ([]AppReqBodyCondition, error) {
+	var ret []AppReqBodyCondition
+	if len(f.All) > 0 {
+		ret = append(ret, AppReqBodyCondition{Key: ConditionTag, Values: f.All, OptType: OptTypeAnd})
+	}
+	for _, group := range f.Any {
+		if len(group) == 0 {
+			continue
+		}
+		ret = append(ret, AppReqBodyCondition{Key: ConditionTag, Values: group, OptType: OptTypeOr})
+	}
+	if len(f.None) > 0 {
+		ret = append(ret, AppReqBodyCondition{Key: ConditionTag, Values: f.None, OptType: OptTypeNot})
+	}
+
+	// 只有排除条件时没有可推送的用户范围
+	if len(ret) == 0 || len(f.All) == 0 && len(ret) == 1 && len(f.None) > 0 {
+		return nil, fmt.Errorf("[TagFilter] 至少需要一个 All 或 Any 标签, err: %w", ErrInvalidTarget)
+	}
+	for _, c := range ret {
+		for _, tag := range c.Values {
+			if len(strings.TrimSpace(tag)) == 0 {
+				return nil, fmt.Errorf("[TagFilter] 标签不能为空, err: %w", ErrInvalidTarget)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// ParseTagExpr 解析标签表达式，如：
+//
+//	vip AND active
+//	(vip OR svip) AND NOT churned
+//	NOT (churned OR banned) AND active
+//
+// 运算符不区分大小写。个推的条件不支持任意嵌套，
+// 如 (a AND b) OR c、a OR b AND c 会返回错误
+func ParseTagExpr(expr string) (*TagFilter, error) {
+	tokens, err := tokenizeTagExpr(expr)
+	if err != nil {
+		return nil, fmt.Errorf("[ParseTagExpr] %w", err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("[ParseTagExpr] 表达式为空")
+	}
+
+	terms, op, err := splitTopLevel(tokens)
+	if err != nil {
+		return nil, fmt.Errorf("[ParseTagExpr] %q: %w", expr, err)
+	}
+
+	f := &TagFilter{}
+	if op == "OR" {
+		// a OR b OR c：每项只能是标签
+		group := make([]string, 0, len(terms))
+		for _, term := range terms {
+			if len(term) != 1 || isTagOperator(term[0]) {
+				return nil, fmt.Errorf("[ParseTagExpr] %q: 不支持的嵌套, OR 的每一项只能是标签", expr)
+			}
+			group = append(group, term[0])
+		}
+		f.Any = [][]string{group}
+		return f, nil
+	}
+
+	for _, term := range terms {
+		not := false
+		if term[0] == "NOT" {
+			not, term = true, term[1:]
+		}
+
+		var tags []string
+		switch {
+		case len(term) == 1 && !isTagOperator(term[0]):
+			tags = term
+		case len(term) > 2 && term[0] == "(" && term[len(term)-1] == ")":
+			tags, err = parseOrGroup(term[1 : len(term)-1])
+			if err != nil {
+				return nil, fmt.Errorf("[ParseTagExpr] %q: %w", expr, err)
+			}
+		default:
+			return nil, fmt.Errorf("[ParseTagExpr] %q: 不支持的表达式 %q", expr, strings.Join(term, " "))
+		}
+
+		switch {
+		case not:
+			f.None = append(f.None, tags...)
+		case len(tags) == 1:
+			f.All = append(f.All, tags[0])
+		default:
+			f.Any = append(f.Any, tags)
+		}
+	}
+	return f, nil
+}
+
+// tokenizeTagExpr 拆分为标签、运算符与括号，运算符转为大写
+func tokenizeTagExpr(expr string) ([]string, error) {
+	expr = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)
+	tokens := strings.Fields(expr)
+	depth := 0
+	for i, t := range tokens {
+		if up := strings.ToUpper(t); up == "AND" || up == "OR" || up == "NOT" {
+			tokens[i] = up
+		}
+		switch t {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("括号不匹配")
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("括号不匹配")
+	}
+	return tokens, nil
+}
+
+// splitTopLevel 按括号外的 AND/OR 拆分，两者混用时报错
+func splitTopLevel(tokens []string) (terms [][]string, op string, err error) {
+	depth, start := 0, 0
+	for i, t := range tokens {
+		switch t {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case "AND", "OR":
+			if depth > 0 {
+				continue
+			}
+			if len(op) > 0 && op != t {
+				return nil, "", fmt.Errorf("不支持的嵌套, AND 与 OR 混用时需用括号, 且括号内只能是 OR")
+			}
+			op = t
+			if i == start {
+				return nil, "", fmt.Errorf("%s 缺少左侧的标签", t)
+			}
+			terms = append(terms, tokens[start:i])
+			start = i + 1
+		}
+	}
+	if start >= len(tokens) {
+		return nil, "", fmt.Errorf("表达式不完整")
+	}
+	terms = append(terms, tokens[start:])
+	return terms, op, nil
+}
+
+// parseOrGroup 解析括号内的 a OR b OR c
+func parseOrGroup(tokens []string) ([]string, error) {
+	var tags []string
+	for i, t := range tokens {
+		if i%2 == 1 {
+			if t != "OR" {
+				return nil, fmt.Errorf("不支持的嵌套, 括号内只能是 OR")
+			}
+			continue
+		}
+		if isTagOperator(t) {
+			return nil, fmt.Errorf("不支持的嵌套, 括号内只能是标签")
+		}
+		tags = append(tags, t)
+	}
+	if len(tokens)%2 == 0 {
+		return nil, fmt.Errorf("表达式不完整")
+	}
+	return tags, nil
+}
+
+func isTagOperator(t string) bool {
+	return t == "AND" || t == "OR" || t == "NOT" || t == "(" || t == ")"
+}
@@ -0,0 +1,102 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_PushToSingleAdaptive 并发逐步增加，被限流后降低
+func Test_PushToSingleAdaptive(t *testing.T) {
+	const serverLimit = 4
+	var mu sync.Mutex
+	inflight, peak := 0, 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if !strings.HasSuffix(req.URL.Path, "push_single") {
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+		}
+		mu.Lock()
+		inflight++
+		if inflight > peak {
+			peak = inflight
+		}
+		limited := inflight > serverLimit
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inflight--
+			mu.Unlock()
+		}()
+
+		time.Sleep(2 * time.Millisecond)
+		if limited {
+			return jsonResponse(req, http.StatusTooManyRequests, `{"result":"flow_exceeded"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"t"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	bodies := make([]getui.SingleReqBody, 300)
+	for i := range bodies {
+		bodies[i] = getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}
+	}
+
+	var adjustMu sync.Mutex
+	var adjusts []int
+	ret, err := client.PushToSingleAdaptive(context.Background(), bodies, getui.AdaptiveOptions{
+		MaxConcurrency: 16,
+		LatencyTarget:  20 * time.Millisecond,
+		OnAdjust: func(n int) {
+			adjustMu.Lock()
+			adjusts = append(adjusts, n)
+			adjustMu.Unlock()
+		},
+	})
+	assert.Len(t, ret.Sent, len(bodies)-len(ret.Failed))
+	if len(ret.Failed) > 0 {
+		assert.ErrorIs(t, err, getui.ErrRateLimited)
+	}
+	for i := 1; i < len(ret.Sent); i++ {
+		assert.Less(t, ret.Sent[i-1].Index, ret.Sent[i].Index)
+	}
+
+	mu.Lock()
+	assert.Greater(t, peak, 1)
+	assert.LessOrEqual(t, peak, 16)
+	mu.Unlock()
+
+	// 先增加，被限流后降低
+	adjustMu.Lock()
+	defer adjustMu.Unlock()
+	increased, decreased := false, false
+	for i, n := range adjusts {
+		prev := 1
+		if i > 0 {
+			prev = adjusts[i-1]
+		}
+		increased = increased || n > prev
+		decreased = decreased || n < prev
+	}
+	assert.True(t, increased)
+	assert.True(t, decreased)
+
+	// 取消后剩余的不再发送
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ret, err = client.PushToSingleAdaptive(ctx, bodies[:5], getui.AdaptiveOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, ret.NotAttempted, 5)
+}
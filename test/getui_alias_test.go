@@ -0,0 +1,111 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_ImportAliases 分批绑定别名，逐行报告失败
+func Test_ImportAliases(t *testing.T) {
+	var mu sync.Mutex
+	var batchSizes []int
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "bind_alias") {
+			body := struct {
+				AliasList []getui.AliasBinding `json:"alias_list"`
+			}{}
+			json.NewDecoder(req.Body).Decode(&body)
+
+			mu.Lock()
+			batchSizes = append(batchSizes, len(body.AliasList))
+			mu.Unlock()
+
+			for _, b := range body.AliasList {
+				if b.Alias == "rejected" {
+					return jsonResponse(req, http.StatusOK, `{"result":"alias_error"}`), nil
+				}
+			}
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		// 测试数据的cid不是真实格式
+		SkipCIDValidation: true,
+	})
+	assert.Nil(t, err)
+
+	input := strings.Join([]string{
+		"cid,alias",
+		"cid1,user1",
+		"cid2,user2",
+		"cid3",
+		"cid4,user4",
+		"cid5,rejected",
+	}, "\n")
+
+	ret, err := getui.ImportAliases(context.Background(), client, strings.NewReader(input), getui.AliasImportOptions{
+		BatchSize:   2,
+		Concurrency: 1,
+		HasHeader:   true,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, ret.Total)
+	assert.Equal(t, 2, ret.Succeeded)
+	assert.Equal(t, 3, ret.Failed)
+	assert.Equal(t, []int{2, 2}, batchSizes)
+
+	lines := []int{}
+	for _, f := range ret.Failures {
+		lines = append(lines, f.Line)
+	}
+	assert.Equal(t, []int{4, 5, 6}, lines)
+}
+
+// Test_ImportAliasesBadCID cid格式错误的行单独记为失败，同批的其它行正常绑定
+func Test_ImportAliasesBadCID(t *testing.T) {
+	var bound []getui.AliasBinding
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "bind_alias") {
+			body := struct {
+				AliasList []getui.AliasBinding `json:"alias_list"`
+			}{}
+			json.NewDecoder(req.Body).Decode(&body)
+			bound = append(bound, body.AliasList...)
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	input := strings.Join([]string{
+		"0123456789abcdef0123456789abcdef,user1",
+		"user2,0123456789abcdef0123456789abcdef",
+		"fedcba9876543210fedcba9876543210,user3",
+	}, "\n")
+
+	ret, err := getui.ImportAliases(context.Background(), client, strings.NewReader(input), getui.AliasImportOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, ret.Total)
+	assert.Equal(t, 2, ret.Succeeded)
+	assert.Equal(t, 1, ret.Failed)
+	assert.Len(t, bound, 2)
+	assert.Equal(t, 2, ret.Failures[0].Line)
+	assert.ErrorIs(t, ret.Failures[0].Err, getui.ErrInvalidTarget)
+}
@@ -0,0 +1,77 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_WithApp 单次调用指定其它应用，各应用使用自己的token
+func Test_WithApp(t *testing.T) {
+	var mu sync.Mutex
+	auths := map[string]int{}
+	var pushes []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body struct {
+			Message struct {
+				AppKey string `json:"appkey"`
+			} `json:"message"`
+		}
+		if req.Body != nil {
+			json.NewDecoder(req.Body).Decode(&body)
+		}
+		appID := strings.Split(strings.TrimPrefix(req.URL.Path, "/v1/"), "/")[0]
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.HasSuffix(req.URL.Path, "auth_sign"):
+			auths[appID]++
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"token-`+appID+`"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_single"):
+			pushes = append(pushes, appID+","+body.Message.AppKey+","+strings.Join(req.Header[getui.AuthHeaderV1], ""))
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"t"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "defaultApp",
+		AppKey:       "defaultKey",
+		MasterSecret: "defaultSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	body := getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}
+	white := getui.WithApp(context.Background(), getui.AppDescriptor{AppID: "whiteApp", AppKey: "whiteKey", MasterSecret: "whiteSecret"})
+	for i := 0; i < 2; i++ {
+		_, err = client.PushToSingleContext(white, body)
+		assert.Nil(t, err)
+	}
+	_, err = client.PushToSingleContext(context.Background(), body)
+	assert.Nil(t, err)
+
+	mu.Lock()
+	assert.Equal(t, []string{
+		"whiteApp,whiteKey,token-whiteApp",
+		"whiteApp,whiteKey,token-whiteApp",
+		"defaultApp,defaultKey,token-defaultApp",
+	}, pushes)
+	assert.Equal(t, map[string]int{"defaultApp": 1, "whiteApp": 1}, auths)
+	mu.Unlock()
+
+	// 凭证不完整
+	bad := getui.WithApp(context.Background(), getui.AppDescriptor{AppID: "badApp"})
+	_, err = client.PushToSingleContext(bad, body)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, client.Shutdown(context.Background()))
+	_, err = client.PushToSingleContext(white, body)
+	assert.ErrorIs(t, err, getui.ErrClientClosed)
+}
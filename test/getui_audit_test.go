@@ -0,0 +1,129 @@
+package getui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_Audit 成功与失败的推送都写入审计记录，序号连续、哈希链可校验
+func Test_Audit(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "push_single"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID","status":"successed_online"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_app"):
+			return jsonResponse(req, http.StatusOK, `{"result":"other_error"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	var buf bytes.Buffer
+	auditor := getui.NewAuditor(getui.NewJSONAuditSink(&buf), getui.AuditorOptions{Service: "order-service"})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Auditor:      auditor,
+	})
+	assert.Nil(t, err)
+
+	ctx := getui.WithAudit(context.Background(), getui.AuditInfo{Actor: "ops@example.com", Template: "order_shipped"})
+	_, err = client.PushToSingleContext(ctx, getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef", RequestID: "req1"})
+	assert.Nil(t, err)
+	_, err = client.PushToAppContext(ctx, getui.AppReqBody{Condition: []getui.AppReqBodyCondition{getui.NewCondition(getui.ConditionTag, getui.OptTypeOr, "vip")}})
+	assert.NotNil(t, err)
+
+	records, err := getui.ReadAuditLog(&buf)
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+	assert.Nil(t, getui.VerifyAuditChain(records))
+
+	first := records[0]
+	assert.Equal(t, uint64(1), first.Seq)
+	assert.Equal(t, "order-service", first.Service)
+	assert.Equal(t, "ops@example.com", first.Actor)
+	assert.Equal(t, "order_shipped", first.Template)
+	assert.Equal(t, "testAppID", first.AppID)
+	assert.Equal(t, "push_single", first.Endpoint)
+	assert.Equal(t, "req1", first.RequestID)
+	assert.Equal(t, "testTaskID", first.TaskID)
+	assert.Equal(t, []string{"0123456789abcdef0123456789abcdef"}, first.Targets)
+	assert.Equal(t, "ok", first.Result)
+	assert.Equal(t, "push_app", records[1].Endpoint)
+	assert.Equal(t, "other_error", records[1].Result)
+	assert.NotEmpty(t, records[1].Error)
+	assert.Equal(t, first.Hash, records[1].PrevHash)
+
+	// 修改、删除记录都能被发现
+	modified := append([]getui.AuditRecord(nil), records...)
+	modified[0].Actor = "someone"
+	assert.ErrorIs(t, getui.VerifyAuditChain(modified), getui.ErrAuditTampered)
+
+	_, err = auditor.Record(context.Background(), getui.AuditRecord{Endpoint: "push_single"})
+	assert.Nil(t, err)
+	more, err := getui.ReadAuditLog(&buf)
+	assert.Nil(t, err)
+	assert.ErrorIs(t, getui.VerifyAuditChain([]getui.AuditRecord{records[0], more[0]}), getui.ErrAuditTampered)
+	assert.Nil(t, getui.VerifyAuditChain(append(records, more...)))
+
+	// 重启后接着上次的序号与哈希继续；写入失败的记录不进入哈希链，下一条复用其序号
+	sinkDown := true
+	var written []getui.AuditRecord
+	resumed := getui.NewAuditor(getui.AuditSinkFunc(func(ctx context.Context, r getui.AuditRecord) error {
+		if sinkDown {
+			return errors.New("sink down")
+		}
+		written = append(written, r)
+		return nil
+	}), getui.AuditorOptions{Last: &more[0]})
+	r, err := resumed.Record(context.Background(), getui.AuditRecord{Endpoint: "push_app"})
+	assert.NotNil(t, err)
+	assert.Equal(t, uint64(4), r.Seq)
+
+	sinkDown = false
+	r, err = resumed.Record(context.Background(), getui.AuditRecord{Endpoint: "push_app"})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), r.Seq)
+	assert.Len(t, written, 1)
+	assert.Nil(t, getui.VerifyAuditChain(append(append(records, more...), written...)))
+}
+
+// Test_AuditCanceled 推送发出后ctx被取消，审计记录照常写入
+func Test_AuditCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var written []getui.AuditRecord
+	auditor := getui.NewAuditor(getui.AuditSinkFunc(func(ctx context.Context, r getui.AuditRecord) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		written = append(written, r)
+		return nil
+	}), getui.AuditorOptions{})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if strings.HasSuffix(req.URL.Path, "push_single") {
+				cancel()
+			}
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","taskid":"testTaskID"}`), nil
+		})},
+		Auditor: auditor,
+	})
+	assert.Nil(t, err)
+
+	client.PushToSingleContext(ctx, getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"})
+	assert.NotNil(t, ctx.Err())
+	assert.Len(t, written, 1)
+	assert.Equal(t, "push_single", written[0].Endpoint)
+}
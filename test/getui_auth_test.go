@@ -5,7 +5,6 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"testing"
 	"time"
@@ -29,7 +28,7 @@ func Test_GetAuth(t *testing.T) {
 	}{AppKey: appKey, Timestamp: ts, Sign: signStr}
 
 	data, _ := json.Marshal(body)
-	req, err := http.NewRequest("POST", "https://restapi.getui.com/v1/"+appID+"/auth_sign", ioutil.NopCloser(bytes.NewReader(data)))
+	req, err := http.NewRequest("POST", "https://restapi.getui.com/v1/"+appID+"/auth_sign", bytes.NewReader(data))
 	assert.Nil(t, err)
 	req.Header.Add("Content-Type", "application/json")
 
@@ -37,14 +36,11 @@ func Test_GetAuth(t *testing.T) {
 	assert.Nil(t, err)
 	defer rsp.Body.Close()
 
-	rspBody, err := ioutil.ReadAll(rsp.Body)
-	assert.Nil(t, err)
-
 	ret := &struct {
 		Result    string `json:"result"`
 		AuthToken string `json:"auth_token"`
 	}{}
-	err = json.Unmarshal(rspBody, ret)
+	err = json.NewDecoder(rsp.Body).Decode(ret)
 	assert.Nil(t, err)
 	t.Log(ret.Result)
 	t.Log(ret.AuthToken)
@@ -0,0 +1,41 @@
+package getui
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_AuthHeader 鉴权请求头按原样发送，可覆盖请求头名
+func Test_AuthHeader(t *testing.T) {
+	var headers []http.Header
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "stop_task/task1") {
+			headers = append(headers, req.Header.Clone())
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	params := getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	}
+	client, err := getui.New(params)
+	assert.Nil(t, err)
+	_, err = client.StopTask("task1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"testAuthToken"}, headers[0]["authtoken"])
+	assert.Nil(t, headers[0]["Authtoken"])
+
+	params.AuthHeader = getui.AuthHeaderV2
+	client, err = getui.New(params)
+	assert.Nil(t, err)
+	_, err = client.StopTask("task1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"testAuthToken"}, headers[1]["token"])
+	assert.Nil(t, headers[1]["authtoken"])
+}
@@ -0,0 +1,25 @@
+package getui
+
+import (
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_Badge 角标取值
+func Test_Badge(t *testing.T) {
+	assert.Equal(t, "5", getui.Badge.Set(5))
+	assert.Equal(t, "0", getui.Badge.Set(-1))
+	assert.Equal(t, "+1", getui.Badge.Increment(1))
+	assert.Equal(t, "-2", getui.Badge.Increment(-2))
+	assert.Equal(t, "-1", getui.Badge.Decrement(1))
+	assert.Equal(t, "+3", getui.Badge.Decrement(-3))
+
+	for _, s := range []string{"", "0", "12", "+1", "-1"} {
+		assert.Nil(t, getui.ValidateBadge(s), s)
+	}
+	for _, s := range []string{"+", "1+", "++1", " 1", "one", "-1.5"} {
+		assert.NotNil(t, getui.ValidateBadge(s), s)
+	}
+}
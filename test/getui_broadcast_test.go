@@ -1,6 +1,10 @@
 package getui
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/printfcoder/getui"
@@ -31,3 +35,74 @@ func Test_Broadcast(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, rsp)
 }
+
+// Test_PushToAll 全量推送需要确认appid
+func Test_PushToAll(t *testing.T) {
+	var bodies []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_app") {
+			data, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(data))
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	params := getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	}
+	client, err := getui.New(params)
+	assert.Nil(t, err)
+
+	_, err = client.PushToAll(getui.AppReqBody{}, "prodAppID")
+	assert.ErrorIs(t, err, getui.ErrBroadcastNotConfirmed)
+
+	_, err = client.PushToAll(getui.AppReqBody{Condition: []getui.AppReqBodyCondition{{Key: "tag", Values: []string{"vip"}}}}, "testAppID")
+	assert.ErrorIs(t, err, getui.ErrInvalidTarget)
+	assert.Len(t, bodies, 0)
+
+	ret, err := client.PushToAll(getui.AppReqBody{}, "testAppID")
+	assert.Nil(t, err)
+	assert.Equal(t, "testTaskID", ret.TaskID)
+	assert.Len(t, bodies, 1)
+	assert.Contains(t, bodies[0], `"condition":[]`)
+
+	params.DisableBroadcast = true
+	client, err = getui.New(params)
+	assert.Nil(t, err)
+	_, err = client.PushToAllContext(context.Background(), getui.AppReqBody{}, "testAppID")
+	assert.ErrorIs(t, err, getui.ErrBroadcastNotConfirmed)
+	// 条件为空的 PushToApp 同样是全量推送
+	_, err = client.PushToApp(getui.AppReqBody{})
+	assert.ErrorIs(t, err, getui.ErrBroadcastNotConfirmed)
+	_, err = client.PushToAppContext(context.Background(), getui.AppReqBody{Condition: []getui.AppReqBodyCondition{}})
+	assert.ErrorIs(t, err, getui.ErrBroadcastNotConfirmed)
+	assert.Len(t, bodies, 1)
+}
+
+// Test_PushToAppPhoneType 条件中的手机类型在请求前校验
+func Test_PushToAppPhoneType(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","taskid":"testTaskID"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	_, err = client.PushToApp(getui.AppReqBody{Condition: []getui.AppReqBodyCondition{
+		getui.NewCondition(getui.ConditionPhoneType, getui.OptTypeOr, "android"),
+	}})
+	assert.ErrorIs(t, err, getui.ErrInvalidTarget)
+
+	_, err = client.PushToApp(getui.AppReqBody{Condition: []getui.AppReqBodyCondition{
+		getui.NewCondition(getui.ConditionPhoneType, getui.OptTypeOr, getui.PhoneTypeAndroid),
+	}})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,33 @@
+package getui
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_VerifyCallbackSignature 回执签名校验
+func Test_VerifyCallbackSignature(t *testing.T) {
+	cid := "0123456789abcdef0123456789abcdef"
+	sign := getui.CallbackSign("testAppKey", cid, "testTaskID", "testMsgID", "testMasterSecret")
+	assert.Len(t, sign, 32)
+
+	body := fmt.Sprintf(`{"appid":"testAppID","cid":"%s","taskid":"testTaskID","msgid":"testMsgID","code":"0","sign":"%s"}`, cid, sign)
+	assert.Nil(t, getui.VerifyCallbackSignature(http.Header{}, []byte(body), "testAppKey", "testMasterSecret"))
+
+	// MasterSecret 不一致
+	err := getui.VerifyCallbackSignature(http.Header{}, []byte(body), "testAppKey", "otherMasterSecret")
+	assert.ErrorIs(t, err, getui.ErrInvalidCallbackSignature)
+
+	// 签名在header中
+	body = fmt.Sprintf(`{"cid":"%s","taskid":"testTaskID","msgid":"testMsgID"}`, cid)
+	header := http.Header{}
+	assert.ErrorIs(t, getui.VerifyCallbackSignature(header, []byte(body), "testAppKey", "testMasterSecret"), getui.ErrInvalidCallbackSignature)
+	header.Set("sign", sign)
+	assert.Nil(t, getui.VerifyCallbackSignature(header, []byte(body), "testAppKey", "testMasterSecret"))
+
+	assert.NotNil(t, getui.VerifyCallbackSignature(header, []byte("not json"), "testAppKey", "testMasterSecret"))
+}
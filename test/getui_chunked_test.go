@@ -0,0 +1,288 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_PushToListChunked 分片发送中途取消，返回各分片的状态
+func Test_PushToListChunked(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var saves, lists int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "save_list_body"):
+			atomic.AddInt32(&saves, 1)
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_list"):
+			var body getui.ListReqBody
+			assert.Nil(t, json.NewDecoder(req.Body).Decode(&body))
+			assert.Equal(t, "testTaskID", body.TaskID)
+			if atomic.AddInt32(&lists, 1) == 2 {
+				cancel()
+			}
+			return jsonResponse(req, http.StatusOK, `{"result":"ok"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	cids := []string{
+		"00000000000000000000000000000001",
+		"00000000000000000000000000000002",
+		"00000000000000000000000000000003",
+		"00000000000000000000000000000004",
+		"00000000000000000000000000000005",
+	}
+	ret, err := client.PushToListChunked(ctx, getui.ListReqBody{CID: cids}, 2)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&saves))
+	assert.Len(t, ret.Sent, 2)
+	assert.Len(t, ret.Failed, 0)
+	assert.Len(t, ret.NotAttempted, 1)
+	assert.Equal(t, 2, ret.NotAttempted[0].Index)
+	assert.Equal(t, cids[4:], ret.Pending())
+
+	// 续发剩余的目标
+	ret, err = client.PushToListChunked(context.Background(), getui.ListReqBody{CID: ret.Pending()}, 2)
+	assert.Nil(t, err)
+	assert.Len(t, ret.Sent, 1)
+	assert.Len(t, ret.Pending(), 0)
+}
+
+// Test_PushToSingleBulk 批量单推中途取消
+func Test_PushToSingleBulk(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var pushes int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			if atomic.AddInt32(&pushes, 1) == 1 {
+				return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+			}
+			cancel()
+			return nil, context.Canceled
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	bodies := []getui.SingleReqBody{
+		{CID: "00000000000000000000000000000001"},
+		{CID: "00000000000000000000000000000002"},
+		{Alias: "user3"},
+	}
+	ret, err := client.PushToSingleBulk(ctx, bodies)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, ret.Sent, 1)
+	assert.Len(t, ret.Failed, 1)
+	assert.Len(t, ret.NotAttempted, 1)
+	assert.Equal(t, []string{"00000000000000000000000000000002", "user3"}, ret.Pending())
+}
+
+// Test_DedupeCIDs 去掉重复的cid，保留顺序并返回去掉的个数
+func Test_DedupeCIDs(t *testing.T) {
+	cids, removed := getui.DedupeCIDs([]string{"b", "a", "b", "c", "a"})
+	assert.Equal(t, []string{"b", "a", "c"}, cids)
+	assert.Equal(t, 2, removed)
+
+	var sent [][]string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_list") {
+			var body getui.ListReqBody
+			assert.Nil(t, json.NewDecoder(req.Body).Decode(&body))
+			sent = append(sent, body.CID)
+			return jsonResponse(req, http.StatusOK, `{"result":"ok"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","taskid":"testTaskID"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		DedupeCIDs:   true,
+	})
+	assert.Nil(t, err)
+
+	a := "00000000000000000000000000000001"
+	b := "00000000000000000000000000000002"
+	ret, err := client.PushToListChunked(context.Background(), getui.ListReqBody{CID: []string{a, b, a, b, a}}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, ret.Duplicates)
+	assert.Equal(t, [][]string{{a, b}}, sent)
+
+	_, err = client.PushToList(getui.ListReqBody{CID: []string{b, b}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{b}, sent[1])
+}
+
+// Test_PruneInvalidCIDs 发送前去掉不存在的cid，查询失败的cid保留
+func Test_PruneInvalidCIDs(t *testing.T) {
+	valid := "00000000000000000000000000000001"
+	noUser := "00000000000000000000000000000002"
+	failing := "00000000000000000000000000000003"
+
+	var sent [][]string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "user_status/"+noUser):
+			return jsonResponse(req, http.StatusOK, `{"result":"no_user"}`), nil
+		case strings.HasSuffix(req.URL.Path, "user_status/"+failing):
+			return jsonResponse(req, http.StatusBadRequest, `{"result":"other_error"}`), nil
+		case strings.Contains(req.URL.Path, "user_status/"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","status":"online"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_list"):
+			var body getui.ListReqBody
+			assert.Nil(t, json.NewDecoder(req.Body).Decode(&body))
+			sent = append(sent, body.CID)
+			return jsonResponse(req, http.StatusOK, `{"result":"ok"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","taskid":"testTaskID"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	ret, err := client.PushToListChunked(context.Background(), getui.ListReqBody{
+		CID:              []string{valid, noUser, failing},
+		PruneInvalidCIDs: true,
+	}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{noUser}, ret.Pruned)
+	assert.Equal(t, [][]string{{valid, failing}}, sent)
+
+	// 全部不存在时不发送
+	sent = nil
+	ret, err = client.PushToListChunked(context.Background(), getui.ListReqBody{CID: []string{noUser}, PruneInvalidCIDs: true}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{noUser}, ret.Pruned)
+	assert.Len(t, sent, 0)
+}
+
+// Test_ChunkedCampaign 汇总各分片的taskid、状态与目标数
+func Test_ChunkedCampaign(t *testing.T) {
+	r := &getui.ChunkedResult{
+		Sent: []getui.ChunkResult{
+			{Index: 0, Targets: []string{"a", "b"}, Ret: &getui.RspBody{TaskID: "task1"}},
+			{Index: 2, Targets: []string{"e"}, Ret: &getui.RspBody{TaskID: "task1"}},
+		},
+		Failed:       []getui.ChunkResult{{Index: 1, Targets: []string{"c", "d"}, Err: getui.ErrRateLimited}},
+		NotAttempted: []getui.ChunkResult{{Index: 3, Targets: []string{"f"}}},
+		Duplicates:   2,
+		Pruned:       []string{"g"},
+	}
+
+	c := r.Campaign()
+	assert.Equal(t, 6, c.TotalTargets)
+	assert.Equal(t, 3, c.SentTargets)
+	assert.Equal(t, 2, c.FailedTargets)
+	assert.Equal(t, 1, c.NotAttemptedTargets)
+	assert.Equal(t, 2, c.Duplicates)
+	assert.Equal(t, 1, c.Pruned)
+	assert.Equal(t, []string{"task1"}, c.TaskIDs)
+	assert.Len(t, c.Chunks, 4)
+	for i, chunk := range c.Chunks {
+		assert.Equal(t, i, chunk.Index)
+	}
+	assert.Equal(t, "failed", c.Chunks[1].Status)
+	assert.ErrorIs(t, c.Chunks[1].Err, getui.ErrRateLimited)
+	assert.Equal(t, "not_attempted", c.Chunks[3].Status)
+}
+
+// Test_ChunkedSharedBody 各分片的请求与单独发送的请求内容一致
+func Test_ChunkedSharedBody(t *testing.T) {
+	var bodies []map[string]interface{}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "save_list_body"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_list"):
+			var body map[string]interface{}
+			assert.Nil(t, json.NewDecoder(req.Body).Decode(&body))
+			bodies = append(bodies, body)
+			return jsonResponse(req, http.StatusOK, `{"result":"ok"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	body := getui.ListReqBody{CID: []string{
+		"00000000000000000000000000000001",
+		"00000000000000000000000000000002",
+		"00000000000000000000000000000003",
+	}}
+	body.Message.MsgType = "notification"
+	body.Notification.Style.Title = "标题 \"quoted\""
+	body.Notification.Style.Text = "内容"
+	_, err = client.PushToListChunked(context.Background(), body, 2)
+	assert.Nil(t, err)
+
+	body.CID = body.CID[:2]
+	_, err = client.PushToList(body)
+	assert.Nil(t, err)
+
+	assert.Len(t, bodies, 3)
+	assert.Equal(t, bodies[2], bodies[0])
+	assert.Equal(t, []interface{}{"00000000000000000000000000000003"}, bodies[1]["cid"])
+	assert.Equal(t, "testTaskID", bodies[1]["taskid"])
+	assert.Equal(t, bodies[0]["notification"], bodies[1]["notification"])
+}
+
+// Test_FailedTargets 导出失败与未发送的目标
+func Test_FailedTargets(t *testing.T) {
+	ret := &getui.ChunkedResult{
+		Sent:         []getui.ChunkResult{{Index: 0, Targets: []string{"cid-1"}}},
+		Failed:       []getui.ChunkResult{{Index: 2, Targets: []string{"cid-3"}, Err: getui.ErrRateLimited}},
+		NotAttempted: []getui.ChunkResult{{Index: 1, Targets: []string{"cid-2", "alias,with,comma"}}},
+	}
+
+	failed := ret.FailedTargets()
+	assert.Len(t, failed, 3)
+	assert.Equal(t, "cid-2", failed[0].Target)
+	assert.Equal(t, "not_attempted", failed[0].Status)
+	assert.Equal(t, "failed", failed[2].Status)
+	assert.ErrorIs(t, failed[2].Err, getui.ErrRateLimited)
+
+	b := strings.Builder{}
+	assert.Nil(t, ret.WriteFailedCSV(&b))
+	assert.Equal(t, "target,chunk,status,reason\n"+
+		"cid-2,1,not_attempted,not_attempted\n"+
+		"\"alias,with,comma\",1,not_attempted,not_attempted\n"+
+		"cid-3,2,failed,getui: 超过频率限制\n", b.String())
+}
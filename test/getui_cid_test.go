@@ -0,0 +1,55 @@
+package getui
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_ValidateCID 请求前检查cid格式
+func Test_ValidateCID(t *testing.T) {
+	assert.Nil(t, getui.ValidateCID("0123456789abcdef0123456789ABCDEF"))
+	for _, cid := range []string{"", "0123456789abcdef", "0123456789abcdef0123456789abcdeg", " 0123456789abcdef0123456789abcde"} {
+		assert.NotNil(t, getui.ValidateCID(cid), cid)
+	}
+
+	var requests int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+	atomic.StoreInt32(&requests, 0)
+
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: "typo"})
+	assert.NotNil(t, err)
+	_, err = client.PushToList(getui.ListReqBody{CID: []string{"0123456789abcdef0123456789abcdef", ""}})
+	assert.NotNil(t, err)
+	_, err = client.UserStatus("typo")
+	assert.NotNil(t, err)
+	_, err = client.BindAlias([]getui.AliasBinding{{CID: "typo", Alias: "user1"}})
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+
+	// 跳过检查
+	client, err = getui.New(getui.InitParams{
+		AppID:             "testAppID",
+		AppKey:            "testAppKey",
+		MasterSecret:      "testMasterSecret",
+		HTTPClient:        &http.Client{Transport: transport},
+		SkipCIDValidation: true,
+	})
+	assert.Nil(t, err)
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: "typo"})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,55 @@
+package getui
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_ClockSkew 本地时钟比个推服务器慢1小时，时间戳超出范围时返回 sign_error
+func Test_ClockSkew(t *testing.T) {
+	serverNow := func() time.Time { return time.Now().Add(time.Hour) }
+	signs := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		signs++
+		body := struct {
+			Timestamp string `json:"timestamp"`
+		}{}
+		json.NewDecoder(req.Body).Decode(&body)
+		ms, _ := strconv.ParseInt(body.Timestamp, 10, 64)
+		ret := `{"result":"ok","auth_token":"testAuthToken"}`
+		if d := serverNow().Sub(time.UnixMilli(ms)); d > 10*time.Minute || d < -10*time.Minute {
+			ret = `{"result":"sign_error"}`
+		}
+		rsp := jsonResponse(req, http.StatusOK, ret)
+		rsp.Header.Set("Date", serverNow().UTC().Format(http.TimeFormat))
+		return rsp, nil
+	})
+	params := getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	}
+
+	_, err := getui.New(params)
+	assert.NotNil(t, err)
+
+	signs = 0
+	params.ClockSkewFromServer = true
+	_, err = getui.New(params)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, signs)
+
+	signs = 0
+	params.ClockSkewFromServer = false
+	params.ClockSkew = time.Hour
+	_, err = getui.New(params)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, signs)
+}
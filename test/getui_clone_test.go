@@ -0,0 +1,64 @@
+package getui
+
+import (
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_CloneSingle With 方法不修改原body
+func Test_CloneSingle(t *testing.T) {
+	base := getui.SingleReqBody{}
+	base.Notification.Style.Title = "发货通知"
+	base.Notification.Style.IsRing = getui.Bool(true)
+	base.PushInfo.Multimedia = []getui.PushInfoMultimedia{{URL: "https://cdn.example.com/a.jpg", Type: 1}}
+
+	a := base.WithCID("cid-a").WithText("你好 A")
+	b := base.WithAlias("alias-b").WithText("你好 B")
+
+	assert.Equal(t, "cid-a", a.CID)
+	assert.Equal(t, "", a.Alias)
+	assert.Equal(t, "alias-b", b.Alias)
+	assert.Equal(t, "你好 A", a.Notification.Style.Text)
+	assert.Equal(t, "你好 A", a.PushInfo.Aps.Alert.Body)
+	assert.Equal(t, "", base.Notification.Style.Text)
+	assert.Equal(t, "", base.CID)
+
+	*a.Notification.Style.IsRing = false
+	a.PushInfo.Multimedia[0].URL = "https://cdn.example.com/b.jpg"
+	assert.True(t, *base.Notification.Style.IsRing)
+	assert.Equal(t, "https://cdn.example.com/a.jpg", base.PushInfo.Multimedia[0].URL)
+}
+
+// Test_CloneList 副本不共享cid切片，修改内容时清空taskid
+func Test_CloneList(t *testing.T) {
+	base := getui.ListReqBody{CID: []string{"cid1", "cid2"}, TaskID: "task"}
+	c := base.Clone()
+	c.CID[0] = "changed"
+	assert.Equal(t, "cid1", base.CID[0])
+	assert.Equal(t, "task", c.TaskID)
+
+	d := base.WithTitle("新标题")
+	assert.Equal(t, "", d.TaskID)
+	assert.Equal(t, "task", base.TaskID)
+
+	e := base.WithCIDs("cid3")
+	assert.Equal(t, []string{"cid3"}, e.CID)
+	assert.Equal(t, []string{"cid1", "cid2"}, base.CID)
+}
+
+// Test_CloneApp 副本不共享条件与 PushInfo
+func Test_CloneApp(t *testing.T) {
+	base := getui.AppReqBody{PushInfo: &getui.PushInfo{}}
+	base = base.WithCondition(getui.NewCondition(getui.ConditionTag, getui.OptTypeOr, "vip"))
+
+	a := base.WithCondition(getui.NewCondition(getui.ConditionPhoneType, getui.OptTypeOr, getui.PhoneTypeIOS)).WithTitle("标题")
+	a.Condition[0].Values[0] = "changed"
+
+	assert.Len(t, base.Condition, 1)
+	assert.Len(t, a.Condition, 2)
+	assert.Equal(t, "vip", base.Condition[0].Values[0])
+	assert.Equal(t, "标题", a.PushInfo.Aps.Alert.Title)
+	assert.Equal(t, "", base.PushInfo.Aps.Alert.Title)
+}
@@ -0,0 +1,55 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_RefreshCloseAuthFailure auth_close 失败不影响申请新token
+func Test_RefreshCloseAuthFailure(t *testing.T) {
+	var paths []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		path := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+		paths = append(paths, path)
+		if path == "auth_close" {
+			return jsonResponse(req, http.StatusOK, `{"result":"not_auth"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	assert.Nil(t, client.RefreshAuth(context.Background()))
+	assert.Equal(t, []string{"auth_sign", "auth_close", "auth_sign"}, paths)
+	assert.Equal(t, "testAuthToken", client.AuthToken())
+}
+
+// Test_KeepTokenOnRefresh 设置 KeepTokenOnRefresh 后刷新前不关闭旧token
+func Test_KeepTokenOnRefresh(t *testing.T) {
+	var paths []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:])
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:              "testAppID",
+		AppKey:             "testAppKey",
+		MasterSecret:       "testMasterSecret",
+		HTTPClient:         &http.Client{Transport: transport},
+		KeepTokenOnRefresh: true,
+	})
+	assert.Nil(t, err)
+
+	assert.Nil(t, client.RefreshAuth(context.Background()))
+	assert.Equal(t, []string{"auth_sign", "auth_sign"}, paths)
+}
@@ -0,0 +1,54 @@
+package getui
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingCodec 统计调用次数的 Codec
+type countingCodec struct {
+	marshal, unmarshal int32
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	atomic.AddInt32(&c.marshal, 1)
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	atomic.AddInt32(&c.unmarshal, 1)
+	return json.Unmarshal(data, v)
+}
+
+// Test_Codec 请求与响应使用配置的 Codec 编解码
+func Test_Codec(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","taskid":"testTaskID"}`), nil
+	})
+	codec := &countingCodec{}
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Codec:        codec,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&codec.unmarshal))
+
+	body := getui.SingleReqBody{CID: "8b4ed2bfa6b22aa3a1c8a8b6e1b6c8b1"}
+	body.Message.MsgType = "notification"
+	body.Notification.Style.Type = 0
+	body.Notification.Style.Text = "text"
+	body.Notification.Style.Title = "title"
+	ret, err := client.PushToSingle(body)
+	assert.Nil(t, err)
+	assert.Equal(t, "testTaskID", ret.TaskID)
+	assert.True(t, atomic.LoadInt32(&codec.marshal) >= 2)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&codec.unmarshal))
+}
@@ -0,0 +1,223 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/printfcoder/getui/consumer"
+	"github.com/stretchr/testify/assert"
+)
+
+// chanMessage 测试用消息，记录确认结果，ackErr 不为空时确认失败
+type chanMessage struct {
+	body   string
+	result chan string
+	ackErr error
+}
+
+func (m *chanMessage) Body() []byte { return []byte(m.body) }
+func (m *chanMessage) Ack() error   { m.result <- "ack"; return m.ackErr }
+func (m *chanMessage) Nack() error  { m.result <- "nack"; return m.ackErr }
+
+type chanSource chan consumer.Message
+
+func (s chanSource) Receive(ctx context.Context) (consumer.Message, error) {
+	select {
+	case m := <-s:
+		return m, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Test_Consumer 成功的消息确认，临时故障重试后重新投递，业务错误与无法解析的消息确认后丢弃
+func Test_Consumer(t *testing.T) {
+	const badCID = "ffffffffffffffffffffffffffffffff"
+	const downCID = "eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			body, _ := io.ReadAll(req.Body)
+			mu.Lock()
+			defer mu.Unlock()
+			if strings.Contains(string(body), badCID) {
+				attempts["badcid"]++
+				return jsonResponse(req, http.StatusOK, `{"result":"other_error"}`), nil
+			}
+			if strings.Contains(string(body), downCID) {
+				attempts["downcid"]++
+				return jsonResponse(req, http.StatusServiceUnavailable, `<html>503</html>`), nil
+			}
+			attempts["goodcid"]++
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	var errCount int
+	src := make(chanSource)
+	c := consumer.New(client, src, consumer.Options{
+		Retries: 2,
+		Backoff: time.Millisecond,
+		OnError: func(body []byte, err error) {
+			mu.Lock()
+			errCount++
+			mu.Unlock()
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+
+	send := func(body string) string {
+		m := &chanMessage{body: body, result: make(chan string, 1)}
+		src <- m
+		return <-m.result
+	}
+	assert.Equal(t, "ack", send(`{"type":"single","single":{"cid":"0123456789abcdef0123456789abcdef"}}`))
+	assert.Equal(t, "nack", send(`{"type":"single","single":{"cid":"eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"}}`))
+	assert.Equal(t, "ack", send(`{"type":"single","single":{"cid":"ffffffffffffffffffffffffffffffff"}}`))
+	assert.Equal(t, "ack", send(`{"type":"single","single":{"cid":"bad"}}`))
+	assert.Equal(t, "ack", send(`{"type":"list"}`))
+	assert.Equal(t, "ack", send(`not json`))
+
+	cancel()
+	assert.Nil(t, <-done)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, attempts["goodcid"])
+	assert.Equal(t, 3, attempts["downcid"])
+	assert.Equal(t, 1, attempts["badcid"])
+	assert.Equal(t, 5, errCount)
+}
+
+// Test_ConsumerResult 发送结果按任务id回调，QPS 限制发送间隔
+func Test_ConsumerResult(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			body, _ := io.ReadAll(req.Body)
+			if strings.Contains(string(body), "ffffffffffffffffffffffffffffffff") {
+				return jsonResponse(req, http.StatusOK, `{"result":"other_error"}`), nil
+			}
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	var mu sync.Mutex
+	results := map[string]string{}
+	src := make(chanSource)
+	c := consumer.New(client, src, consumer.Options{
+		Retries: -1,
+		QPS:     20,
+		OnResult: func(ctx context.Context, job consumer.PushJob, ret *getui.RspBody, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				results[job.ID] = "failed"
+				return
+			}
+			results[job.ID] = ret.TaskID
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+
+	send := func(body string) string {
+		m := &chanMessage{body: body, result: make(chan string, 1)}
+		src <- m
+		return <-m.result
+	}
+	start := time.Now()
+	assert.Equal(t, "ack", send(`{"id":"job-1","type":"single","single":{"cid":"0123456789abcdef0123456789abcdef"}}`))
+	assert.Equal(t, "ack", send(`{"id":"job-2","type":"single","single":{"cid":"ffffffffffffffffffffffffffffffff"}}`))
+	assert.Equal(t, "ack", send(`{"id":"job-3","type":"single","single":{"cid":"0123456789abcdef0123456789abcdef"}}`))
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+
+	cancel()
+	assert.Nil(t, <-done)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]string{"job-1": "testTaskID", "job-2": "failed", "job-3": "testTaskID"}, results)
+}
+
+// Test_ConsumerAckError 确认或重新投递消息失败时回调 OnError
+func Test_ConsumerAckError(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			return jsonResponse(req, http.StatusServiceUnavailable, `<html>503</html>`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	queueDown := errors.New("queue down")
+	errs := make(chan error, 4)
+	src := make(chanSource)
+	c := consumer.New(client, src, consumer.Options{
+		OnError: func(body []byte, err error) { errs <- err },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+
+	next := func() error {
+		select {
+		case err := <-errs:
+			return err
+		case <-time.After(time.Second):
+			return nil
+		}
+	}
+
+	// 临时故障重新投递失败：先回调发送错误，再回调 Nack 错误
+	m := &chanMessage{body: `{"type":"single","single":{"cid":"0123456789abcdef0123456789abcdef"}}`, result: make(chan string, 1), ackErr: queueDown}
+	src <- m
+	assert.Equal(t, "nack", <-m.result)
+	assert.True(t, getui.IsTransient(next()))
+	assert.ErrorIs(t, next(), queueDown)
+
+	// 无法解析的消息确认失败
+	m = &chanMessage{body: `not json`, result: make(chan string, 1), ackErr: queueDown}
+	src <- m
+	assert.Equal(t, "ack", <-m.result)
+	assert.False(t, errors.Is(next(), queueDown))
+	assert.ErrorIs(t, next(), queueDown)
+
+	cancel()
+	assert.Nil(t, <-done)
+}
@@ -0,0 +1,89 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/printfcoder/getui/vaultcreds"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_FileCredentials 凭证文件轮换后，下次鉴权使用新凭证
+func Test_FileCredentials(t *testing.T) {
+	var appKeys []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := struct {
+			AppKey string `json:"appkey"`
+		}{}
+		json.NewDecoder(req.Body).Decode(&body)
+		appKeys = append(appKeys, body.AppKey)
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	path := filepath.Join(t.TempDir(), "getui.json")
+	write := func(appKey string) {
+		data := `{"app_id":"fileAppID","app_key":"` + appKey + `","master_secret":"testMasterSecret"}`
+		assert.Nil(t, os.WriteFile(path, []byte(data), 0600))
+	}
+	write("appKey1")
+
+	client, err := getui.New(getui.InitParams{
+		CredentialsProvider: getui.FileCredentials(path),
+		HTTPClient:          &http.Client{Transport: transport},
+		KeepTokenOnRefresh:  true,
+	})
+	assert.Nil(t, err)
+
+	write("appKey2")
+	assert.Nil(t, client.RefreshAuth(context.Background()))
+	assert.Equal(t, []string{"appKey1", "appKey2"}, appKeys)
+
+	// 凭证以凭证文件为准，不能直接修改
+	assert.ErrorIs(t, client.UpdateCredentials("appKey3", "otherSecret"), getui.ErrCredentialsProviderSet)
+	assert.Len(t, appKeys, 2)
+
+	_, err = getui.New(getui.InitParams{
+		CredentialsProvider: getui.FileCredentials(filepath.Join(t.TempDir(), "missing.json")),
+		HTTPClient:          &http.Client{Transport: transport},
+	})
+	assert.NotNil(t, err)
+}
+
+// Test_EnvCredentials 从环境变量读取凭证
+func Test_EnvCredentials(t *testing.T) {
+	t.Setenv("TEST_GETUI_APP_ID", "envAppID")
+	t.Setenv("TEST_GETUI_APP_KEY", "envAppKey")
+	t.Setenv("TEST_GETUI_MASTER_SECRET", "envMasterSecret")
+
+	creds, err := getui.EnvCredentials("TEST_GETUI_").Credentials(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, getui.Credentials{AppID: "envAppID", AppKey: "envAppKey", MasterSecret: "envMasterSecret"}, creds)
+
+	_, err = getui.EnvCredentials("TEST_MISSING_").Credentials(context.Background())
+	assert.NotNil(t, err)
+}
+
+// Test_VaultCredentials 从Vault KV v2读取凭证
+func Test_VaultCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/getui" || r.Header.Get("X-Vault-Token") != "vaultToken" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"app_id":"vaultAppID","app_key":"vaultAppKey","master_secret":"vaultMasterSecret"},"metadata":{"version":3}}}`))
+	}))
+	defer srv.Close()
+
+	creds, err := vaultcreds.New(srv.URL+"/", "vaultToken", "/secret/data/getui").Credentials(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, getui.Credentials{AppID: "vaultAppID", AppKey: "vaultAppKey", MasterSecret: "vaultMasterSecret"}, creds)
+
+	_, err = vaultcreds.New(srv.URL, "wrongToken", "secret/data/getui").Credentials(context.Background())
+	assert.NotNil(t, err)
+}
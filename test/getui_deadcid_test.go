@@ -0,0 +1,81 @@
+package getui
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_DeadCIDCollector 收集 successed_ignore 的单推与cid无效的回执
+func Test_DeadCIDCollector(t *testing.T) {
+	ignored := "0123456789abcdef0123456789abcdef"
+	online := "fedcba9876543210fedcba9876543210"
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			body, _ := io.ReadAll(req.Body)
+			if strings.Contains(string(body), ignored) {
+				return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"t1","status":"successed_ignore"}`), nil
+			}
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"t2","status":"successed_online"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	var mu sync.Mutex
+	var notified []string
+	collector := getui.NewDeadCIDCollector(func(ctx context.Context, d getui.DeadCID) {
+		mu.Lock()
+		defer mu.Unlock()
+		notified = append(notified, d.CID)
+	})
+	store := getui.NewMemoryStore()
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Store:        store,
+		DeadCIDs:     collector,
+	})
+	assert.Nil(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.PushToSingle(getui.SingleReqBody{CID: ignored})
+		assert.Nil(t, err)
+	}
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: online})
+	assert.Nil(t, err)
+
+	handler := getui.NewReceiptHandler(getui.ReceiptHandlerOptions{SkipVerify: true, Store: store, OnReceipt: collector.OnReceipt})
+	w := httptest.NewRecorder()
+	body := `{"cid":"uninstalled","taskid":"t3","actionId":"10003","recvtime":1760576400000}`
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mu.Lock()
+	assert.Equal(t, []string{ignored, "uninstalled"}, notified)
+	mu.Unlock()
+
+	dead := collector.Drain()
+	assert.Len(t, dead, 2)
+	assert.Equal(t, ignored, dead[0].CID)
+	assert.Equal(t, getui.DeadCIDIgnored, dead[0].Reason)
+	assert.Equal(t, getui.DeadCIDInvalid, dead[1].Reason)
+	assert.Equal(t, "t3", dead[1].TaskID)
+	assert.Len(t, collector.List(), 0)
+
+	// 从 Store 中的历史记录查询
+	loaded, err := getui.LoadDeadCIDs(context.Background(), store, getui.StoreQuery{Since: time.Unix(0, 0)})
+	assert.Nil(t, err)
+	assert.Len(t, loaded, 2)
+	assert.Equal(t, ignored, loaded[0].CID)
+	assert.Equal(t, "uninstalled", loaded[1].CID)
+}
@@ -0,0 +1,29 @@
+package getui
+
+import (
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_DebugSign 计算签名与auth body
+func Test_DebugSign(t *testing.T) {
+	ret, err := getui.DebugSign("testAppKey", "testMasterSecret", "1468389120000", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "3233e70a588523e1cb235570e96f9d6b1f6ba0568b47cd577634cc58cca3e6af", ret.Sign)
+	assert.Equal(t, `{"appkey":"testAppKey","timestamp":"1468389120000","sign":"`+ret.Sign+`"}`, string(ret.Body))
+
+	assert.Equal(t, getui.SignSHA256, ret.Algorithm)
+
+	_, err = getui.DebugSign("testAppKey", "testMasterSecret", "2016-07-13", "")
+	assert.NotNil(t, err)
+
+	// 旧版应用的md5签名
+	ret, err = getui.DebugSign("testAppKey", "testMasterSecret", "1468389120000", getui.SignMD5)
+	assert.Nil(t, err)
+	assert.Equal(t, "3e45664de8290d2c7a977a9017d1a352", ret.Sign)
+
+	_, err = getui.DebugSign("testAppKey", "testMasterSecret", "1468389120000", "sha1")
+	assert.NotNil(t, err)
+}
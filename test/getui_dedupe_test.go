@@ -0,0 +1,60 @@
+package getui
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_Dedupe 去重窗口内相同的推送只发送一次
+func Test_Dedupe(t *testing.T) {
+	pushCount := 0
+	fail := false
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			pushCount++
+			if fail {
+				return jsonResponse(req, http.StatusOK, `{"result":"flow_exceeded"}`), nil
+			}
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		DedupeWindow: time.Minute,
+	})
+	assert.Nil(t, err)
+
+	reqBody := getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}
+	reqBody.Message.MsgType = "notification"
+	reqBody.Notification.Style.Title = "订单已发货"
+
+	_, err = client.PushToSingle(reqBody)
+	assert.Nil(t, err)
+
+	// requestid 不同也视为重复
+	reqBody.RequestID = "another"
+	_, err = client.PushToSingle(reqBody)
+	assert.ErrorIs(t, err, getui.ErrDuplicatePush)
+	assert.Equal(t, 1, pushCount)
+
+	// 内容不同则正常发送
+	reqBody.Notification.Style.Title = "订单已签收"
+	fail = true
+	_, err = client.PushToSingle(reqBody)
+	assert.NotNil(t, err)
+
+	// 失败的推送允许重试
+	fail = false
+	_, err = client.PushToSingle(reqBody)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, pushCount)
+}
@@ -0,0 +1,99 @@
+package getui
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_PushDefaults 未设置的提示音、渠道使用客户端默认值，推送中设置的优先
+func Test_PushDefaults(t *testing.T) {
+	var bodies []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			data, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(data))
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Defaults: getui.PushDefaults{
+			Sound:        "default",
+			Channel:      "orders",
+			ChannelName:  "订单通知",
+			ChannelLevel: 3,
+			IsRing:       getui.Bool(true),
+			IsVibrate:    getui.Bool(true),
+		},
+	})
+	assert.Nil(t, err)
+
+	body := getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}
+	body.Notification.Style.Title = "标题"
+	_, err = client.PushToSingle(body)
+	assert.Nil(t, err)
+	assert.Contains(t, bodies[0], `"is_ring":true,"is_vibrate":true,"channel":"orders","channel_name":"订单通知","channel_level":3`)
+	assert.Contains(t, bodies[0], `"sound":"default"`)
+
+	body.Notification.Style.IsVibrate = getui.Bool(false)
+	body.Notification.Style.Channel = "marketing"
+	body.PushInfo.Aps.Sound = "coin.caf"
+	_, err = client.PushToSingle(body)
+	assert.Nil(t, err)
+	assert.Contains(t, bodies[1], `"is_ring":true,"is_vibrate":false,"channel":"marketing"`)
+	assert.NotContains(t, bodies[1], `订单通知`)
+	assert.Contains(t, bodies[1], `"sound":"coin.caf"`)
+}
+
+// Test_MessageDefaults 未设置的离线、消息类型、离线时长使用客户端默认值
+func Test_MessageDefaults(t *testing.T) {
+	var bodies []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "save_list_body") {
+			data, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(data))
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	params := getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Defaults: getui.PushDefaults{
+			IsOffline:        true,
+			MsgType:          "notification",
+			OfflineExpire:    time.Hour,
+			TransmissionType: true,
+		},
+	}
+	client, err := getui.New(params)
+	assert.Nil(t, err)
+
+	_, err = client.PushToList(getui.ListReqBody{CID: []string{"0123456789abcdef0123456789abcdef"}})
+	assert.Nil(t, err)
+	assert.Contains(t, bodies[0], `"is_offline":true,"offline_expire_time":3600000,"msgtype":"notification"`)
+	assert.Contains(t, bodies[0], `"transmission_type":true`)
+
+	body := getui.ListReqBody{CID: []string{"0123456789abcdef0123456789abcdef"}}
+	body.Message.MsgType = "transmission"
+	body.Message.OfflineExpireTime = 60000
+	_, err = client.PushToList(body)
+	assert.Nil(t, err)
+	assert.Contains(t, bodies[1], `"offline_expire_time":60000,"msgtype":"transmission"`)
+
+	params.Defaults.OfflineExpire = 100 * time.Hour
+	_, err = getui.New(params)
+	assert.NotNil(t, err)
+}
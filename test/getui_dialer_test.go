@@ -0,0 +1,40 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_DialContext 请求通过自定义的拨号函数建立连接
+func Test_DialContext(t *testing.T) {
+	errDial := errors.New("dial refused")
+	var addrs []string
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		addrs = append(addrs, addr)
+		return nil, errDial
+	}
+
+	_, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		DialContext:  dial,
+	})
+	assert.True(t, errors.Is(err, errDial))
+	assert.Equal(t, []string{"restapi.getui.com:443"}, addrs)
+
+	_, err = getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		DialContext:  dial,
+		HTTPClient:   &http.Client{},
+	})
+	assert.NotNil(t, err)
+}
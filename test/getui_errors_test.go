@@ -0,0 +1,62 @@
+package getui
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_SentinelErrors 常见错误可用 errors.Is 判断
+func Test_SentinelErrors(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.Contains(req.URL.Path, "user_status/"):
+			return jsonResponse(req, http.StatusOK, `{"result":"no_user"}`), nil
+		case strings.Contains(req.URL.Path, "stop_task/"):
+			return jsonResponse(req, http.StatusOK, `{"result":"taskid_error"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_app"):
+			return jsonResponse(req, http.StatusOK, `{"result":"not_auth"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_single"):
+			return jsonResponse(req, http.StatusOK, `{"result":"flow_exceeded"}`), nil
+		case strings.HasSuffix(req.URL.Path, "save_list_body"):
+			return jsonResponse(req, http.StatusTooManyRequests, `Too Many Requests`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	cid := "0123456789abcdef0123456789abcdef"
+
+	_, err = client.PushToSingle(getui.SingleReqBody{})
+	assert.ErrorIs(t, err, getui.ErrInvalidTarget)
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: "typo"})
+	assert.ErrorIs(t, err, getui.ErrInvalidTarget)
+
+	_, err = client.UserStatus(cid)
+	assert.ErrorIs(t, err, getui.ErrNoUser)
+	existed, err := client.UserExisted(cid)
+	assert.Nil(t, err)
+	assert.False(t, existed)
+
+	_, err = client.StopTask("testTaskID")
+	assert.ErrorIs(t, err, getui.ErrTaskNotFound)
+
+	_, err = client.PushToApp(getui.AppReqBody{Condition: []getui.AppReqBodyCondition{getui.NewCondition(getui.ConditionTag, getui.OptTypeOr, "vip")}})
+	assert.ErrorIs(t, err, getui.ErrTokenExpired)
+
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: cid})
+	assert.ErrorIs(t, err, getui.ErrRateLimited)
+
+	_, err = client.PushToList(getui.ListReqBody{CID: []string{cid}})
+	assert.ErrorIs(t, err, getui.ErrRateLimited)
+}
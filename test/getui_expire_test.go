@@ -0,0 +1,69 @@
+package getui
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_OfflineExpireValidate 推送前校验直接设置的毫秒值
+func Test_OfflineExpireValidate(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	// 超出72小时
+	reqBody := getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}
+	reqBody.Message.OfflineExpireTime = int64(7 * 24 * time.Hour / time.Millisecond)
+	_, err = client.PushToSingle(reqBody)
+	assert.NotNil(t, err)
+
+	appBody := getui.AppReqBody{Condition: []getui.AppReqBodyCondition{getui.NewCondition(getui.ConditionTag, getui.OptTypeOr, "vip")}}
+	appBody.Message.OfflineExpireTime = -1
+	_, err = client.PushToApp(appBody)
+	assert.NotNil(t, err)
+
+	// 换算为纳秒会溢出的毫秒值
+	reqBody.Message.OfflineExpireTime = 18446744074709
+	_, err = client.PushToSingle(reqBody)
+	assert.NotNil(t, err)
+
+	reqBody.Message.OfflineExpireTime = 3600000
+	_, err = client.PushToSingle(reqBody)
+	assert.Nil(t, err)
+}
+
+// Test_SetTTL 各类推送body统一设置离线时长，list推的两处离线时长一致
+func Test_SetTTL(t *testing.T) {
+	single := getui.SingleReqBody{}
+	assert.Nil(t, single.SetTTL(2*time.Hour))
+	assert.True(t, single.Message.IsOffline)
+	assert.Equal(t, int64(7200000), single.Message.OfflineExpireTime)
+
+	list := getui.ListReqBody{}
+	assert.Nil(t, list.SetTTL(time.Hour))
+	assert.True(t, list.Message.IsOffline)
+	assert.Equal(t, int64(3600000), list.OfflineExpireTime)
+	assert.Equal(t, int64(3600000), list.Message.OfflineExpireTime)
+
+	app := getui.AppReqBody{}
+	assert.Nil(t, app.SetTTL(getui.MaxOfflineExpire))
+	assert.Equal(t, int64(getui.MaxOfflineExpire/time.Millisecond), app.Message.OfflineExpireTime)
+
+	// 超出范围时不修改body
+	assert.NotNil(t, single.SetTTL(0))
+	assert.NotNil(t, list.SetTTL(-time.Second))
+	assert.NotNil(t, app.SetTTL(getui.MaxOfflineExpire+time.Millisecond))
+	assert.Equal(t, int64(7200000), single.Message.OfflineExpireTime)
+	assert.Equal(t, int64(3600000), list.OfflineExpireTime)
+}
@@ -0,0 +1,113 @@
+package getui
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// replayClient 使用golden文件创建客户端
+func replayClient(t *testing.T, file string) (getui.Client, *getui.Replayer) {
+	replayer, err := getui.NewReplayer(file)
+	assert.Nil(t, err)
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppSecret:    "testAppSecret",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: replayer},
+	})
+	assert.Nil(t, err)
+	return client, replayer
+}
+
+// Test_ReplaySingle 回放单推，锁定请求报文格式
+func Test_ReplaySingle(t *testing.T) {
+	client, replayer := replayClient(t, "testdata/push_single.json")
+
+	reqBody := getui.SingleReqBody{}
+	reqBody.Message.IsOffline = true
+	reqBody.Message.MsgType = "notification"
+	reqBody.Notification.Style.Text = "这是内容"
+	reqBody.Notification.Style.Type = 0
+	reqBody.Notification.Style.Title = "这是title"
+	reqBody.Notification.TransmissionType = true
+	reqBody.Notification.TransmissionContent = "透传内容"
+	reqBody.CID = "0123456789abcdef0123456789abcdef"
+
+	rsp, err := client.PushToSingle(reqBody)
+	assert.Nil(t, err)
+	assert.NotNil(t, rsp)
+	assert.Equal(t, "successed_online", rsp.Status)
+	assert.Equal(t, 0, replayer.Remaining())
+}
+
+// Test_ReplayList 回放tolist推送，save_list_body 与 push_list 两步
+func Test_ReplayList(t *testing.T) {
+	client, replayer := replayClient(t, "testdata/push_list.json")
+
+	reqBody := getui.ListReqBody{}
+	reqBody.Message.IsOffline = true
+	reqBody.Message.MsgType = "notification"
+	reqBody.OfflineExpireTime = 3600000
+	reqBody.Notification.Style.Text = "这是内容"
+	reqBody.Notification.Style.Title = "这是title"
+	reqBody.Notification.TransmissionType = true
+	reqBody.Notification.TransmissionContent = "透传内容"
+	reqBody.CID = []string{"0123456789abcdef0123456789abcdef", "fedcba9876543210fedcba9876543210"}
+
+	rsp, err := client.PushToList(reqBody)
+	assert.Nil(t, err)
+	assert.NotNil(t, rsp)
+	assert.Equal(t, "RASL-0516_ab4ZaRnOhH7pY2Eu0YQpd7", rsp.TaskID)
+	assert.Equal(t, 0, replayer.Remaining())
+}
+
+// Test_ReplayAuthExpire 解析个推返回的token过期时间
+func Test_ReplayAuthExpire(t *testing.T) {
+	client, _ := replayClient(t, "testdata/push_single.json")
+	assert.Equal(t, int64(1468389120000), client.AuthTokenExpireTime().UnixNano()/1e6)
+}
+
+// Test_RecorderAuthHeader 自定义的鉴权请求头同样脱敏，回放时按同一请求头比对
+func Test_RecorderAuthHeader(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "custom_header.json")
+	recorder := getui.NewRecorder(file, roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","status":"online"}`), nil
+	}))
+	recorder.AuthHeader = "X-Getui-Token"
+
+	params := getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		AuthHeader:   "X-Getui-Token",
+		HTTPClient:   &http.Client{Transport: recorder},
+	}
+	client, err := getui.New(params)
+	assert.Nil(t, err)
+	_, err = client.UserStatus("0123456789abcdef0123456789abcdef")
+	assert.Nil(t, err)
+	assert.Nil(t, recorder.Save())
+
+	data, err := os.ReadFile(file)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "testAuthToken")
+	interactions := recorder.Interactions()
+	assert.Equal(t, "[REDACTED]", interactions[1].RequestHeader["X-Getui-Token"])
+
+	replayer, err := getui.NewReplayer(file)
+	assert.Nil(t, err)
+	replayer.AuthHeader = "X-Getui-Token"
+	params.HTTPClient = &http.Client{Transport: replayer}
+	client, err = getui.New(params)
+	assert.Nil(t, err)
+	_, err = client.UserStatus("0123456789abcdef0123456789abcdef")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, replayer.Remaining())
+}
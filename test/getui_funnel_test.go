@@ -0,0 +1,59 @@
+package getui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_Funnel 按任务、按天统计漏斗
+func Test_Funnel(t *testing.T) {
+	day1 := time.Date(2026, 10, 15, 10, 0, 0, 0, getui.GetuiLocation)
+	day2 := day1.Add(24 * time.Hour)
+	pushes := []getui.PushRecord{
+		{TaskID: "task1", Endpoint: "push_list", TargetCount: 4, SentAt: day1},
+		{TaskID: "task2", Endpoint: "push_single", TargetCount: 1, SentAt: day1.Add(time.Hour)},
+		{TaskID: "task3", Endpoint: "push_list", TargetCount: 5, SentAt: day2},
+	}
+	receipt := func(taskID, cid, action string) getui.Receipt {
+		return getui.Receipt{TaskID: taskID, CID: cid, ActionID: action, RecvTime: day2.UnixNano() / int64(time.Millisecond)}
+	}
+	receipts := []getui.Receipt{
+		receipt("task1", "a", getui.ReceiptActionArrive),
+		receipt("task1", "a", getui.ReceiptActionArrive), // 重复回执
+		receipt("task1", "b", getui.ReceiptActionArrive),
+		receipt("task1", "a", getui.ReceiptActionClick),
+		receipt("task1", "c", getui.ReceiptActionInvalid),
+		receipt("task2", "d", getui.ReceiptActionArrive),
+		receipt("task3", "e", getui.ReceiptActionArrive),
+		receipt("other", "f", getui.ReceiptActionArrive), // 没有发送结果
+	}
+
+	byTask := getui.FunnelByTask(pushes, receipts)
+	assert.Len(t, byTask, 3)
+	assert.Equal(t, getui.FunnelStats{Key: "task1", Sent: 4, Delivered: 2, Clicked: 1, Invalid: 1, DeliveryRate: 0.5, ClickRate: 0.5}, byTask[0])
+
+	byDay := getui.FunnelByDay(pushes, receipts, nil)
+	assert.Len(t, byDay, 2)
+	assert.Equal(t, "2026-10-15", byDay[0].Key)
+	assert.Equal(t, int64(5), byDay[0].Sent)
+	assert.Equal(t, int64(3), byDay[0].Delivered)
+	assert.Equal(t, 0.2, byDay[1].DeliveryRate)
+
+	store := getui.NewMemoryStore()
+	ctx := context.Background()
+	for _, p := range pushes {
+		assert.Nil(t, store.SavePushResult(ctx, p))
+	}
+	for _, r := range receipts {
+		assert.Nil(t, store.SaveReceipt(ctx, r))
+	}
+	byEndpoint, err := getui.LoadFunnel(ctx, store, getui.StoreQuery{}, func(p getui.PushRecord) string { return p.Endpoint })
+	assert.Nil(t, err)
+	assert.Len(t, byEndpoint, 2)
+	assert.Equal(t, "push_list", byEndpoint[0].Key)
+	assert.Equal(t, int64(9), byEndpoint[0].Sent)
+}
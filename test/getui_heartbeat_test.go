@@ -0,0 +1,38 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_AuthHeartbeat 刷新周期为真实时长，支持小于1小时，超出token有效期时报错
+func Test_AuthHeartbeat(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	newClient := func(d time.Duration) error {
+		client, err := getui.New(getui.InitParams{
+			AppID:         "testAppID",
+			AppKey:        "testAppKey",
+			MasterSecret:  "testMasterSecret",
+			HTTPClient:    &http.Client{Transport: transport},
+			AuthHeartbeat: d,
+		})
+		if err == nil {
+			client.Shutdown(context.Background())
+		}
+		return err
+	}
+
+	for _, d := range []time.Duration{0, 30 * time.Minute, 20 * time.Hour, 24 * time.Hour, 20} {
+		assert.Nil(t, newClient(d), d.String())
+	}
+	for _, d := range []time.Duration{30 * time.Second, 25 * time.Hour, -time.Hour} {
+		assert.NotNil(t, newClient(d), d.String())
+	}
+}
@@ -0,0 +1,45 @@
+package getui
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_HostFailover 主域名连接失败时切换到备用地址，冷却期内优先使用备用地址
+func Test_HostFailover(t *testing.T) {
+	var hosts []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		hosts = append(hosts, req.URL.Host)
+		if req.URL.Host == "restapi.getui.com" {
+			return nil, errors.New("no such host")
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","status":"online"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		APIHosts:     []string{"https://restapi.getui.com/v1/", "https://backup.example.com/v1"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"restapi.getui.com", "backup.example.com"}, hosts)
+
+	hosts = nil
+	_, err = client.UserStatus("8b4ed2bfa6b22aa3a1c8a8b6e1b6c8b1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"backup.example.com"}, hosts)
+
+	_, err = getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		APIHosts:     []string{"restapi.getui.com"},
+	})
+	assert.NotNil(t, err)
+}
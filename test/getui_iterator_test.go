@@ -0,0 +1,65 @@
+package getui
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_TaskIterator 自动获取后续页
+func Test_TaskIterator(t *testing.T) {
+	const total = 5
+	var pages []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if !strings.HasSuffix(req.URL.Path, "task_list") {
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+		}
+
+		q := req.URL.Query()
+		if q.Get("type") == "history" && q.Get("page") == "2" {
+			return jsonResponse(req, http.StatusOK, `{"result":"other_error"}`), nil
+		}
+		page, _ := strconv.Atoi(q.Get("page"))
+		size, _ := strconv.Atoi(q.Get("size"))
+		pages = append(pages, q.Get("page"))
+
+		var items []string
+		for i := (page - 1) * size; i < page*size && i < total; i++ {
+			items = append(items, fmt.Sprintf(`{"taskid":"task%d","status":"scheduled"}`, i))
+		}
+		return jsonResponse(req, http.StatusOK, fmt.Sprintf(`{"result":"ok","total":%d,"data":[%s]}`, total, strings.Join(items, ","))), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	var ids []string
+	it := client.ScheduledTasks(2)
+	for it.Next() {
+		ids = append(ids, it.Value().TaskID)
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, []string{"task0", "task1", "task2", "task3", "task4"}, ids)
+	assert.Equal(t, []string{"1", "2", "3"}, pages)
+
+	// 第二页出错
+	ids = nil
+	it = client.HistoryTasks(time.Now().Add(-time.Hour), time.Now(), 2)
+	for it.Next() {
+		ids = append(ids, it.Value().TaskID)
+	}
+	assert.NotNil(t, it.Err())
+	assert.Equal(t, []string{"task0", "task1"}, ids)
+	assert.False(t, it.Next())
+}
@@ -0,0 +1,82 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_LazyAuth 初始化时不访问网络，首次请求时申请token
+func Test_LazyAuth(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		paths = append(paths, req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:])
+		mu.Unlock()
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","taskid":"t"}`), nil
+	})
+	params := getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		LazyAuth:     true,
+	}
+
+	// 从未请求的客户端关闭时也不访问网络
+	client, err := getui.New(params)
+	assert.Nil(t, err)
+	assert.Empty(t, client.AuthToken())
+	assert.Nil(t, client.Shutdown(context.Background()))
+	assert.Len(t, paths, 0)
+
+	client, err = getui.New(params)
+	assert.Nil(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.PushToSingle(getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"})
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, "testAuthToken", client.AuthToken())
+	assert.Nil(t, client.Authenticate(context.Background()))
+
+	mu.Lock()
+	assert.Equal(t, []string{"auth_sign", "push_single", "push_single", "push_single", "push_single"}, paths)
+	mu.Unlock()
+}
+
+// Test_Authenticate 主动完成鉴权
+func Test_Authenticate(t *testing.T) {
+	auths := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "auth_sign") {
+			auths++
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		LazyAuth:     true,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, auths)
+	assert.Nil(t, client.Authenticate(context.Background()))
+	assert.Nil(t, client.Authenticate(context.Background()))
+	assert.Equal(t, 1, auths)
+	assert.Equal(t, "testAuthToken", client.TokenInfo().Token)
+}
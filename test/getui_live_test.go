@@ -0,0 +1,122 @@
+//go:build live
+
+package getui
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// 真实环境测试，默认不编译，升级前用于确认与个推接口的兼容性：
+//
+//	GETUI_APP_ID=... GETUI_APP_KEY=... GETUI_MASTER_SECRET=... GETUI_TEST_CID=... go test -tags live -run Test_Live ./test/
+//
+// 会向 GETUI_TEST_CID 发送真实推送，请使用测试应用与测试设备
+
+// liveClient 使用环境变量中的凭证创建客户端，未配置时跳过
+func liveClient(t *testing.T) (getui.Client, string) {
+	t.Helper()
+	cid := os.Getenv("GETUI_TEST_CID")
+	if len(os.Getenv("GETUI_APP_KEY")) == 0 || len(cid) == 0 {
+		t.Skip("未配置 GETUI_APP_KEY 或 GETUI_TEST_CID")
+	}
+
+	client, err := getui.New(getui.InitParams{CredentialsProvider: getui.EnvCredentials("")})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		assert.Nil(t, client.Shutdown(ctx))
+	})
+	return client, cid
+}
+
+// liveBody 测试推送的消息
+func liveBody(title string) getui.SingleReqBody {
+	body := getui.SingleReqBody{}
+	body.Message.IsOffline = true
+	body.Message.MsgType = "notification"
+	body.Message.OfflineExpireTime = int64(time.Hour / time.Millisecond)
+	body.Notification.Style.Title = title
+	body.Notification.Style.Text = "getui 真实环境测试 " + time.Now().Format(time.RFC3339)
+	return body
+}
+
+// Test_LiveAuth 鉴权与刷新
+func Test_LiveAuth(t *testing.T) {
+	client, _ := liveClient(t)
+
+	info := client.TokenInfo()
+	assert.NotEmpty(t, info.Token)
+	assert.True(t, info.ExpireTime.After(time.Now()))
+
+	assert.Nil(t, client.RefreshAuth(context.Background()))
+	assert.NotEmpty(t, client.AuthToken())
+}
+
+// Test_LiveUserStatus 查询测试设备状态
+func Test_LiveUserStatus(t *testing.T) {
+	client, cid := liveClient(t)
+
+	status, err := client.UserStatus(cid)
+	assert.Nil(t, err)
+	if assert.NotNil(t, status) {
+		assert.Equal(t, "ok", status.Result)
+	}
+
+	existed, err := client.UserExisted(cid)
+	assert.Nil(t, err)
+	assert.True(t, existed)
+}
+
+// Test_LiveSingle 单推通知与透传
+func Test_LiveSingle(t *testing.T) {
+	client, cid := liveClient(t)
+
+	body := liveBody("单推")
+	body.CID = cid
+	ret, err := client.PushToSingle(body)
+	assert.Nil(t, err)
+	if assert.NotNil(t, ret) {
+		assert.NotEmpty(t, ret.TaskID)
+	}
+
+	content, err := client.EncodePayload(map[string]string{"kind": "live_test"})
+	assert.Nil(t, err)
+	body = liveBody("透传")
+	body.CID = cid
+	body.Message.MsgType = "transmission"
+	ret, err = client.SilentPush(context.Background(), body.WithTransmission(content))
+	assert.Nil(t, err)
+	assert.NotNil(t, ret)
+}
+
+// Test_LiveList list推及推送结果查询
+func Test_LiveList(t *testing.T) {
+	client, cid := liveClient(t)
+
+	single := liveBody("list推")
+	body := getui.ListReqBody{CID: []string{cid}, GroupName: "getui_live_test"}
+	body.Message = single.Message
+	body.Notification = single.Notification
+	body.OfflineExpireTime = single.Message.OfflineExpireTime
+
+	ret, err := client.PushToListChunked(context.Background(), body, 0)
+	assert.Nil(t, err)
+	if !assert.NotNil(t, ret) || !assert.Len(t, ret.Sent, 1) {
+		return
+	}
+	assert.Len(t, ret.FailedTargets(), 0)
+
+	taskID := ret.Sent[0].Ret.TaskID
+	results, err := client.PushResult(taskID)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+}
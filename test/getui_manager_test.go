@@ -0,0 +1,110 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_ManagerQuota 各应用独立限速，互不影响
+func Test_ManagerQuota(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	params := func(appID string) getui.InitParams {
+		return getui.InitParams{
+			AppID:        appID,
+			AppKey:       "testAppKey",
+			MasterSecret: "testMasterSecret",
+			HTTPClient:   &http.Client{Transport: transport},
+		}
+	}
+
+	manager := getui.NewManager()
+	assert.Nil(t, manager.Add("marketing", getui.AppConfig{InitParams: params("marketingAppID"), QPS: 20}))
+	assert.Nil(t, manager.Add("transactional", getui.AppConfig{InitParams: params("transactionalAppID")}))
+	assert.NotNil(t, manager.Add("marketing", getui.AppConfig{InitParams: params("marketingAppID")}))
+	assert.Equal(t, []string{"marketing", "transactional"}, manager.Names())
+
+	marketing, err := manager.Get("marketing")
+	assert.Nil(t, err)
+	transactional, err := manager.Get("transactional")
+	assert.Nil(t, err)
+
+	reqBody := getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}
+
+	// 营销应用被限速
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			marketing.PushToSingle(reqBody)
+		}()
+	}
+
+	// 事务应用不受影响
+	time.Sleep(10 * time.Millisecond)
+	txStart := time.Now()
+	_, err = transactional.PushToSingle(reqBody)
+	assert.Nil(t, err)
+	assert.Less(t, time.Since(txStart), 50*time.Millisecond)
+
+	wg.Wait()
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+
+	_, err = manager.Get("unknown")
+	assert.NotNil(t, err)
+	assert.Nil(t, manager.Shutdown(context.Background()))
+}
+
+// Test_ManagerConcurrency 并发配额
+func Test_ManagerConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inflight, peak := 0, 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			mu.Lock()
+			inflight++
+			if inflight > peak {
+				peak = inflight
+			}
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			inflight--
+			mu.Unlock()
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	manager := getui.NewManager()
+	assert.Nil(t, manager.Add("app", getui.AppConfig{
+		InitParams: getui.InitParams{
+			AppID:        "testAppID",
+			AppKey:       "testAppKey",
+			MasterSecret: "testMasterSecret",
+			HTTPClient:   &http.Client{Transport: transport},
+		},
+		MaxConcurrency: 2,
+	}))
+	client, _ := manager.Get("app")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.PushToSingle(getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"})
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 2, peak)
+}
@@ -0,0 +1,56 @@
+package getui
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// histMetrics 记录直方图观测值的 memMetrics
+type histMetrics struct {
+	*memMetrics
+	hmu          sync.Mutex
+	observations map[string][]float64
+}
+
+func (m *histMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {
+	m.hmu.Lock()
+	defer m.hmu.Unlock()
+	key := metricKey(name, labels)
+	m.observations[key] = append(m.observations[key], value)
+}
+
+// Test_RequestMetrics 按接口上报请求次数与耗时
+func Test_RequestMetrics(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.Contains(req.URL.Path, "user_status") {
+			return jsonResponse(req, http.StatusOK, `{"result":"no_user"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","taskid":"testTaskID"}`), nil
+	})
+	metrics := &histMetrics{memMetrics: newMemMetrics(), observations: map[string][]float64{}}
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Metrics:      metrics,
+	})
+	assert.Nil(t, err)
+
+	_, err = client.PushToList(getui.ListReqBody{CID: []string{"0123456789abcdef0123456789abcdef"}})
+	assert.Nil(t, err)
+	_, err = client.UserStatus("0123456789abcdef0123456789abcdef")
+	assert.NotNil(t, err)
+
+	assert.Equal(t, float64(1), metrics.counter(getui.MetricRequests+",result=ok,endpoint=auth_sign"))
+	assert.Equal(t, float64(1), metrics.counter(getui.MetricRequests+",result=ok,endpoint=save_list_body"))
+	assert.Equal(t, float64(1), metrics.counter(getui.MetricRequests+",result=ok,endpoint=push_list"))
+	assert.Equal(t, float64(1), metrics.counter(getui.MetricRequests+",result=error,endpoint=user_status"))
+	assert.Len(t, metrics.observations[getui.MetricRequestDuration+",result=ok,endpoint=save_list_body"], 1)
+	assert.Len(t, metrics.observations[getui.MetricRequestDuration+",result=error,endpoint=user_status"], 1)
+}
@@ -0,0 +1,42 @@
+package getui
+
+import (
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_MessageTemplate text/template 语法的消息文案
+func Test_MessageTemplate(t *testing.T) {
+	tpl, err := getui.ParseMessageTemplate(getui.MessageTemplateSource{
+		Title:        `{{default "朋友" .name}}，你的订单已发货`,
+		Text:         `订单 {{.id}}：{{truncate 6 .item}}`,
+		Transmission: `{"id":"{{.id}}"}`,
+	}, getui.MissingKeyError)
+	assert.Nil(t, err)
+
+	body := getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}
+	err = tpl.Apply(&body.Notification, &body.PushInfo, map[string]string{"id": "42", "item": "机械键盘与鼠标套装", "name": ""})
+	assert.Nil(t, err)
+	assert.Equal(t, "朋友，你的订单已发货", body.Notification.Style.Title)
+	assert.Equal(t, "订单 42：机械键盘与…", body.Notification.Style.Text)
+	assert.Equal(t, body.Notification.Style.Text, body.PushInfo.Aps.Alert.Body)
+	assert.Equal(t, `{"id":"42"}`, body.Notification.TransmissionContent)
+
+	// 缺少变量
+	_, err = tpl.Render(map[string]string{"id": "42"})
+	assert.NotNil(t, err)
+
+	lenient, err := getui.ParseMessageTemplate(getui.MessageTemplateSource{Text: "你好{{.name}}"}, getui.MissingKeyEmpty)
+	assert.Nil(t, err)
+	m, err := lenient.Render(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "你好", m.Text)
+
+	// 语法错误与不存在的函数在解析时报错
+	_, err = getui.ParseMessageTemplate(getui.MessageTemplateSource{Title: "{{.name"}, getui.MissingKeyError)
+	assert.NotNil(t, err)
+	_, err = getui.ParseMessageTemplate(getui.MessageTemplateSource{Title: `{{env "HOME"}}`}, getui.MissingKeyError)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,55 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_OnError 失败的请求调用 OnError，成功的请求不调用
+func Test_OnError(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "push_single"):
+			return jsonResponse(req, http.StatusOK, `{"result":"no_user"}`), nil
+		case strings.Contains(req.URL.Path, "user_status"):
+			return jsonResponse(req, http.StatusServiceUnavailable, ""), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	var infos []getui.ErrorInfo
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		OnError: func(ctx context.Context, info getui.ErrorInfo) {
+			infos = append(infos, info)
+		},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, infos, 0)
+
+	ctx := getui.WithRequestID(context.Background(), "req-1")
+	_, err = client.PushToSingleContext(ctx, getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"})
+	assert.NotNil(t, err)
+	_, err = client.UserStatus("0123456789abcdef0123456789abcdef")
+	assert.NotNil(t, err)
+
+	assert.Len(t, infos, 2)
+	assert.Equal(t, "push_single", infos[0].Endpoint)
+	assert.Equal(t, "POST", infos[0].Method)
+	assert.Equal(t, "req-1", infos[0].RequestID)
+	assert.Equal(t, "no_user", infos[0].Result)
+	assert.True(t, errors.Is(infos[0].Err, getui.ErrNoUser))
+
+	assert.Equal(t, "user_status", infos[1].Endpoint)
+	assert.Equal(t, http.StatusServiceUnavailable, infos[1].StatusCode)
+	assert.Equal(t, "", infos[1].Result)
+}
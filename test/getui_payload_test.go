@@ -0,0 +1,52 @@
+package getui
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_EncodePayload 透传内容默认按JSON序列化，可替换为二进制格式
+func Test_EncodePayload(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	params := getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	}
+
+	client, err := getui.New(params)
+	assert.Nil(t, err)
+	content, err := client.EncodePayload(map[string]interface{}{"order": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"order":1}`, content)
+	content, err = client.EncodePayload("raw")
+	assert.Nil(t, err)
+	assert.Equal(t, "raw", content)
+	_, err = client.EncodePayload(func() {})
+	assert.NotNil(t, err)
+
+	// 模拟 msgpack.Marshal 等二进制序列化
+	params.PayloadSerializer = getui.Base64Payload(func(v interface{}) ([]byte, error) {
+		if b, ok := v.([]byte); ok {
+			return b, nil
+		}
+		return nil, errors.New("unsupported")
+	})
+	client, err = getui.New(params)
+	assert.Nil(t, err)
+	content, err = client.EncodePayload([]byte{0x01, 0xff})
+	assert.Nil(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte{0x01, 0xff}), content)
+	body := getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}.WithTransmission(content)
+	assert.Equal(t, content, body.Notification.TransmissionContent)
+	_, err = client.EncodePayload("x")
+	assert.NotNil(t, err)
+}
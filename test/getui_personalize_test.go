@@ -0,0 +1,90 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_PushPersonalized 按收件人渲染，每批200条通过 push_single_batch 发送
+func Test_PushPersonalized(t *testing.T) {
+	var batches [][]getui.SingleReqBody
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single_batch") {
+			body := struct {
+				MsgList []getui.SingleReqBody `json:"msg_list"`
+			}{}
+			json.NewDecoder(req.Body).Decode(&body)
+			batches = append(batches, body.MsgList)
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":{}}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		DedupeWindow: time.Minute,
+	})
+	assert.Nil(t, err)
+
+	msg, err := getui.ParseMessageTemplate(getui.MessageTemplateSource{
+		Title:        "发货通知",
+		Text:         "你好 {{.name}}，你的订单 {{.id}} 已发货",
+		Transmission: `{"type":"order","id":"{{.id}}"}`,
+	}, getui.MissingKeyError)
+	assert.Nil(t, err)
+	p := getui.Personalization{Message: msg, Vars: map[string]map[string]string{}}
+	for i := 0; i < 250; i++ {
+		p.Vars[fmt.Sprintf("%032x", i)] = map[string]string{"name": fmt.Sprint("用户", i), "id": fmt.Sprint(i)}
+	}
+
+	ret, err := client.PushPersonalized(getui.WithRequestID(context.Background(), "trace"), p)
+	assert.Nil(t, err)
+	assert.Len(t, ret.Sent, 2)
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[0], 200)
+	assert.Len(t, batches[1], 50)
+
+	first := batches[0][0]
+	assert.Equal(t, fmt.Sprintf("%032x", 0), first.CID)
+	assert.Equal(t, "你好 用户0，你的订单 0 已发货", first.Notification.Style.Text)
+	assert.Equal(t, `{"type":"order","id":"0"}`, first.Notification.TransmissionContent)
+	assert.Equal(t, "发货通知", first.PushInfo.Aps.Alert.Title)
+	assert.Equal(t, "trace-0", first.RequestID)
+	assert.Equal(t, "testAppKey", first.Message.AppKey)
+
+	// 与单推一样去重，窗口期内重复的消息不发送
+	batches = nil
+	ret, err = client.PushPersonalized(context.Background(), p)
+	assert.ErrorIs(t, err, getui.ErrDuplicatePush)
+	assert.Len(t, ret.Failed, 2)
+	assert.Len(t, batches, 0)
+
+	// 与单推一样校验，回执地址错误时不发送
+	batches = nil
+	bad := p
+	bad.Template.CallbackURL = "ftp://example.com/receipt"
+	_, err = client.PushPersonalized(context.Background(), bad)
+	assert.NotNil(t, err)
+	assert.Len(t, batches, 0)
+
+	// 缺少变量时不发送
+	p.Vars[fmt.Sprintf("%032x", 999)] = map[string]string{"name": "无订单"}
+	_, err = client.PushPersonalized(context.Background(), p)
+	assert.NotNil(t, err)
+	assert.Len(t, batches, 0)
+
+	// 文案模板必填
+	_, err = client.PushPersonalized(context.Background(), getui.Personalization{Vars: p.Vars})
+	assert.NotNil(t, err)
+	assert.Len(t, batches, 0)
+}
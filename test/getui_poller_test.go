@@ -0,0 +1,110 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// memMetrics 内存中的指标
+type memMetrics struct {
+	mu       sync.Mutex
+	gauges   map[string]float64
+	counters map[string]float64
+}
+
+func newMemMetrics() *memMetrics {
+	return &memMetrics{gauges: map[string]float64{}, counters: map[string]float64{}}
+}
+
+func metricKey(name string, labels map[string]string) string {
+	key := name
+	for _, k := range []string{"taskid", "result", "endpoint"} {
+		if v, ok := labels[k]; ok {
+			key += "," + k + "=" + v
+		}
+	}
+	return key
+}
+
+func (m *memMetrics) SetGauge(name string, value float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gauges[metricKey(name, labels)] = value
+}
+
+func (m *memMetrics) AddCounter(name string, delta float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[metricKey(name, labels)] += delta
+}
+
+func (m *memMetrics) gauge(key string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.gauges[key]
+}
+
+func (m *memMetrics) counter(key string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[key]
+}
+
+// Test_ReportPoller 轮询最近发送任务的推送结果并上报指标
+func Test_ReportPoller(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "push_single"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"storedTaskID"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_result"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","data":[
+				{"taskId":"storedTaskID","clickNum":1,"GT":{"sent":8,"feedback":6},"apn":{"sent":2,"feedback":2}},
+				{"taskId":"trackedTaskID","GT":{"sent":4,"feedback":1}}]}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	store := getui.NewMemoryStore()
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Store:        store,
+	})
+	assert.Nil(t, err)
+
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"})
+	assert.Nil(t, err)
+
+	_, err = getui.NewReportPoller(client, getui.ReportPollerOptions{})
+	assert.NotNil(t, err)
+
+	metrics := newMemMetrics()
+	poller, err := getui.NewReportPoller(client, getui.ReportPollerOptions{
+		Metrics:  metrics,
+		Interval: 10 * time.Millisecond,
+		Store:    store,
+	})
+	assert.Nil(t, err)
+	poller.Track("trackedTaskID")
+
+	assert.Eventually(t, func() bool {
+		return metrics.gauge("getui_task_sent,taskid=trackedTaskID") == 4
+	}, time.Second, 10*time.Millisecond)
+	assert.Nil(t, poller.Close(context.Background()))
+
+	assert.Equal(t, 10.0, metrics.gauge("getui_task_sent,taskid=storedTaskID"))
+	assert.Equal(t, 8.0, metrics.gauge("getui_task_delivered,taskid=storedTaskID"))
+	assert.Equal(t, 0.8, metrics.gauge("getui_task_delivery_rate,taskid=storedTaskID"))
+	assert.Equal(t, 1.0, metrics.gauge("getui_task_clicked,taskid=storedTaskID"))
+	assert.Equal(t, 0.25, metrics.gauge("getui_task_delivery_rate,taskid=trackedTaskID"))
+	assert.True(t, metrics.counters["getui_report_polls_total,result=ok"] >= 1)
+}
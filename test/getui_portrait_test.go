@@ -0,0 +1,34 @@
+package getui
+
+import (
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_PortraitCondition 按画像标签或名称生成画像条件
+func Test_PortraitCondition(t *testing.T) {
+	cond := getui.PortraitCondition(getui.OptTypeOr, getui.PortraitAge18To24, getui.PortraitAge25To34)
+	assert.Equal(t, getui.ConditionPortrait, cond.Key)
+	assert.Equal(t, []string{string(getui.PortraitAge18To24), string(getui.PortraitAge25To34)}, cond.Values)
+	assert.Equal(t, getui.OptTypeOr, cond.OptType)
+
+	cond, err := getui.PortraitConditionByName(getui.OptTypeAnd, [2]string{"性别", "女"}, [2]string{"兴趣", "旅游"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{string(getui.PortraitFemale), string(getui.PortraitTraveler)}, cond.Values)
+
+	_, err = getui.PortraitConditionByName(getui.OptTypeOr, [2]string{"性别", "未知"})
+	assert.ErrorIs(t, err, getui.ErrInvalidTarget)
+
+	info, ok := getui.PortraitTagByID(getui.PortraitMale)
+	assert.True(t, ok)
+	assert.Equal(t, "性别", info.Category)
+	assert.Len(t, getui.PortraitTagsInCategory("性别"), 2)
+
+	// 补充账号开通的画像标签
+	getui.RegisterPortraitTags(getui.PortraitTagInfo{Tag: "9001", Category: "自定义人群", Name: "高活跃"})
+	cond, err = getui.PortraitConditionByName(getui.OptTypeOr, [2]string{"自定义人群", "高活跃"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"9001"}, cond.Values)
+}
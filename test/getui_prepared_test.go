@@ -0,0 +1,80 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_PreparedPush 评审时构建整批推送，上线时发送，发送的JSON与构建时一致
+func Test_PreparedPush(t *testing.T) {
+	var sent []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") || strings.HasSuffix(req.URL.Path, "push_app") {
+			data, _ := io.ReadAll(req.Body)
+			sent = append(sent, string(data))
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Defaults:     getui.PushDefaults{Sound: "default"},
+	})
+	assert.Nil(t, err)
+	ctx := context.Background()
+
+	single := getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}
+	single.Notification.Style.Title = "标题"
+	app := &getui.AppReqBody{Condition: []getui.AppReqBodyCondition{getui.NewCondition(getui.ConditionTag, getui.OptTypeOr, "vip")}}
+	app.Notification.Style.Title = "会员专享"
+
+	// 校验不通过时列出所有不通过的body，不发送请求
+	_, err = client.Prepare(ctx, single, getui.SingleReqBody{CID: "bad"}, "body")
+	var perrs getui.PrepareErrors
+	assert.True(t, errors.As(err, &perrs))
+	assert.Len(t, perrs, 2)
+	assert.ErrorIs(t, perrs[1], getui.ErrInvalidTarget)
+	assert.Len(t, sent, 0)
+
+	pushes, err := client.Prepare(ctx, single, app)
+	assert.Nil(t, err)
+	assert.Len(t, pushes, 2)
+	assert.Equal(t, "push_single", pushes[0].Endpoint())
+	assert.Equal(t, []string{single.CID}, pushes[0].Targets())
+	assert.NotEmpty(t, pushes[0].RequestID())
+	assert.Contains(t, string(pushes[0].JSON()), `"sound":"default"`)
+	assert.Equal(t, "push_app", pushes[1].Endpoint())
+	assert.Len(t, sent, 0)
+
+	ret, err := client.SendPrepared(ctx, pushes)
+	assert.Nil(t, err)
+	assert.Len(t, ret.Sent, 2)
+	assert.Equal(t, string(pushes[0].JSON()), sent[0])
+	assert.Equal(t, string(pushes[1].JSON()), sent[1])
+	assert.Equal(t, pushes[0].RequestID(), ret.Sent[0].Ret.RequestID)
+
+	// 其他应用发送时记为失败
+	other := getui.WithApp(ctx, getui.AppDescriptor{AppID: "otherAppID", AppKey: "otherAppKey", MasterSecret: "otherSecret"})
+	ret, err = client.SendPrepared(other, pushes[:1])
+	assert.NotNil(t, err)
+	assert.Len(t, ret.Failed, 1)
+	assert.ErrorIs(t, ret.Failed[0].Err, getui.ErrPreparedAppMismatch)
+
+	// ctx 已取消时不再发送
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	ret, err = client.SendPrepared(canceled, pushes)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, ret.NotAttempted, 2)
+	assert.Len(t, sent, 2)
+}
@@ -0,0 +1,54 @@
+package getui
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_BuildRequest 预览的JSON与实际发送的一致
+func Test_BuildRequest(t *testing.T) {
+	var sent []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			data, _ := io.ReadAll(req.Body)
+			sent = append(sent, string(data))
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Defaults:     getui.PushDefaults{Sound: "default"},
+	})
+	assert.Nil(t, err)
+
+	body := getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef", RequestID: "req1"}
+	body.Notification.Style.Title = "标题"
+
+	preview, err := client.BuildRequest(body)
+	assert.Nil(t, err)
+	assert.Contains(t, string(preview), `"appkey":"testAppKey"`)
+	assert.Contains(t, string(preview), `"sound":"default"`)
+	assert.Len(t, sent, 0)
+
+	_, err = client.PushToSingle(body)
+	assert.Nil(t, err)
+	assert.Equal(t, string(preview), sent[0])
+
+	preview, err = client.BuildRequest(&getui.ListReqBody{CID: []string{"0123456789abcdef0123456789abcdef"}})
+	assert.Nil(t, err)
+	assert.Contains(t, string(preview), `"need_detail":true`)
+
+	_, err = client.BuildRequest(getui.SingleReqBody{CID: "bad"})
+	assert.ErrorIs(t, err, getui.ErrInvalidTarget)
+	_, err = client.BuildRequest("body")
+	assert.NotNil(t, err)
+}
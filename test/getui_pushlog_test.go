@@ -0,0 +1,50 @@
+package getui
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_PushLog 每次推送输出一行JSON日志
+func Test_PushLog(t *testing.T) {
+	replayer, err := getui.NewReplayer("testdata/push_list.json")
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: replayer},
+		PushLog:      buf,
+	})
+	assert.Nil(t, err)
+
+	reqBody := getui.ListReqBody{}
+	reqBody.Message.IsOffline = true
+	reqBody.Message.MsgType = "notification"
+	reqBody.OfflineExpireTime = 3600000
+	reqBody.Notification.Style.Text = "这是内容"
+	reqBody.Notification.Style.Title = "这是title"
+	reqBody.Notification.TransmissionType = true
+	reqBody.Notification.TransmissionContent = "透传内容"
+	reqBody.CID = []string{"0123456789abcdef0123456789abcdef", "fedcba9876543210fedcba9876543210"}
+	_, err = client.PushToList(reqBody)
+	assert.Nil(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 1)
+
+	entry := getui.PushLogEntry{}
+	assert.Nil(t, json.Unmarshal(lines[0], &entry))
+	assert.Equal(t, "push_list", entry.Endpoint)
+	assert.Equal(t, "RASL-0516_ab4ZaRnOhH7pY2Eu0YQpd7", entry.TaskID)
+	assert.Equal(t, 2, entry.TargetCount)
+	assert.Equal(t, "ok", entry.Result)
+	assert.Equal(t, "successed_online", entry.Status)
+}
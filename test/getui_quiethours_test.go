@@ -0,0 +1,125 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_QuietHours 时段判断，支持跨越午夜
+func Test_QuietHours(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*60*60)
+	q, err := getui.ParseQuietHours("22:00-08:00", shanghai)
+	assert.Nil(t, err)
+
+	at := func(hour, minute int) time.Time { return time.Date(2026, 10, 16, hour, minute, 0, 0, shanghai) }
+	assert.True(t, q.Contains(at(23, 30)))
+	assert.True(t, q.Contains(at(7, 59)))
+	assert.False(t, q.Contains(at(8, 0)))
+	assert.False(t, q.Contains(at(12, 0)))
+	assert.Equal(t, 9*time.Hour, q.Until(at(23, 0)))
+	assert.Equal(t, time.Hour, q.Until(at(7, 0)))
+	assert.Equal(t, time.Duration(0), q.Until(at(12, 0)))
+
+	// 同一时刻在其它时区表示
+	assert.True(t, q.Contains(at(23, 30).UTC()))
+
+	_, err = getui.ParseQuietHours("22:00", shanghai)
+	assert.NotNil(t, err)
+	_, err = getui.ParseQuietHours("25:00-08:00", shanghai)
+	assert.NotNil(t, err)
+}
+
+// Test_SenderQuietHours 免打扰时段内的任务在时段结束后发送，Urgent 任务立即发送
+func Test_SenderQuietHours(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	// 从现在开始持续300毫秒的免打扰时段
+	now := time.Now().In(getui.GetuiLocation)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, getui.GetuiLocation)
+	start := now.Sub(midnight)
+	quiet := &getui.QuietHours{Start: start, End: start + 300*time.Millisecond}
+	sender := getui.NewSender(client, getui.SenderOptions{Workers: 2, QuietHours: quiet})
+
+	var mu sync.Mutex
+	sent := map[string]time.Time{}
+	job := func(name string, urgent bool) getui.Job {
+		return getui.Job{
+			Priority: getui.PriorityMarketing,
+			Urgent:   urgent,
+			Single:   &getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"},
+			Callback: func(rsp *getui.RspBody, err error) {
+				assert.Nil(t, err)
+				mu.Lock()
+				sent[name] = time.Now()
+				mu.Unlock()
+			},
+		}
+	}
+
+	submitted := time.Now()
+	ctx := context.Background()
+	assert.Nil(t, sender.Submit(ctx, job("campaign", false)))
+	assert.Nil(t, sender.Submit(ctx, job("otp", true)))
+
+	closeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	assert.Nil(t, sender.Close(closeCtx))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, sent, 2)
+	assert.Less(t, sent["otp"].Sub(submitted), 200*time.Millisecond)
+	assert.GreaterOrEqual(t, sent["campaign"].Sub(submitted), 250*time.Millisecond)
+}
+
+// Test_SenderHeldLimit 暂存任务超出 MaxHeld 时返回错误，关闭超时后暂存的任务回调 ErrSenderClosed
+func Test_SenderHeldLimit(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	// 从现在开始持续1小时的免打扰时段
+	now := time.Now().In(getui.GetuiLocation)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, getui.GetuiLocation)
+	start := now.Sub(midnight)
+	quiet := &getui.QuietHours{Start: start, End: start + time.Hour}
+	sender := getui.NewSender(client, getui.SenderOptions{Workers: 2, QuietHours: quiet, MaxHeld: 1})
+
+	results := make(chan error, 1)
+	job := getui.Job{
+		Priority: getui.PriorityMarketing,
+		Single:   &getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"},
+		Callback: func(rsp *getui.RspBody, err error) { results <- err },
+	}
+	ctx := context.Background()
+	assert.Nil(t, sender.Submit(ctx, job))
+	assert.ErrorIs(t, sender.Submit(ctx, job), getui.ErrHeldFull)
+
+	closeCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, sender.Close(closeCtx), context.DeadlineExceeded)
+	assert.ErrorIs(t, <-results, getui.ErrSenderClosed)
+	assert.ErrorIs(t, sender.Submit(ctx, job), getui.ErrSenderClosed)
+}
@@ -0,0 +1,36 @@
+package getui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_ParseReceipt 解析回执
+func Test_ParseReceipt(t *testing.T) {
+	cases := []struct {
+		body string
+		want getui.ReceiptType
+	}{
+		{`{"cid":"0123456789abcdef0123456789abcdef","taskid":"t","msgid":"m","actionId":"10001","code":"0","recvtime":1760576400000}`, getui.ReceiptArrived},
+		{`{"cid":"0123456789abcdef0123456789abcdef","taskid":"t","msgid":"m","actionId":"10002","code":"0","recvtime":"1760576400000"}`, getui.ReceiptClicked},
+		{`{"cid":"0123456789abcdef0123456789abcdef","taskid":"t","msgid":"m","actionId":"10003","code":"1"}`, getui.ReceiptInvalidCID},
+		{`{"cid":"0123456789abcdef0123456789abcdef","taskid":"t","msgid":"m","code":"0"}`, getui.ReceiptArrived},
+		{`{"cid":"0123456789abcdef0123456789abcdef","taskid":"t","msgid":"m","actionId":"99999"}`, getui.ReceiptUnknown},
+	}
+	for _, c := range cases {
+		r, err := getui.ParseReceipt([]byte(c.body))
+		assert.Nil(t, err, c.body)
+		assert.Equal(t, c.want, r.Type(), c.body)
+		assert.Equal(t, "0123456789abcdef0123456789abcdef", r.CID)
+	}
+
+	r, err := getui.ParseReceipt([]byte(cases[1].body))
+	assert.Nil(t, err)
+	assert.True(t, r.Time().Equal(time.Unix(1760576400, 0)))
+
+	_, err = getui.ParseReceipt([]byte(`{"recvtime":"abc"}`))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,108 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_ReceiptHandler 挂载在路由上的回执接口
+func Test_ReceiptHandler(t *testing.T) {
+	cid := "0123456789abcdef0123456789abcdef"
+	var got []*getui.Receipt
+	var fail bool
+
+	mux := http.NewServeMux()
+	mux.Handle("/getui/receipt", getui.NewReceiptHandler(getui.ReceiptHandlerOptions{
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		OnReceipt: func(ctx context.Context, r *getui.Receipt) error {
+			if fail {
+				return errors.New("db down")
+			}
+			got = append(got, r)
+			return nil
+		},
+	}))
+
+	post := func(method, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(method, "/getui/receipt", strings.NewReader(body)))
+		return w
+	}
+
+	sign := getui.CallbackSign("testAppKey", cid, "testTaskID", "testMsgID", "testMasterSecret")
+	body := fmt.Sprintf(`{"cid":"%s","taskid":"testTaskID","msgid":"testMsgID","actionId":"10002","code":"0","sign":"%s"}`, cid, sign)
+
+	w := post(http.MethodPost, body)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"result":"ok"}`, w.Body.String())
+	assert.Len(t, got, 1)
+	assert.Equal(t, getui.ReceiptClicked, got[0].Type())
+
+	assert.Equal(t, http.StatusUnauthorized, post(http.MethodPost, strings.Replace(body, sign, strings.Repeat("0", 32), 1)).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, post(http.MethodGet, "").Code)
+
+	fail = true
+	assert.Equal(t, http.StatusInternalServerError, post(http.MethodPost, body).Code)
+	assert.Len(t, got, 1)
+}
+
+// Test_CampaignCallbackURL 推送携带活动的回执地址，回执接口按活动区分回执
+func Test_CampaignCallbackURL(t *testing.T) {
+	callbackURL, err := getui.CampaignCallbackURL("https://example.com/getui/receipt?env=prod", "spring")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/getui/receipt?campaign=spring&env=prod", callbackURL)
+	_, err = getui.CampaignCallbackURL("/getui/receipt", "spring")
+	assert.NotNil(t, err)
+
+	var sent []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") || strings.HasSuffix(req.URL.Path, "save_list_body") {
+			body := struct {
+				CallbackURL string `json:"callback_url"`
+			}{}
+			json.NewDecoder(req.Body).Decode(&body)
+			sent = append(sent, body.CallbackURL)
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","taskid":"testTaskID"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	cid := "0123456789abcdef0123456789abcdef"
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: cid, CallbackURL: callbackURL})
+	assert.Nil(t, err)
+	_, err = client.PushToList(getui.ListReqBody{CID: []string{cid}, CallbackURL: callbackURL})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{callbackURL, callbackURL}, sent)
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: cid, CallbackURL: "ftp://example.com"})
+	assert.NotNil(t, err)
+
+	var got *getui.Receipt
+	handler := getui.NewReceiptHandler(getui.ReceiptHandlerOptions{
+		SkipVerify: true,
+		OnReceipt: func(ctx context.Context, r *getui.Receipt) error {
+			got = r
+			return nil
+		},
+	})
+	w := httptest.NewRecorder()
+	body := fmt.Sprintf(`{"cid":"%s","taskid":"testTaskID","msgid":"testMsgID","actionId":"10001","code":"0"}`, cid)
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, callbackURL, strings.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "spring", got.Campaign)
+}
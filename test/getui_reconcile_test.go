@@ -0,0 +1,72 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_Reconciler 按回执计算到达率，到达率异常的任务只通知一次
+func Test_Reconciler(t *testing.T) {
+	ctx := context.Background()
+	store := getui.NewMemoryStore()
+	sentAt := time.Now().Add(-time.Hour)
+	for _, taskID := range []string{"healthy", "silent", "small"} {
+		count := 20
+		if taskID == "small" {
+			count = 2
+		}
+		assert.Nil(t, store.SavePushResult(ctx, getui.PushRecord{TaskID: taskID, Endpoint: "push_list", TargetCount: count, SentAt: sentAt}))
+	}
+	// 刚发送的任务还在等待回执
+	assert.Nil(t, store.SavePushResult(ctx, getui.PushRecord{TaskID: "recent", Endpoint: "push_list", TargetCount: 20, SentAt: time.Now()}))
+
+	recvTime := sentAt.Add(time.Minute).UnixNano() / int64(time.Millisecond)
+	for i := 0; i < 18; i++ {
+		assert.Nil(t, store.SaveReceipt(ctx, getui.Receipt{TaskID: "healthy", CID: fmt.Sprint(i), ActionID: getui.ReceiptActionArrive, RecvTime: recvTime}))
+	}
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, store.SaveReceipt(ctx, getui.Receipt{TaskID: "silent", CID: fmt.Sprint(i), ActionID: getui.ReceiptActionArrive, RecvTime: recvTime}))
+	}
+
+	var mu sync.Mutex
+	var anomalies []getui.DeliveryAnomaly
+	metrics := newMemMetrics()
+	r, err := getui.NewReconciler(getui.ReconcilerOptions{
+		Store:    store,
+		Metrics:  metrics,
+		Interval: time.Hour,
+		OnAnomaly: func(ctx context.Context, a getui.DeliveryAnomaly) {
+			mu.Lock()
+			defer mu.Unlock()
+			anomalies = append(anomalies, a)
+		},
+	})
+	assert.Nil(t, err)
+	// 创建后立即对账一次
+	assert.Nil(t, r.Close(ctx))
+
+	mu.Lock()
+	assert.Len(t, anomalies, 1)
+	assert.Equal(t, "silent", anomalies[0].TaskID)
+	assert.Equal(t, int64(3), anomalies[0].Stats.Delivered)
+	mu.Unlock()
+	assert.Equal(t, 0.9, metrics.gauge(getui.MetricReceiptDeliveryRate+",taskid=healthy"))
+	assert.Equal(t, 0.15, metrics.gauge(getui.MetricReceiptDeliveryRate+",taskid=silent"))
+	assert.Equal(t, float64(0), metrics.gauge(getui.MetricReceiptDeliveryRate+",taskid=recent"))
+	assert.Equal(t, float64(1), metrics.counter(getui.MetricDeliveryAnomalies))
+
+	// 已通知过的任务不再重复通知
+	again, err := r.Reconcile(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, again, 0)
+	assert.Equal(t, float64(2), metrics.counter(getui.MetricReconcileRuns+",result=ok"))
+
+	_, err = getui.NewReconciler(getui.ReconcilerOptions{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,148 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_CronNext cron表达式的下一次触发时间
+func Test_CronNext(t *testing.T) {
+	loc := getui.GetuiLocation
+	from := time.Date(2026, 10, 16, 9, 30, 0, 0, loc) // 周五
+
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"0 9 * * *", time.Date(2026, 10, 17, 9, 0, 0, 0, loc)},
+		{"*/15 * * * *", time.Date(2026, 10, 16, 9, 45, 0, 0, loc)},
+		{"0 9 * * 1-5", time.Date(2026, 10, 19, 9, 0, 0, 0, loc)},
+		{"0 8 1 * *", time.Date(2026, 11, 1, 8, 0, 0, 0, loc)},
+		{"0 0 * * 7", time.Date(2026, 10, 18, 0, 0, 0, 0, loc)},
+		{"30 * * * * *", time.Date(2026, 10, 16, 9, 30, 30, 0, loc)},
+		{"@daily", time.Date(2026, 10, 17, 0, 0, 0, 0, loc)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, loc)},
+	}
+	for _, c := range cases {
+		s, err := getui.ParseCron(c.spec)
+		assert.Nil(t, err, c.spec)
+		assert.Equal(t, c.want, s.Next(from), c.spec)
+	}
+
+	for _, spec := range []string{"", "* * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		_, err := getui.ParseCron(spec)
+		assert.NotNil(t, err, spec)
+	}
+}
+
+// memRecurringStore 内存中的周期任务状态
+type memRecurringStore struct {
+	mu     sync.Mutex
+	states map[string]getui.RecurringState
+}
+
+func (m *memRecurringStore) Load(ctx context.Context, id string) (getui.RecurringState, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.states[id]
+	return s, ok, nil
+}
+
+func (m *memRecurringStore) Save(ctx context.Context, state getui.RecurringState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[state.ID] = state
+	return nil
+}
+
+func (m *memRecurringStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.states, id)
+	return nil
+}
+
+// Test_Recurring 按秒触发的周期推送，验证发送、重叠保护、暂停与持久化
+func Test_Recurring(t *testing.T) {
+	var pushes int32
+	release := make(chan struct{})
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			atomic.AddInt32(&pushes, 1)
+			<-release
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","taskid":"testTaskID"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	store := &memRecurringStore{states: map[string]getui.RecurringState{}}
+	results := make(chan error, 16)
+	recurring := getui.NewRecurring(client, getui.RecurringOptions{
+		Store: store,
+		OnResult: func(id string, at time.Time, ret *getui.RspBody, err error) {
+			assert.Equal(t, "digest", id)
+			results <- err
+		},
+	})
+
+	ctx := context.Background()
+	err = recurring.Add(ctx, getui.RecurringJob{
+		ID:   "digest",
+		Spec: "* * * * * *",
+		Build: func(at time.Time) (getui.Job, error) {
+			return getui.Job{Single: &getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}}, nil
+		},
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, recurring.Add(ctx, getui.RecurringJob{ID: "digest", Spec: "* * * * * *"}))
+
+	// 第一次发送阻塞期间的触发被跳过
+	assert.ErrorIs(t, <-results, getui.ErrRecurringOverlap)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&pushes))
+	close(release)
+	for err := range results {
+		if err == nil {
+			break
+		}
+	}
+
+	assert.Nil(t, recurring.Pause(ctx, "digest"))
+	state, err := recurring.State("digest")
+	assert.Nil(t, err)
+	assert.True(t, state.Paused)
+	assert.False(t, state.LastRun.IsZero())
+	assert.True(t, store.states["digest"].Paused)
+
+	assert.Nil(t, recurring.Close(ctx))
+	assert.ErrorIs(t, recurring.Pause(ctx, "digest"), getui.ErrRecurringNotFound)
+
+	// 重启后恢复暂停状态
+	recurring = getui.NewRecurring(client, getui.RecurringOptions{Store: store})
+	assert.Nil(t, recurring.Add(ctx, getui.RecurringJob{
+		ID:    "digest",
+		Spec:  "* * * * * *",
+		Build: func(at time.Time) (getui.Job, error) { return getui.Job{}, nil },
+	}))
+	state, err = recurring.State("digest")
+	assert.Nil(t, err)
+	assert.True(t, state.Paused)
+	assert.True(t, state.NextRun.IsZero())
+	assert.Nil(t, recurring.Remove(ctx, "digest"))
+	assert.Len(t, store.states, 0)
+	assert.Nil(t, recurring.Close(ctx))
+}
@@ -0,0 +1,60 @@
+package getui
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_RedactSecrets 日志与错误信息中不出现密钥与token
+func Test_RedactSecrets(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			// 个推在desc中回显了token与密钥
+			return jsonResponse(req, http.StatusOK, `{"result":"sign_error","desc":"token testAuthToken secret testMasterSecret"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	params := getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		AppSecret:    "testAppSecret",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	}
+	for _, s := range []string{fmt.Sprintf("%v", params), fmt.Sprintf("%+v", params), fmt.Sprintf("%#v", params)} {
+		assert.NotContains(t, s, "testMasterSecret")
+		assert.NotContains(t, s, "testAppSecret")
+		assert.Contains(t, s, "testAppKey")
+	}
+
+	logger := &memLogger{}
+	params.Logger = logger
+	client, err := getui.New(params)
+	assert.Nil(t, err)
+
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "[REDACTED]")
+	assert.NotContains(t, err.Error(), "testAuthToken")
+	assert.NotContains(t, err.Error(), "testMasterSecret")
+
+	assert.Len(t, logger.lines, 1)
+	line := fmt.Sprint(logger.lines[0])
+	assert.NotContains(t, line, "testAuthToken")
+	assert.NotContains(t, line, "testMasterSecret")
+
+	// auth_sign 失败时返回的结构中不输出token
+	transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(req, http.StatusOK, `{"result":"sign_error","auth_token":"leakedAuthToken"}`), nil
+	})
+	params.HTTPClient = &http.Client{Transport: transport}
+	_, err = getui.New(params)
+	assert.NotNil(t, err)
+	assert.NotContains(t, err.Error(), "leakedAuthToken")
+}
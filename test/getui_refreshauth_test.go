@@ -0,0 +1,187 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_RefreshAuth 立即申请新token
+func Test_RefreshAuth(t *testing.T) {
+	var signs int32
+	fail := int32(0)
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "auth_sign") {
+			if atomic.LoadInt32(&fail) == 1 {
+				return jsonResponse(req, http.StatusOK, `{"result":"sign_error"}`), nil
+			}
+			n := atomic.AddInt32(&signs, 1)
+			return jsonResponse(req, http.StatusOK, fmt.Sprintf(`{"result":"ok","auth_token":"token%d"}`, n)), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "token1", client.AuthToken())
+
+	err = client.RefreshAuth(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "token2", client.AuthToken())
+
+	atomic.StoreInt32(&fail, 1)
+	err = client.RefreshAuth(context.Background())
+	assert.NotNil(t, err)
+}
+
+// Test_PauseAutoRefresh 暂停期间仍可手动刷新，恢复后不阻塞
+func Test_PauseAutoRefresh(t *testing.T) {
+	var signs int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "auth_sign") {
+			n := atomic.AddInt32(&signs, 1)
+			return jsonResponse(req, http.StatusOK, fmt.Sprintf(`{"result":"ok","auth_token":"token%d"}`, n)), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	client.PauseAutoRefresh()
+	assert.Nil(t, client.RefreshAuth(context.Background()))
+	assert.Equal(t, "token2", client.AuthToken())
+
+	client.ResumeAutoRefresh()
+	client.ResumeAutoRefresh()
+	assert.Nil(t, client.Shutdown(context.Background()))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&signs))
+}
+
+// Test_TokenInfo token的取得时间、过期时间与最近一次刷新的错误
+func Test_TokenInfo(t *testing.T) {
+	fail := int32(0)
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "auth_sign") && atomic.LoadInt32(&fail) == 1 {
+			return jsonResponse(req, http.StatusOK, `{"result":"sign_error"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","expire_time":"4102444800000"}`), nil
+	})
+	before := time.Now()
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	info := client.TokenInfo()
+	assert.Equal(t, "testAuthToken", info.Token)
+	assert.False(t, info.IssuedAt.Before(before))
+	assert.Equal(t, int64(4102444800), info.ExpireTime.Unix())
+	assert.Nil(t, info.LastRefreshErr)
+
+	atomic.StoreInt32(&fail, 1)
+	assert.NotNil(t, client.RefreshAuth(context.Background()))
+	assert.NotNil(t, client.TokenInfo().LastRefreshErr)
+
+	atomic.StoreInt32(&fail, 0)
+	assert.Nil(t, client.RefreshAuth(context.Background()))
+	assert.Nil(t, client.TokenInfo().LastRefreshErr)
+}
+
+// nextRefresh 按个推返回的过期时间（expireIn 为0时不返回）创建客户端，返回后台计划的下次刷新时间及创建前后的时间
+func nextRefresh(t *testing.T, params getui.InitParams, expireIn time.Duration) (next, before, after time.Time) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if expireIn == 0 {
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+		}
+		expireTime := time.Now().Add(expireIn).UnixNano() / int64(time.Millisecond)
+		return jsonResponse(req, http.StatusOK, fmt.Sprintf(`{"result":"ok","auth_token":"testAuthToken","expire_time":"%d"}`, expireTime)), nil
+	})
+	params.AppID, params.AppKey, params.MasterSecret = "testAppID", "testAppKey", "testMasterSecret"
+	params.HTTPClient = &http.Client{Transport: transport}
+
+	before = time.Now()
+	client, err := getui.New(params)
+	assert.Nil(t, err)
+	defer client.Shutdown(context.Background())
+
+	assert.Eventually(t, func() bool { return !client.TokenInfo().NextRefresh.IsZero() }, time.Second, time.Millisecond)
+	return client.TokenInfo().NextRefresh, before, time.Now()
+}
+
+// Test_NextRefreshDelay 下次刷新时间：不晚于心跳周期与过期前 AuthExpireMargin，不早于1分钟
+func Test_NextRefreshDelay(t *testing.T) {
+	cases := []struct {
+		name      string
+		heartbeat time.Duration
+		margin    time.Duration
+		expireIn  time.Duration
+		want      time.Duration
+	}{
+		{name: "未返回过期时间按心跳周期", heartbeat: time.Hour, want: time.Hour},
+		{name: "过期较晚按心跳周期", heartbeat: time.Hour, margin: 5 * time.Minute, expireIn: 24 * time.Hour, want: time.Hour},
+		{name: "过期较早提前margin", heartbeat: 20 * time.Hour, margin: 5 * time.Minute, expireIn: 30 * time.Minute, want: 25 * time.Minute},
+		{name: "默认margin为10分钟", heartbeat: 20 * time.Hour, expireIn: 30 * time.Minute, want: 20 * time.Minute},
+		{name: "即将过期时不早于1分钟", heartbeat: 20 * time.Hour, margin: 5 * time.Minute, expireIn: 2 * time.Minute, want: time.Minute},
+		{name: "已过期时不早于1分钟", heartbeat: 20 * time.Hour, expireIn: -time.Hour, want: time.Minute},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			next, before, after := nextRefresh(t, getui.InitParams{
+				AuthHeartbeat:     tc.heartbeat,
+				AuthExpireMargin:  tc.margin,
+				AuthRefreshJitter: -1,
+			}, tc.expireIn)
+			// 过期时间精确到毫秒
+			assert.False(t, next.Before(before.Add(tc.want-time.Millisecond)))
+			assert.False(t, next.After(after.Add(tc.want)))
+		})
+	}
+}
+
+// Test_RefreshJitter 随机提前量在 [0, AuthRefreshJitter) 之间，提前后仍不早于1分钟
+func Test_RefreshJitter(t *testing.T) {
+	cases := []struct {
+		name      string
+		heartbeat time.Duration
+		jitter    time.Duration
+		expireIn  time.Duration
+		min, max  time.Duration
+	}{
+		{name: "按心跳周期提前", heartbeat: time.Hour, jitter: 10 * time.Minute, min: 50 * time.Minute, max: time.Hour},
+		{name: "默认至多提前5分钟", heartbeat: time.Hour, min: 55 * time.Minute, max: time.Hour},
+		{name: "按过期时间提前", heartbeat: 20 * time.Hour, jitter: 10 * time.Minute, expireIn: 40 * time.Minute, min: 20 * time.Minute, max: 30 * time.Minute},
+		{name: "抖动大于周期时不早于1分钟", heartbeat: 2 * time.Minute, jitter: time.Hour, min: time.Minute, max: 2 * time.Minute},
+		{name: "小于0时不抖动", heartbeat: time.Hour, jitter: -1, min: time.Hour, max: time.Hour},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 5; i++ {
+				next, before, after := nextRefresh(t, getui.InitParams{
+					AuthHeartbeat:     tc.heartbeat,
+					AuthRefreshJitter: tc.jitter,
+				}, tc.expireIn)
+				assert.False(t, next.Before(before.Add(tc.min-time.Millisecond)))
+				assert.False(t, next.After(after.Add(tc.max)))
+			}
+		})
+	}
+}
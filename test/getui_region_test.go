@@ -0,0 +1,52 @@
+package getui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_RegionLookup 按名称、编码查找地区
+func Test_RegionLookup(t *testing.T) {
+	r, ok := getui.RegionByName("广东省", "深圳市")
+	assert.True(t, ok)
+	assert.Equal(t, "44030000", r.Code)
+
+	// 可省略后缀
+	r, ok = getui.RegionByName("广东", "深圳")
+	assert.True(t, ok)
+	assert.Equal(t, "44030000", r.Code)
+
+	r, ok = getui.RegionByName("广西", "")
+	assert.True(t, ok)
+	assert.Equal(t, "45000000", r.Code)
+
+	// 直辖市
+	r, ok = getui.RegionByName("北京", "北京市")
+	assert.True(t, ok)
+	assert.Equal(t, "11000000", r.Code)
+
+	_, ok = getui.RegionByName("广东", "不存在")
+	assert.False(t, ok)
+
+	r, ok = getui.RegionByCode("33010000")
+	assert.True(t, ok)
+	assert.Equal(t, "杭州市", r.City)
+
+	rs := getui.RegionsInProvince("江苏")
+	assert.True(t, len(rs) > 1)
+	assert.Equal(t, "32000000", rs[0].Code)
+}
+
+// Test_RegionCondition 按名称生成地区条件
+func Test_RegionCondition(t *testing.T) {
+	cond, err := getui.RegionCondition([2]string{"广东", "深圳"}, [2]string{"上海", ""})
+	assert.Nil(t, err)
+	assert.Equal(t, getui.ConditionRegion, cond.Key)
+	assert.Equal(t, []string{"44030000", "31000000"}, cond.Values)
+
+	_, err = getui.RegionCondition([2]string{"火星", ""})
+	assert.True(t, errors.Is(err, getui.ErrInvalidTarget))
+}
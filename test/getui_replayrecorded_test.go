@@ -0,0 +1,64 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_ReplayInteractions 录制的请求回放到其他应用：鉴权由客户端处理，appkey、taskid 替换为回放时的
+func Test_ReplayInteractions(t *testing.T) {
+	interactions, err := getui.ReadInteractions("testdata/push_list.json")
+	assert.Nil(t, err)
+
+	var mu sync.Mutex
+	var paths []string
+	var pushList map[string]interface{}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		paths = append(paths, req.URL.Path)
+		switch {
+		case strings.HasSuffix(req.URL.Path, "save_list_body"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"newTaskID"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_list"):
+			data, _ := io.ReadAll(req.Body)
+			json.Unmarshal(data, &pushList)
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","status":"successed_online","taskid":"newTaskID"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "sandboxAppID",
+		AppKey:       "sandboxAppKey",
+		MasterSecret: "sandboxMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	results, err := client.ReplayInteractions(context.Background(), interactions)
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "save_list_body", results[0].Path)
+	assert.Equal(t, "push_list", results[1].Path)
+	assert.Equal(t, "successed_online", results[1].Ret.Status)
+
+	assert.Equal(t, []string{"/v1/sandboxAppID/auth_sign", "/v1/sandboxAppID/save_list_body", "/v1/sandboxAppID/push_list"}, paths)
+	assert.Equal(t, "newTaskID", pushList["taskid"])
+	assert.Equal(t, "sandboxAppKey", pushList["message"].(map[string]interface{})["appkey"])
+	assert.Len(t, pushList["cid"], 2)
+
+	// ctx 已取消时不发送
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.ReplayInteractions(ctx, interactions)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, paths, 3)
+}
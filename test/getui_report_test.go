@@ -0,0 +1,55 @@
+package getui
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_ReportCSV 查询报表并导出CSV
+func Test_ReportCSV(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "query_app_push/20261016"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","data":{"date":"20261016","sendCount":100,"sendOnlineCount":60,"receiveCount":80,"showCount":70,"clickCount":7}}`), nil
+		case strings.HasSuffix(req.URL.Path, "query_app_user/20261016"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","data":{"date":"20261016","newRegistCount":3,"registTotalCount":1000,"activeCount":400,"onlineCount":120}}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	// 按北京时间取日期，UTC 15点即北京时间23点
+	date := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	push, err := client.QueryAppPush(date)
+	assert.Nil(t, err)
+	user, err := client.QueryAppUser(date)
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, getui.AppPushReportList{*push}.WriteCSV(buf))
+	assert.Equal(t, "date,send_count,send_online_count,receive_count,show_count,click_count\n20261016,100,60,80,70,7\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, getui.AppUserReportList{*user}.WriteCSV(buf))
+	assert.Equal(t, "date,new_regist_count,regist_total_count,active_count,online_count\n20261016,3,1000,400,120\n", buf.String())
+
+	buf.Reset()
+	results := getui.PushResultList{{TaskID: "testTaskID", MsgTotal: 10, MsgProcess: 10, GT: getui.PushResultStat{Sent: 8}}}
+	assert.Nil(t, results.WriteCSV(buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "testTaskID,10,10,0,0,8,0,0,0,0,0,0,0", lines[1])
+}
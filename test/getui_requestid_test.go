@@ -0,0 +1,79 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// memLogger 内存中的日志
+type memLogger struct {
+	mu    sync.Mutex
+	lines []map[string]interface{}
+}
+
+func (l *memLogger) Log(ctx context.Context, level getui.LogLevel, msg string, fields ...getui.LogField) {
+	line := map[string]interface{}{"level": level.String(), "msg": msg}
+	for _, f := range fields {
+		line[f.Key] = f.Value
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, line)
+}
+
+// Test_RequestIDFromContext 从ctx取requestid，日志与错误中带上requestid
+func Test_RequestIDFromContext(t *testing.T) {
+	var sent []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			var body getui.SingleReqBody
+			assert.Nil(t, json.NewDecoder(req.Body).Decode(&body))
+			sent = append(sent, body.RequestID)
+			if body.CID[0] == 'f' {
+				return jsonResponse(req, http.StatusOK, `{"result":"flow_exceeded"}`), nil
+			}
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	logger := &memLogger{}
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Logger:       logger,
+	})
+	assert.Nil(t, err)
+
+	ctx := getui.WithRequestID(context.Background(), "trace-123")
+	assert.Equal(t, "trace-123", getui.RequestIDFromContext(ctx))
+
+	ret, err := client.PushToSingleContext(ctx, getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"})
+	assert.Nil(t, err)
+	assert.Equal(t, "trace-123", ret.RequestID)
+
+	// body 中指定的优先
+	_, err = client.PushToSingleContext(ctx, getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef", RequestID: "own-id"})
+	assert.Nil(t, err)
+
+	_, err = client.PushToSingleContext(ctx, getui.SingleReqBody{CID: "ffffffffffffffffffffffffffffffff"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "trace-123")
+
+	assert.Equal(t, []string{"trace-123", "own-id", "trace-123"}, sent)
+	assert.Len(t, logger.lines, 3)
+	assert.Equal(t, "info", logger.lines[0]["level"])
+	assert.Equal(t, "trace-123", logger.lines[0]["requestid"])
+	assert.Equal(t, "testTaskID", logger.lines[0]["taskid"])
+	assert.Equal(t, "error", logger.lines[2]["level"])
+	assert.Equal(t, "trace-123", logger.lines[2]["requestid"])
+}
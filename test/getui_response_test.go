@@ -0,0 +1,92 @@
+package getui
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTripFunc 用函数模拟个推接口
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// jsonResponse 构造JSON响应
+func jsonResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+		Request:    req,
+	}
+}
+
+// Test_ResponseSizeCap 响应超过上限时报错
+func Test_ResponseSizeCap(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"result":"ok","auth_token":"` + strings.Repeat("a", 2048) + `"}`
+		return jsonResponse(req, http.StatusOK, body), nil
+	})
+
+	_, err := getui.New(getui.InitParams{
+		AppID:            "testAppID",
+		AppKey:           "testAppKey",
+		MasterSecret:     "testMasterSecret",
+		HTTPClient:       &http.Client{Transport: transport},
+		MaxResponseBytes: 1024,
+	})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "上限")
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, client.AuthToken(), 2048)
+}
+
+// Test_NonJSONResponse 网关返回HTML或空body时返回 TransportError
+func Test_NonJSONResponse(t *testing.T) {
+	var body string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "auth_sign") {
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+		}
+		rsp := jsonResponse(req, http.StatusBadGateway, body)
+		rsp.Header.Set("Content-Type", "text/html")
+		return rsp, nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	body = "<html><body>502 Bad Gateway" + strings.Repeat(" ", 1024) + "</body></html>"
+	_, err = client.UserStatus("8b4ed2bfa6b22aa3a1c8a8b6e1b6c8b1")
+	var te *getui.TransportError
+	assert.True(t, errors.As(err, &te))
+	assert.Equal(t, http.StatusBadGateway, te.StatusCode)
+	assert.Equal(t, "text/html", te.ContentType)
+	assert.True(t, strings.HasPrefix(te.Excerpt, "<html><body>502 Bad Gateway"))
+	assert.True(t, len(te.Excerpt) < 300)
+	assert.NotContains(t, err.Error(), "JSON无法解析")
+
+	body = ""
+	_, err = client.UserStatus("8b4ed2bfa6b22aa3a1c8a8b6e1b6c8b1")
+	assert.True(t, errors.As(err, &te))
+	assert.Equal(t, "", te.Excerpt)
+}
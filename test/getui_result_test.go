@@ -0,0 +1,75 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// resultClient 模拟push_result接口，第n次查询返回 progress[n] 的已处理数，为负数时返回503
+func resultClient(t *testing.T, progress []int) getui.Client {
+	polls := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if !strings.HasSuffix(req.URL.Path, "push_result") {
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+		}
+
+		var body struct {
+			TaskIDList []string `json:"taskIdList"`
+		}
+		assert.Nil(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, []string{"testTaskID"}, body.TaskIDList)
+
+		p := progress[len(progress)-1]
+		if polls < len(progress) {
+			p = progress[polls]
+		}
+		polls++
+		if p < 0 {
+			return jsonResponse(req, http.StatusServiceUnavailable, "<html>503 Service Unavailable</html>"), nil
+		}
+		rsp := fmt.Sprintf(`{"result":"ok","data":[{"taskId":"testTaskID","msgTotal":10,"msgProcess":%d,"GT":{"sent":%d,"feedback":%d,"result":"ok"}}]}`, p, p, p/2)
+		return jsonResponse(req, http.StatusOK, rsp), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+	return client
+}
+
+// Test_WaitForResult 轮询直到统计稳定
+func Test_WaitForResult(t *testing.T) {
+	client := resultClient(t, []int{2, 6, 10, 10})
+
+	ret, err := client.WaitForResult(context.Background(), "testTaskID", 10*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), ret.MsgProcess)
+	assert.Equal(t, int64(10), ret.GT.Sent)
+	assert.Equal(t, int64(5), ret.GT.Feedback)
+
+	// 统计一直未完成时，ctx结束返回最后的结果
+	client = resultClient(t, []int{1, 2, 3})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	ret, err = client.WaitForResult(ctx, "testTaskID", 10*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int64(3), ret.MsgProcess)
+
+	// 临时故障不中断轮询
+	client = resultClient(t, []int{5, -1, 10, -1, 10})
+	ret, err = client.WaitForResult(context.Background(), "testTaskID", 10*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), ret.MsgProcess)
+}
@@ -0,0 +1,155 @@
+package getui
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_RetryBudget 5xx时重试，客户端的重试预算用完后快速失败
+func Test_RetryBudget(t *testing.T) {
+	var pushes, failures int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			n := atomic.AddInt32(&pushes, 1)
+			if n > atomic.LoadInt32(&failures) {
+				return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+			}
+			return jsonResponse(req, http.StatusServiceUnavailable, `<html>503</html>`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+		RetryBudget:  3,
+		RetryPolicy:  getui.RetryAllPolicy,
+	})
+	assert.Nil(t, err)
+
+	body := getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}
+
+	// 失败一次后重试成功
+	atomic.StoreInt32(&failures, 1)
+	ret, err := client.PushToSingle(body)
+	assert.Nil(t, err)
+	assert.Equal(t, "testTaskID", ret.TaskID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&pushes))
+
+	// 持续失败，用完剩余的2次预算
+	atomic.StoreInt32(&pushes, 0)
+	atomic.StoreInt32(&failures, 100)
+	_, err = client.PushToSingle(body)
+	assert.ErrorIs(t, err, getui.ErrRetryBudgetExhausted)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&pushes))
+
+	// 预算用完后不再重试
+	_, err = client.PushToSingle(body)
+	assert.ErrorIs(t, err, getui.ErrRetryBudgetExhausted)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&pushes))
+}
+
+// Test_RetryPolicy 自定义策略按方法、接口、result 决定是否重试
+func Test_RetryPolicy(t *testing.T) {
+	var pushes, stops int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "push_single"):
+			if atomic.AddInt32(&pushes, 1) == 1 {
+				return jsonResponse(req, http.StatusOK, `{"result":"flow_exceeded"}`), nil
+			}
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+		case strings.Contains(req.URL.Path, "stop_task"):
+			atomic.AddInt32(&stops, 1)
+			return jsonResponse(req, http.StatusServiceUnavailable, `<html>503</html>`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	var attempts []getui.RetryAttempt
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+		RetryPolicy: getui.RetryPolicyFunc(func(a getui.RetryAttempt) bool {
+			attempts = append(attempts, a)
+			return a.Result == "flow_exceeded"
+		}),
+	})
+	assert.Nil(t, err)
+
+	// 按 result 重试
+	ret, err := client.PushToSingle(getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"})
+	assert.Nil(t, err)
+	assert.Equal(t, "testTaskID", ret.TaskID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&pushes))
+	assert.Equal(t, "POST", attempts[0].Method)
+	assert.Equal(t, "push_single", attempts[0].Endpoint)
+	assert.Equal(t, 1, attempts[0].Attempt)
+
+	// 策略不重试5xx
+	_, err = client.StopTask("task1")
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&stops))
+	last := attempts[len(attempts)-1]
+	assert.Equal(t, "stop_task", last.Endpoint)
+	assert.Equal(t, "stop_task/task1", last.Path)
+	assert.Equal(t, http.StatusServiceUnavailable, last.StatusCode)
+}
+
+// Test_DefaultRetryPolicy 推送只在请求未发出或429时重试
+func Test_DefaultRetryPolicy(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	p := getui.DefaultRetryPolicy
+	assert.True(t, p.ShouldRetry(getui.RetryAttempt{Method: "GET", StatusCode: 503}))
+	assert.True(t, p.ShouldRetry(getui.RetryAttempt{Method: "DELETE", StatusCode: 429}))
+	assert.False(t, p.ShouldRetry(getui.RetryAttempt{Method: "POST", Endpoint: "push_single", StatusCode: 503}))
+	assert.False(t, p.ShouldRetry(getui.RetryAttempt{Method: "POST", Endpoint: "push_single", Err: errors.New("connection reset")}))
+	assert.True(t, p.ShouldRetry(getui.RetryAttempt{Method: "POST", Endpoint: "push_single", Err: &url.Error{Op: "Post", Err: dialErr}}))
+	assert.True(t, p.ShouldRetry(getui.RetryAttempt{Method: "POST", Endpoint: "push_single", StatusCode: 429}))
+	assert.True(t, p.ShouldRetry(getui.RetryAttempt{Method: "POST", Endpoint: "auth_sign", StatusCode: 503}))
+	assert.False(t, p.ShouldRetry(getui.RetryAttempt{Method: "GET", StatusCode: 200}))
+	assert.True(t, getui.RetryAllPolicy.ShouldRetry(getui.RetryAttempt{Method: "POST", Endpoint: "push_single", StatusCode: 503}))
+}
+
+// Test_DefaultRetryPolicyPush 默认策略下推送遇到5xx不重试，避免重复推送
+func Test_DefaultRetryPolicyPush(t *testing.T) {
+	var pushes int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			atomic.AddInt32(&pushes, 1)
+			return jsonResponse(req, http.StatusBadGateway, `<html>502</html>`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+	})
+	assert.Nil(t, err)
+
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"})
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&pushes))
+}
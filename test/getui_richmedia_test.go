@@ -0,0 +1,30 @@
+package getui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_SetRichMedia 富媒体附件的 push_info 结构
+func Test_SetRichMedia(t *testing.T) {
+	info := getui.PushInfo{}
+	info.Aps.Alert.Title = "新品上架"
+	err := info.SetRichMedia("https://cdn.example.com/a.jpg", getui.MediaImage, true)
+	assert.Nil(t, err)
+
+	data, err := json.Marshal(info)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"aps":{"alert":{"title":"新品上架"},"mutable-content":1},`+
+		`"multimedia":[{"url":"https://cdn.example.com/a.jpg","type":1,"only_wifi":true}],`+
+		`"media":{"url":"https://cdn.example.com/a.jpg","type":"image"}}`, string(data))
+
+	assert.NotNil(t, info.SetRichMedia("http://cdn.example.com/a.jpg", getui.MediaImage, false))
+	assert.NotNil(t, info.SetRichMedia("https://cdn.example.com/a.jpg", getui.MediaType(9), false))
+
+	// 未设置时不影响原有结构
+	data, _ = json.Marshal(getui.PushInfo{})
+	assert.Equal(t, `{"aps":{"alert":{}}}`, string(data))
+}
@@ -0,0 +1,40 @@
+package getui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_FormatPushTime 其它时区的时间转换为北京时间
+func Test_FormatPushTime(t *testing.T) {
+	newYork := time.FixedZone("EST", -5*60*60)
+	at := time.Date(2026, 10, 16, 21, 30, 45, 0, newYork)
+	assert.Equal(t, "202610171030", getui.FormatPushTime(at))
+
+	body := getui.AppReqBody{}
+	assert.NotNil(t, body.ScheduleAt(time.Now().Add(-time.Minute)))
+	assert.Nil(t, body.ScheduleAt(time.Now().Add(time.Hour)))
+	assert.Len(t, body.PushTime, 12)
+}
+
+// Test_NextLocalTime 用户所在时区的下一个早上9点
+func Test_NextLocalTime(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	// 东京时间 08:00，当天9点
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, tokyo)
+	assert.Equal(t, time.Date(2026, 10, 16, 9, 0, 0, 0, tokyo), getui.NextLocalTime(now, tokyo, 9, 0))
+
+	// 东京时间 09:00 整，次日9点
+	now = time.Date(2026, 10, 16, 9, 0, 0, 0, tokyo)
+	assert.Equal(t, time.Date(2026, 10, 17, 9, 0, 0, 0, tokyo), getui.NextLocalTime(now, tokyo, 9, 0))
+
+	// 以UTC表示的当前时间，结果为东京时区
+	now = time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC)
+	next := getui.NextLocalTime(now, tokyo, 9, 0)
+	assert.Equal(t, "202610170900", getui.FormatPushTime(next.Add(time.Hour)))
+	assert.Equal(t, time.Date(2026, 10, 17, 9, 0, 0, 0, tokyo), next)
+}
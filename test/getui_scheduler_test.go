@@ -0,0 +1,165 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_Scheduler 各应用的活动轮流发送，小活动不必等大活动发完
+func Test_Scheduler(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "save_list_body"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_list"):
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			sent = append(sent, strings.Split(req.URL.Path, "/")[2])
+			mu.Unlock()
+			return jsonResponse(req, http.StatusOK, `{"result":"ok"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	params := func(appID string) getui.InitParams {
+		return getui.InitParams{
+			AppID:        appID,
+			AppKey:       "testAppKey",
+			MasterSecret: "testMasterSecret",
+			HTTPClient:   &http.Client{Transport: transport},
+		}
+	}
+
+	manager := getui.NewManager()
+	assert.Nil(t, manager.Add("marketing", getui.AppConfig{InitParams: params("marketingAppID")}))
+	assert.Nil(t, manager.Add("transactional", getui.AppConfig{InitParams: params("transactionalAppID")}))
+
+	cids := func(n int) []string {
+		ret := make([]string, n)
+		for i := range ret {
+			ret[i] = fmt.Sprintf("%032x", i)
+		}
+		return ret
+	}
+
+	s := manager.NewScheduler(getui.SchedulerOptions{GlobalQPS: 100, GlobalBurst: 1})
+	defer s.Close()
+
+	start := time.Now()
+	blast, err := s.Submit(context.Background(), "marketing", getui.ListReqBody{CID: cids(8)}, 1)
+	assert.Nil(t, err)
+	small, err := s.Submit(context.Background(), "transactional", getui.ListReqBody{CID: cids(2)}, 1)
+	assert.Nil(t, err)
+	_, err = s.Submit(context.Background(), "unknown", getui.ListReqBody{CID: cids(2)}, 1)
+	assert.NotNil(t, err)
+
+	ret, err := small.Wait(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, ret.Sent, 2)
+	ret, err = blast.Wait(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, ret.Sent, 8)
+
+	// 全局限速每秒100个分片
+	assert.True(t, time.Since(start) >= 80*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, sent, 10)
+	last := 0
+	for i, app := range sent {
+		if app == "transactionalAppID" {
+			last = i
+		}
+	}
+	assert.Less(t, last, 5)
+}
+
+// Test_SchedulerClose 关闭后未发送的分片记为未发送
+func Test_SchedulerClose(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","taskid":"testTaskID"}`), nil
+	})
+	manager := getui.NewManager()
+	assert.Nil(t, manager.Add("marketing", getui.AppConfig{InitParams: getui.InitParams{
+		AppID:        "marketingAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	}}))
+
+	s := manager.NewScheduler(getui.SchedulerOptions{GlobalQPS: 10})
+	cids := make([]string, 20)
+	for i := range cids {
+		cids[i] = fmt.Sprintf("%032x", i)
+	}
+	sc, err := s.Submit(context.Background(), "marketing", getui.ListReqBody{CID: cids}, 1)
+	assert.Nil(t, err)
+	time.Sleep(50 * time.Millisecond)
+	s.Close()
+
+	ret, err := sc.Wait(context.Background())
+	assert.ErrorIs(t, err, getui.ErrClientClosed)
+	assert.True(t, len(ret.NotAttempted) > 0)
+	assert.Equal(t, 20, len(ret.Sent)+len(ret.NotAttempted))
+
+	_, err = s.Submit(context.Background(), "marketing", getui.ListReqBody{CID: cids}, 1)
+	assert.NotNil(t, err)
+}
+
+// Test_SchedulerCloseInFlight 不限速时关闭也不再发送，正在发送的分片被取消
+func Test_SchedulerCloseInFlight(t *testing.T) {
+	started := make(chan struct{}, 1)
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_list") {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","taskid":"testTaskID"}`), nil
+	})
+	manager := getui.NewManager()
+	assert.Nil(t, manager.Add("marketing", getui.AppConfig{InitParams: getui.InitParams{
+		AppID:        "marketingAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	}}))
+
+	s := manager.NewScheduler(getui.SchedulerOptions{})
+	cids := make([]string, 20)
+	for i := range cids {
+		cids[i] = fmt.Sprintf("%032x", i)
+	}
+	sc, err := s.Submit(context.Background(), "marketing", getui.ListReqBody{CID: cids}, 1)
+	assert.Nil(t, err)
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		s.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close 未取消正在发送的分片")
+	}
+
+	ret, err := sc.Wait(context.Background())
+	assert.NotNil(t, err)
+	assert.Len(t, ret.Sent, 0)
+	assert.True(t, len(ret.NotAttempted) > 0)
+}
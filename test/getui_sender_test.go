@@ -0,0 +1,65 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_SenderPriority 事务类推送先于排队中的营销推送发送
+func Test_SenderPriority(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			once.Do(func() {
+				close(started)
+				<-release
+			})
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	sender := getui.NewSender(client, getui.SenderOptions{Workers: 1})
+
+	var mu sync.Mutex
+	var order []string
+	job := func(name string, priority getui.Priority) getui.Job {
+		return getui.Job{
+			Priority: priority,
+			Single:   &getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"},
+			Callback: func(rsp *getui.RspBody, err error) {
+				assert.Nil(t, err)
+				mu.Lock()
+				order = append(order, name)
+				mu.Unlock()
+			},
+		}
+	}
+
+	ctx := context.Background()
+	assert.Nil(t, sender.Submit(ctx, job("campaign-1", getui.PriorityMarketing)))
+	<-started
+	assert.Nil(t, sender.Submit(ctx, job("campaign-2", getui.PriorityMarketing)))
+	assert.Nil(t, sender.Submit(ctx, job("otp", getui.PriorityTransactional)))
+	close(release)
+
+	assert.Nil(t, sender.Close(ctx))
+	assert.Equal(t, []string{"campaign-1", "otp", "campaign-2"}, order)
+	assert.ErrorIs(t, sender.Submit(ctx, job("late", getui.PriorityTransactional)), getui.ErrSenderClosed)
+}
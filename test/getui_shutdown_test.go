@@ -0,0 +1,98 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_Shutdown 等待进行中的推送完成后关闭鉴权
+func Test_Shutdown(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		paths = append(paths, req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:])
+		mu.Unlock()
+
+		switch {
+		case strings.HasSuffix(req.URL.Path, "auth_sign"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_single"):
+			close(started)
+			<-release
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID","status":"successed_online"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	reqBody := getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}
+	pushed := make(chan error)
+	go func() {
+		_, err := client.PushToSingle(reqBody)
+		pushed <- err
+	}()
+	<-started
+
+	shutdown := make(chan error)
+	go func() {
+		shutdown <- client.Shutdown(context.Background())
+	}()
+
+	// 关闭过程中不再接受新的推送
+	time.Sleep(10 * time.Millisecond)
+	_, err = client.PushToSingle(reqBody)
+	assert.ErrorIs(t, err, getui.ErrClientClosed)
+
+	close(release)
+	assert.Nil(t, <-pushed)
+	assert.Nil(t, <-shutdown)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"auth_sign", "push_single", "auth_close"}, paths)
+}
+
+// Test_ShutdownTimeout ctx结束时不再等待
+func Test_ShutdownTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			<-release
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	go client.PushToSingle(getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"})
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.NotNil(t, client.Shutdown(ctx))
+}
@@ -0,0 +1,73 @@
+package getui
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_Signer 自定义签名，不提供 MasterSecret
+func Test_Signer(t *testing.T) {
+	var gotSign string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := struct {
+			Sign string `json:"sign"`
+		}{}
+		json.NewDecoder(req.Body).Decode(&body)
+		gotSign = body.Sign
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	signer := getui.SignerFunc(func(ctx context.Context, appKey, timestamp string) (string, error) {
+		// 实际场景中由 KMS 计算
+		return getui.NewSHA256Signer("kmsMasterSecret").Sign(ctx, appKey, timestamp)
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:      "testAppID",
+		AppKey:     "testAppKey",
+		Signer:     signer,
+		HTTPClient: &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "testAuthToken", client.AuthToken())
+	assert.Len(t, gotSign, 64)
+}
+
+// Test_MD5Signer 旧版应用使用md5签名
+func Test_MD5Signer(t *testing.T) {
+	var gotBody struct {
+		Timestamp string `json:"timestamp"`
+		Sign      string `json:"sign"`
+	}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		json.NewDecoder(req.Body).Decode(&gotBody)
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	_, err := getui.New(getui.InitParams{
+		AppID:         "testAppID",
+		AppKey:        "testAppKey",
+		MasterSecret:  "testMasterSecret",
+		SignAlgorithm: getui.SignMD5,
+		HTTPClient:    &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+	want := md5.Sum([]byte("testAppKey" + gotBody.Timestamp + "testMasterSecret"))
+	assert.Equal(t, hex.EncodeToString(want[:]), gotBody.Sign)
+
+	_, err = getui.New(getui.InitParams{
+		AppID:         "testAppID",
+		AppKey:        "testAppKey",
+		MasterSecret:  "testMasterSecret",
+		SignAlgorithm: "sha1",
+		HTTPClient:    &http.Client{Transport: transport},
+	})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,66 @@
+package getui
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_SilentPush 静默推送只带透传内容与 content-available
+func Test_SilentPush(t *testing.T) {
+	var bodies []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			data, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(data))
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID","status":"successed_online"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	body := getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"}
+	body.Notification.TransmissionContent = `{"sync":"orders"}`
+	ret, err := client.SilentPush(context.Background(), body)
+	assert.Nil(t, err)
+	assert.Equal(t, "testTaskID", ret.TaskID)
+	assert.Len(t, bodies, 1)
+	assert.Contains(t, bodies[0], `"msgtype":"transmission"`)
+	assert.Contains(t, bodies[0], `"transmission":{"transmission_type":false,"transmission_content":"{\"sync\":\"orders\"}"}`)
+	assert.Contains(t, bodies[0], `"push_info":{"aps":{"content-available":1}}`)
+	assert.NotContains(t, bodies[0], `alert`)
+	assert.NotContains(t, bodies[0], `notification`)
+
+	visible := body
+	visible.PushInfo.Aps.Alert.Body = "可见"
+	_, err = client.SilentPush(context.Background(), visible)
+	assert.ErrorIs(t, err, getui.ErrNotSilent)
+
+	visible = body
+	visible.Notification.Style.Title = "可见"
+	_, err = client.SilentPush(context.Background(), visible)
+	assert.ErrorIs(t, err, getui.ErrNotSilent)
+
+	// 短信补量与回执地址不会随静默推送发送
+	visible = body
+	visible.SMS = &getui.SMSInfo{TemplateID: "tpl-otp"}
+	_, err = client.SilentPush(context.Background(), visible)
+	assert.ErrorIs(t, err, getui.ErrNotSilent)
+
+	visible = body
+	visible.CallbackURL = "https://example.com/receipt"
+	_, err = client.SilentPush(context.Background(), visible)
+	assert.ErrorIs(t, err, getui.ErrNotSilent)
+	assert.Len(t, bodies, 1)
+}
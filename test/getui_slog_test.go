@@ -0,0 +1,32 @@
+package getui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_SlogLogger 日志级别与字段转为 slog
+func Test_SlogLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := getui.NewSlogLogger(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	l.Log(context.Background(), getui.LogWarn, "推送失败",
+		getui.LogField{Key: "endpoint", Value: "push_single"},
+		getui.LogField{Key: "requestid", Value: "req1"},
+		getui.LogField{Key: "target_count", Value: 2},
+	)
+
+	line := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "WARN", line["level"])
+	assert.Equal(t, "推送失败", line["msg"])
+	assert.Equal(t, "push_single", line["endpoint"])
+	assert.Equal(t, "req1", line["requestid"])
+	assert.Equal(t, float64(2), line["target_count"])
+}
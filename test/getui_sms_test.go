@@ -0,0 +1,74 @@
+package getui
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_SMSInfo 短信补量信息随推送发送，并校验等待时长与离线存储
+func Test_SMSInfo(t *testing.T) {
+	var sms []map[string]interface{}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") || strings.HasSuffix(req.URL.Path, "save_list_body") {
+			body := struct {
+				SMS map[string]interface{} `json:"sms_message"`
+			}{}
+			json.NewDecoder(req.Body).Decode(&body)
+			sms = append(sms, body.SMS)
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken","taskid":"testTaskID"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	info, err := getui.NewSMSInfo("tpl-otp", map[string]string{"code": "123456"}, 5*time.Minute)
+	assert.Nil(t, err)
+	_, err = getui.NewSMSInfo("tpl-otp", nil, 0)
+	assert.NotNil(t, err)
+
+	single := getui.SingleReqBody{CID: "8b4ed2bfa6b22aa3a1c8a8b6e1b6c8b1", SMS: info}
+	single.Message.MsgType = "notification"
+	assert.Nil(t, single.SetTTL(time.Hour))
+	_, err = client.PushToSingle(single)
+	assert.Nil(t, err)
+
+	list := getui.ListReqBody{CID: []string{"8b4ed2bfa6b22aa3a1c8a8b6e1b6c8b1"}, SMS: info}
+	list.Message.MsgType = "notification"
+	assert.Nil(t, list.SetTTL(time.Hour))
+	_, err = client.PushToList(list)
+	assert.Nil(t, err)
+
+	want := map[string]interface{}{
+		"sms_template_id":   "tpl-otp",
+		"sms_content_param": map[string]interface{}{"code": "123456"},
+		"offline_send_time": float64(300000),
+	}
+	assert.Equal(t, []map[string]interface{}{want, want}, sms)
+
+	// 等待时长超过离线时长时短信不会补发
+	assert.Nil(t, single.SetTTL(time.Minute))
+	_, err = client.PushToSingle(single)
+	assert.NotNil(t, err)
+
+	// 未开启离线存储
+	single.Message.IsOffline = false
+	single.Message.OfflineExpireTime = 0
+	_, err = client.PushToSingle(single)
+	assert.NotNil(t, err)
+
+	single.Message.IsOffline = true
+	single.SMS = &getui.SMSInfo{OfflineSendTime: 60000}
+	_, err = client.PushToSingle(single)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,78 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_SplitAudience 按比例确定性分组
+func Test_SplitAudience(t *testing.T) {
+	audience := make([]string, 10000)
+	for i := range audience {
+		audience[i] = fmt.Sprintf("%032x", i)
+	}
+	variants := []getui.Variant{{Name: "A"}, {Name: "B"}}
+
+	groups, err := getui.SplitAudience(audience, variants, []float64{1, 3})
+	assert.Nil(t, err)
+	assert.Equal(t, 10000, len(groups[0])+len(groups[1]))
+	assert.InDelta(t, 2500, len(groups[0]), 200)
+
+	again, err := getui.SplitAudience(audience, variants, []float64{1, 3})
+	assert.Nil(t, err)
+	assert.Equal(t, groups, again)
+
+	groups, err = getui.SplitAudience(audience, variants, []float64{0, 1})
+	assert.Nil(t, err)
+	assert.Len(t, groups[0], 0)
+
+	_, err = getui.SplitAudience(audience, variants, []float64{1})
+	assert.NotNil(t, err)
+	_, err = getui.SplitAudience(audience, []getui.Variant{{Name: "A"}, {Name: "A"}}, []float64{1, 1})
+	assert.NotNil(t, err)
+	_, err = getui.SplitAudience(audience, variants, []float64{0, 0})
+	assert.NotNil(t, err)
+}
+
+// Test_SplitPush 各版本使用自己的 group_name 与 taskid
+func Test_SplitPush(t *testing.T) {
+	var groupNames []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "save_list_body") {
+			body := struct {
+				GroupName string `json:"group_name"`
+			}{}
+			json.NewDecoder(req.Body).Decode(&body)
+			groupNames = append(groupNames, body.GroupName)
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"task-`+body.GroupName+`"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	audience := make([]string, 100)
+	for i := range audience {
+		audience[i] = fmt.Sprintf("%032x", i)
+	}
+	b := getui.Variant{Name: "B"}
+	b.Body.GroupName = "spring-b"
+	ret, err := client.SplitPush(context.Background(), audience, []getui.Variant{{Name: "A"}, b}, []float64{1, 1})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"A", "spring-b"}, groupNames)
+	assert.Equal(t, map[string]string{"A": "task-A", "B": "task-spring-b"}, ret.TaskIDs())
+	assert.Len(t, ret.Assignment, 100)
+	assert.Equal(t, len(ret.Groups[0].Targets)+len(ret.Groups[1].Targets), 100)
+}
@@ -0,0 +1,68 @@
+package getui
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_StartupAuthRetry 首次鉴权遇到网络错误时退避重试，凭证错误不重试
+func Test_StartupAuthRetry(t *testing.T) {
+	newTransport := func(failures int32, failResult string, calls *int32) roundTripFunc {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if strings.HasSuffix(req.URL.Path, "auth_sign") {
+				n := atomic.AddInt32(calls, 1)
+				if n <= failures {
+					if len(failResult) > 0 {
+						return jsonResponse(req, http.StatusOK, `{"result":"`+failResult+`"}`), nil
+					}
+					return nil, errors.New("connection reset by peer")
+				}
+			}
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+		})
+	}
+	params := func(transport roundTripFunc, timeout time.Duration) getui.InitParams {
+		return getui.InitParams{
+			AppID:              "testAppID",
+			AppKey:             "testAppKey",
+			MasterSecret:       "testMasterSecret",
+			HTTPClient:         &http.Client{Transport: transport},
+			RetryBackoff:       10 * time.Millisecond,
+			StartupAuthTimeout: timeout,
+		}
+	}
+
+	// 网络抖动后恢复
+	var calls int32
+	client, err := getui.New(params(newTransport(2, "", &calls), 2*time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, "testAuthToken", client.AuthToken())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// 默认不重试
+	calls = 0
+	_, err = getui.New(params(newTransport(1, "", &calls), 0))
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// 凭证错误不重试
+	calls = 0
+	_, err = getui.New(params(newTransport(1, "appkey_error", &calls), 2*time.Second))
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// 超时后返回最后一次的错误
+	calls = 0
+	start := time.Now()
+	_, err = getui.New(params(newTransport(1000, "", &calls), 100*time.Millisecond))
+	assert.NotNil(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Greater(t, atomic.LoadInt32(&calls), int32(1))
+}
@@ -0,0 +1,125 @@
+package getui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_MemoryStore 推送成功后保存发送结果，回执接口保存回执
+func Test_MemoryStore(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID","status":"successed_online"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	store := getui.NewMemoryStore()
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Store:        store,
+	})
+	assert.Nil(t, err)
+
+	cid := "0123456789abcdef0123456789abcdef"
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: cid})
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+	pushes, err := store.PushResults(ctx, getui.StoreQuery{TaskID: "testTaskID"})
+	assert.Nil(t, err)
+	assert.Len(t, pushes, 1)
+	assert.Equal(t, "push_single", pushes[0].Endpoint)
+	assert.Equal(t, []string{cid}, pushes[0].Targets)
+	assert.Equal(t, "successed_online", pushes[0].Status)
+
+	pushes, err = store.PushResults(ctx, getui.StoreQuery{Since: time.Now().Add(time.Minute)})
+	assert.Nil(t, err)
+	assert.Len(t, pushes, 0)
+
+	handler := getui.NewReceiptHandler(getui.ReceiptHandlerOptions{SkipVerify: true, Store: store})
+	for i, action := range []string{getui.ReceiptActionArrive, getui.ReceiptActionClick} {
+		body := fmt.Sprintf(`{"cid":"%s","taskid":"testTaskID","msgid":"m","actionId":"%s","recvtime":%d}`, cid, action, 1760576400000+i)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	receipts, err := store.Receipts(ctx, getui.StoreQuery{TaskID: "testTaskID", Limit: 1})
+	assert.Nil(t, err)
+	assert.Len(t, receipts, 1)
+	assert.Equal(t, getui.ReceiptArrived, receipts[0].Type())
+}
+
+// Test_TracePush 按requestid、taskid或任务组名查找发送结果与回执
+func Test_TracePush(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case strings.HasSuffix(req.URL.Path, "save_list_body"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"listTaskID"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_list"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_single"):
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"singleTaskID"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	store := getui.NewMemoryStore()
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Store:        store,
+	})
+	assert.Nil(t, err)
+
+	ctx := getui.WithRequestID(context.Background(), "req-list")
+	_, err = client.PushToListContext(ctx, getui.ListReqBody{GroupName: "spring", CID: []string{"0123456789abcdef0123456789abcdef"}})
+	assert.Nil(t, err)
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef", RequestID: "req-single"})
+	assert.Nil(t, err)
+	assert.Nil(t, store.SaveReceipt(context.Background(), getui.Receipt{TaskID: "listTaskID", ActionID: getui.ReceiptActionArrive}))
+
+	for _, id := range []string{"req-list", "listTaskID", "spring"} {
+		trace, err := getui.TracePush(context.Background(), store, id)
+		assert.Nil(t, err)
+		assert.Len(t, trace.Pushes, 1, id)
+		assert.Equal(t, "spring", trace.Pushes[0].GroupName)
+		assert.Equal(t, []string{"listTaskID"}, trace.TaskIDs())
+		assert.Len(t, trace.Receipts, 1, id)
+	}
+
+	trace, err := getui.TracePush(context.Background(), store, "req-single")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"singleTaskID"}, trace.TaskIDs())
+	assert.Len(t, trace.Receipts, 0)
+
+	_, err = getui.TracePush(context.Background(), store, "")
+	assert.NotNil(t, err)
+}
+
+// Test_RspStatus 单推返回的 status 与导出的常量一致
+func Test_RspStatus(t *testing.T) {
+	for _, status := range []string{getui.StatusSuccessOnline, getui.StatusSuccessOffline, getui.StatusSuccessIgnore} {
+		var ret getui.RspBody
+		assert.Nil(t, json.Unmarshal([]byte(`{"result":"ok","taskid":"t","status":"`+status+`"}`), &ret))
+		assert.Equal(t, status, ret.Status)
+	}
+	assert.Equal(t, "successed_online", getui.StatusSuccessOnline)
+	assert.Equal(t, "successed_offline", getui.StatusSuccessOffline)
+	assert.Equal(t, "successed_ignore", getui.StatusSuccessIgnore)
+}
@@ -0,0 +1,73 @@
+package getui
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_PushToTag 按配置选择条件推送或快速标签推送，快速推送被限流时改用条件推送
+func Test_PushToTag(t *testing.T) {
+	var mu sync.Mutex
+	var paths, bodies []string
+	fastLimited := false
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "auth_sign") {
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+		}
+		data, _ := io.ReadAll(req.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		paths = append(paths, req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:])
+		bodies = append(bodies, string(data))
+		if strings.HasSuffix(req.URL.Path, "push_by_tag") && fastLimited {
+			return jsonResponse(req, http.StatusTooManyRequests, `{"result":"flow_exceeded"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+	})
+
+	params := getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	}
+	client, err := getui.New(params)
+	assert.Nil(t, err)
+
+	n := getui.Notification{}
+	n.Style.Title = "标题"
+	ret, err := client.PushToTag("vip", n, getui.PushInfo{})
+	assert.Nil(t, err)
+	assert.Equal(t, "testTaskID", ret.TaskID)
+	assert.Equal(t, []string{"push_app"}, paths)
+	assert.Contains(t, bodies[0], `"condition":[{"key":"tag","values":["vip"],"opt_type":"0"}]`)
+	assert.NotContains(t, bodies[0], `push_info`)
+
+	params.TagPushMode = getui.TagPushFast
+	client, err = getui.New(params)
+	assert.Nil(t, err)
+
+	paths, bodies = nil, nil
+	info := getui.PushInfo{}
+	info.Aps.Alert.Title = "标题"
+	_, err = client.PushToTag("vip", n, info)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"push_by_tag"}, paths)
+	assert.Contains(t, bodies[0], `"fast_custom_tag":"vip"`)
+	assert.Contains(t, bodies[0], `"push_info"`)
+
+	paths, bodies = nil, nil
+	fastLimited = true
+	_, err = client.PushToTag("vip", n, info)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"push_by_tag", "push_app"}, paths)
+
+	_, err = client.PushToTag("", n, info)
+	assert.ErrorIs(t, err, getui.ErrInvalidTarget)
+}
@@ -0,0 +1,66 @@
+package getui
+
+import (
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_ParseTagExpr 标签表达式转换为toapp条件
+func Test_ParseTagExpr(t *testing.T) {
+	cases := []struct {
+		expr string
+		want getui.TagFilter
+	}{
+		{"vip AND active", getui.TagFilter{All: []string{"vip", "active"}}},
+		{"vip or svip", getui.TagFilter{Any: [][]string{{"vip", "svip"}}}},
+		{"(vip OR svip) AND NOT churned", getui.TagFilter{Any: [][]string{{"vip", "svip"}}, None: []string{"churned"}}},
+		{"NOT (churned OR banned) AND active", getui.TagFilter{All: []string{"active"}, None: []string{"churned", "banned"}}},
+		{"(a OR b) AND (c OR d)", getui.TagFilter{Any: [][]string{{"a", "b"}, {"c", "d"}}}},
+	}
+	for _, c := range cases {
+		f, err := getui.ParseTagExpr(c.expr)
+		assert.Nil(t, err, c.expr)
+		assert.Equal(t, c.want, *f, c.expr)
+	}
+
+	for _, expr := range []string{"", "(a AND b) OR c", "a OR b AND c", "a OR NOT b", "(a OR (b OR c))", "(a AND b", "a AND", "AND a"} {
+		_, err := getui.ParseTagExpr(expr)
+		assert.NotNil(t, err, expr)
+	}
+}
+
+// Test_TagFilterConditions 生成的条件与 opt_type
+func Test_TagFilterConditions(t *testing.T) {
+	f, err := getui.ParseTagExpr("(vip OR svip) AND active AND NOT churned")
+	assert.Nil(t, err)
+	conds, err := f.Conditions()
+	assert.Nil(t, err)
+	assert.Equal(t, []getui.AppReqBodyCondition{
+		{Key: "tag", Values: []string{"active"}, OptType: "1"},
+		{Key: "tag", Values: []string{"vip", "svip"}, OptType: "0"},
+		{Key: "tag", Values: []string{"churned"}, OptType: "2"},
+	}, conds)
+
+	_, err = getui.TagFilter{None: []string{"churned"}}.Conditions()
+	assert.ErrorIs(t, err, getui.ErrInvalidTarget)
+	_, err = getui.TagFilter{All: []string{""}}.Conditions()
+	assert.ErrorIs(t, err, getui.ErrInvalidTarget)
+}
+
+// Test_NewCondition 使用导出的常量构造条件
+func Test_NewCondition(t *testing.T) {
+	c := getui.NewCondition(getui.ConditionRegion, getui.OptTypeOr, "11000000", "31000000")
+	assert.Equal(t, getui.AppReqBodyCondition{Key: "region", Values: []string{"11000000", "31000000"}, OptType: "0"}, c)
+	assert.Equal(t, "2", getui.OptTypeNot)
+	assert.Equal(t, "tag", getui.ConditionTag)
+}
+
+// Test_PhoneType 手机类型常量与校验
+func Test_PhoneType(t *testing.T) {
+	assert.Nil(t, getui.ValidatePhoneType(getui.PhoneTypeAndroid))
+	assert.Nil(t, getui.ValidatePhoneType(getui.PhoneTypeIOS))
+	assert.ErrorIs(t, getui.ValidatePhoneType("ios"), getui.ErrInvalidTarget)
+	assert.ErrorIs(t, getui.ValidatePhoneType(""), getui.ErrInvalidTarget)
+}
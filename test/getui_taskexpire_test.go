@@ -0,0 +1,59 @@
+package getui
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_PushToListTaskExpired taskid失效时重新保存消息共同体并重试一次
+func Test_PushToListTaskExpired(t *testing.T) {
+	var mu sync.Mutex
+	saves, pushes := 0, []string{}
+	expireAll := false
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.HasSuffix(req.URL.Path, "save_list_body"):
+			saves++
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"newTaskID"}`), nil
+		case strings.HasSuffix(req.URL.Path, "push_list"):
+			body := getui.ListReqBody{}
+			json.NewDecoder(req.Body).Decode(&body)
+			pushes = append(pushes, body.TaskID)
+			if body.TaskID == "oldTaskID" || expireAll {
+				return jsonResponse(req, http.StatusOK, `{"result":"taskid_error"}`), nil
+			}
+			return jsonResponse(req, http.StatusOK, `{"result":"ok"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	body := getui.ListReqBody{CID: []string{"0123456789abcdef0123456789abcdef"}, TaskID: "oldTaskID"}
+	ret, err := client.PushToList(body)
+	assert.Nil(t, err)
+	assert.Equal(t, "newTaskID", ret.TaskID)
+	assert.Equal(t, 1, saves)
+	assert.Equal(t, []string{"oldTaskID", "newTaskID"}, pushes)
+
+	// 只重试一次
+	saves, pushes = 0, nil
+	expireAll = true
+	_, err = client.PushToList(body)
+	assert.ErrorIs(t, err, getui.ErrTaskNotFound)
+	assert.Equal(t, 1, saves)
+	assert.Len(t, pushes, 2)
+}
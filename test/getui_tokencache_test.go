@@ -0,0 +1,69 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// memTokenCache 内存实现的token缓存
+type memTokenCache struct {
+	mu         sync.Mutex
+	token      string
+	expireTime time.Time
+}
+
+func (m *memTokenCache) Get(ctx context.Context, appID string) (string, time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.token, m.expireTime, nil
+}
+
+func (m *memTokenCache) Set(ctx context.Context, appID string, token string, expireTime time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.token, m.expireTime = token, expireTime
+	return nil
+}
+
+// Test_TokenCache 多个实例共享token，只申请一次
+func Test_TokenCache(t *testing.T) {
+	var mu sync.Mutex
+	authCount := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "auth_sign") {
+			mu.Lock()
+			authCount++
+			mu.Unlock()
+		}
+		expire := time.Now().Add(24*time.Hour).UnixNano() / 1e6
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"sharedToken","expire_time":"`+
+			strconv.FormatInt(expire, 10)+`"}`), nil
+	})
+
+	cache := &memTokenCache{}
+	params := getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		TokenCache:   cache,
+		HTTPClient:   &http.Client{Transport: transport},
+	}
+
+	first, err := getui.New(params)
+	assert.Nil(t, err)
+	second, err := getui.New(params)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, authCount)
+	assert.Equal(t, "sharedToken", first.AuthToken())
+	assert.Equal(t, "sharedToken", second.AuthToken())
+	assert.Equal(t, first.AuthTokenExpireTime(), second.AuthTokenExpireTime())
+}
@@ -0,0 +1,40 @@
+package getui
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_UserAgent 所有请求带SDK标识，可追加应用标识
+func Test_UserAgent(t *testing.T) {
+	var agents []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		agents = append(agents, req.Header.Get("User-Agent"))
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"getui-go/" + getui.Version}, agents)
+
+	agents = nil
+	client, err = getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		UserAgent:    "order-service/1.2",
+	})
+	assert.Nil(t, err)
+	_, err = client.PushToSingle(getui.SingleReqBody{CID: "0123456789abcdef0123456789abcdef"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"getui-go/" + getui.Version + " order-service/1.2", "getui-go/" + getui.Version + " order-service/1.2"}, agents)
+}
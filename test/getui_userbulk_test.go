@@ -0,0 +1,80 @@
+package getui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_UserExistedBulk 并发查询用户是否存在，逐个cid报告失败
+func Test_UserExistedBulk(t *testing.T) {
+	var inflight, peak int32
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if !strings.Contains(req.URL.Path, "user_status/") {
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+		}
+
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		cid := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+		switch cid[0] {
+		case '0':
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","cid":"`+cid+`","status":"online"}`), nil
+		case 'f':
+			return jsonResponse(req, http.StatusOK, `{"result":"no_user"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"sign_error"}`), nil
+	})
+
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	cids := []string{
+		"00000000000000000000000000000001",
+		"00000000000000000000000000000002",
+		"00000000000000000000000000000002",
+		"ffffffffffffffffffffffffffffffff",
+		"a0000000000000000000000000000000",
+		"typo",
+	}
+	ret, err := client.UserExistedBulk(cids, 2)
+	assert.NotNil(t, err)
+	assert.Equal(t, map[string]bool{
+		"00000000000000000000000000000001": true,
+		"00000000000000000000000000000002": true,
+		"ffffffffffffffffffffffffffffffff": false,
+	}, ret)
+	assert.True(t, atomic.LoadInt32(&peak) <= 2)
+
+	var failed getui.CIDErrors
+	assert.True(t, errors.As(err, &failed))
+	assert.Len(t, failed, 2)
+	assert.Contains(t, failed, "typo")
+	assert.Contains(t, failed, "a0000000000000000000000000000000")
+
+	// ctx 已取消时不再查询，各cid以 ctx.Err() 记为失败
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ret, err = client.UserExistedBulkContext(ctx, cids[:2], 2)
+	assert.Len(t, ret, 0)
+	assert.True(t, errors.As(err, &failed))
+	assert.ErrorIs(t, failed["00000000000000000000000000000001"], context.Canceled)
+}
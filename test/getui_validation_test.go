@@ -0,0 +1,57 @@
+package getui
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_ValidationMode 严格模式拒绝不合规的请求，宽松模式只记录警告
+func Test_ValidationMode(t *testing.T) {
+	sent := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "push_single") {
+			sent++
+			return jsonResponse(req, http.StatusOK, `{"result":"ok","taskid":"testTaskID"}`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	logger := &memLogger{}
+	params := getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+		Logger:       logger,
+	}
+	body := getui.SingleReqBody{CID: "not-a-cid"}
+	body.PushInfo.Aps.AutoBadge = "one"
+
+	strict, err := getui.New(params)
+	assert.Nil(t, err)
+	_, err = strict.PushToSingle(body)
+	assert.ErrorIs(t, err, getui.ErrInvalidTarget)
+	assert.Equal(t, 0, sent)
+
+	params.ValidationMode = getui.ValidationLenient
+	lenient, err := getui.New(params)
+	assert.Nil(t, err)
+	_, err = lenient.PushToSingle(body)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+
+	warnings := 0
+	for _, line := range logger.lines {
+		if line["level"] == getui.LogWarn.String() && line["op"] == "PushToSingle" {
+			warnings++
+		}
+	}
+	assert.Equal(t, 2, warnings)
+
+	// 目标为空仍然报错
+	_, err = lenient.PushToSingle(getui.SingleReqBody{})
+	assert.ErrorIs(t, err, getui.ErrInvalidTarget)
+}
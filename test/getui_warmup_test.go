@@ -0,0 +1,37 @@
+package getui
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/printfcoder/getui"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_WarmUp 预热时向接口地址发送HEAD请求建立连接
+func Test_WarmUp(t *testing.T) {
+	var mu sync.Mutex
+	var heads []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodHead {
+			mu.Lock()
+			heads = append(heads, req.URL.String())
+			mu.Unlock()
+			return jsonResponse(req, http.StatusNotFound, ""), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"result":"ok","auth_token":"testAuthToken"}`), nil
+	})
+	client, err := getui.New(getui.InitParams{
+		AppID:        "testAppID",
+		AppKey:       "testAppKey",
+		MasterSecret: "testMasterSecret",
+		HTTPClient:   &http.Client{Transport: transport},
+	})
+	assert.Nil(t, err)
+
+	assert.Nil(t, client.WarmUp(context.Background()))
+	assert.Equal(t, []string{"https://restapi.getui.com/v1/"}, heads)
+	assert.Equal(t, "testAuthToken", client.AuthToken())
+}
@@ -5,6 +5,9 @@ package getui
 type SaveListBody struct {
 	Message      saveListBodymessage `json:"message"`
 	Notification Notification        `json:"notification"`
+	GroupName    string              `json:"group_name,omitempty"`
+	SMS          *SMSInfo            `json:"sms_message,omitempty"`
+	CallbackURL  string              `json:"callback_url,omitempty"`
 }
 
 type saveListBodymessage struct {
@@ -0,0 +1,16 @@
+package getui
+
+import (
+	"context"
+	"time"
+)
+
+// TokenCache 多副本共享的token缓存
+// 配置后，各副本刷新token时优先使用缓存中仍然有效的token，只有缓存缺失或即将过期时才向个推申请，
+// 并且不再在刷新前调用 CloseAuth，避免使其它副本正在使用的token失效
+type TokenCache interface {
+	// Get 读取appID对应的token，不存在时返回空token且err为nil
+	Get(ctx context.Context, appID string) (token string, expireTime time.Time, err error)
+	// Set 写入appID对应的token，expireTime 为零值表示个推未返回过期时间
+	Set(ctx context.Context, appID string, token string, expireTime time.Time) error
+}
@@ -0,0 +1,100 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultUserBulkConcurrency 批量查询用户的默认并发数
+const defaultUserBulkConcurrency = 8
+
+// CIDErrors 批量操作中失败的cid及原因
+type CIDErrors map[string]error
+
+// Error 实现 error，按cid排序列出前几个失败原因
+func (e CIDErrors) Error() string {
+	cids := make([]string, 0, len(e))
+	for cid := range e {
+		cids = append(cids, cid)
+	}
+	sort.Strings(cids)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d个cid失败", len(e))
+	for i, cid := range cids {
+		if i == 3 {
+			b.WriteString(", ...")
+			break
+		}
+		fmt.Fprintf(&b, ", %s: %s", cid, e[cid])
+	}
+	return b.String()
+}
+
+// UserExistedBulk 并发查询多个用户是否存在，concurrency <=0 时为8
+// 返回成功查询的cid的结果；有cid查询失败时同时返回 CIDErrors，可用 errors.As 取出逐个cid的原因
+func (c *client) UserExistedBulk(cids []string, concurrency int) (map[string]bool, error) {
+	return c.userExistedBulk(context.Background(), cids, concurrency)
+}
+
+// UserExistedBulkContext 同 UserExistedBulk，ctx 结束后不再查询剩余的cid，这些cid以 ctx.Err() 记入 CIDErrors
+func (c *client) UserExistedBulkContext(ctx context.Context, cids []string, concurrency int) (map[string]bool, error) {
+	c, err := c.forApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[UserExistedBulk] %w", err)
+	}
+	return c.userExistedBulk(ctx, cids, concurrency)
+}
+
+func (c *client) userExistedBulk(ctx context.Context, cids []string, concurrency int) (map[string]bool, error) {
+
+	if concurrency <= 0 {
+		concurrency = defaultUserBulkConcurrency
+	}
+
+	var mu sync.Mutex
+	ret := make(map[string]bool, len(cids))
+	failed := CIDErrors{}
+
+	work := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(cids); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for cid := range work {
+				var existed bool
+				err := ctx.Err()
+				if err == nil {
+					existed, err = c.userExisted(ctx, cid)
+				}
+				mu.Lock()
+				if err != nil {
+					failed[cid] = err
+				} else {
+					ret[cid] = existed
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[string]bool, len(cids))
+	for _, cid := range cids {
+		if seen[cid] {
+			continue
+		}
+		seen[cid] = true
+		work <- cid
+	}
+	close(work)
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return ret, fmt.Errorf("[UserExistedBulk] %w", failed)
+	}
+	return ret, nil
+}
@@ -0,0 +1,34 @@
+package getui
+
+import "context"
+
+// ValidationMode 请求前校验的处理方式
+type ValidationMode int
+
+const (
+	// ValidationStrict 校验不通过时返回错误，不发送请求
+	ValidationStrict ValidationMode = iota
+	// ValidationLenient 校验不通过时只通过 Logger 输出警告，继续发送，由个推决定是否接受
+	ValidationLenient
+)
+
+// String 模式名
+func (m ValidationMode) String() string {
+	switch m {
+	case ValidationStrict:
+		return "strict"
+	case ValidationLenient:
+		return "lenient"
+	}
+	return "unknown"
+}
+
+// checkValid 按 ValidationMode 处理校验错误，宽松模式下记录警告并返回nil
+// 目标为空等无法发送的错误不经过这里，总是返回
+func (c *client) checkValid(ctx context.Context, op string, err error) error {
+	if err == nil || c.ValidationMode != ValidationLenient {
+		return err
+	}
+	c.log(ctx, LogWarn, "校验不通过, 宽松模式下继续发送", LogField{"op", op}, LogField{"err", err.Error()})
+	return nil
+}
@@ -0,0 +1,73 @@
+// Package vaultcreds 从 HashiCorp Vault KV v2 读取个推凭证的 getui.CredentialsProvider 实现
+package vaultcreds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/printfcoder/getui"
+)
+
+// Provider Vault 凭证来源
+// 密钥中需包含 app_key、master_secret，可选 app_id
+type Provider struct {
+	addr  string
+	token string
+	path  string
+	// HTTPClient 访问Vault使用的http客户端，为空时使用 http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// New 创建Vault凭证来源
+// addr 如 https://vault.example.com:8200，path 为KV v2的数据路径，如 secret/data/getui/app1
+func New(addr, token, path string) *Provider {
+	return &Provider{
+		addr:  strings.TrimRight(addr, "/"),
+		token: token,
+		path:  strings.Trim(path, "/"),
+	}
+}
+
+// kvResponse KV v2 读取接口的返回
+type kvResponse struct {
+	Data struct {
+		Data getui.Credentials `json:"data"`
+	} `json:"data"`
+}
+
+// Credentials 实现 getui.CredentialsProvider
+func (p *Provider) Credentials(ctx context.Context) (getui.Credentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.addr+"/v1/"+p.path, nil)
+	if err != nil {
+		return getui.Credentials{}, fmt.Errorf("[vaultcreds] 创建请求失败, err: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	rsp, err := client.Do(req)
+	if err != nil {
+		return getui.Credentials{}, fmt.Errorf("[vaultcreds] 读取密钥失败, err: %w", err)
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, rsp.Body)
+		return getui.Credentials{}, fmt.Errorf("[vaultcreds] 读取密钥失败, http状态码 %d", rsp.StatusCode)
+	}
+	var ret kvResponse
+	err = json.NewDecoder(rsp.Body).Decode(&ret)
+	if err != nil {
+		return getui.Credentials{}, fmt.Errorf("[vaultcreds] 密钥格式错误, err: %w", err)
+	}
+	if len(ret.Data.Data.AppKey) == 0 {
+		return getui.Credentials{}, fmt.Errorf("[vaultcreds] 密钥 %s 中 app_key 为空", p.path)
+	}
+	return ret.Data.Data, nil
+}
@@ -0,0 +1,15 @@
+package getui
+
+// Version SDK版本
+const Version = "0.1.0"
+
+// sdkUserAgent SDK的 User-Agent
+const sdkUserAgent = "getui-go/" + Version
+
+// userAgent 请求的 User-Agent，配置了 UserAgent 时追加在SDK标识之后
+func (c *client) userAgent() string {
+	if len(c.UserAgent) == 0 {
+		return sdkUserAgent
+	}
+	return sdkUserAgent + " " + c.UserAgent
+}
@@ -0,0 +1,69 @@
+package getui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WarmUp 预先建立到个推的TLS连接并确保已有token，避免部署后第一条推送承担握手耗时
+// 连接数与应用的并发上限相同，未设置时为1；连接是否保留取决于 http.Transport 的空闲连接配置
+func (c *client) WarmUp(ctx context.Context) error {
+
+	err := c.ensureAuth(ctx)
+	if err != nil {
+		return fmt.Errorf("[WarmUp] 申请token失败, err: %w", err)
+	}
+
+	n := 1
+	if c.concurrency != nil {
+		n = cap(c.concurrency)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = c.warmUpConn(ctx)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return fmt.Errorf("[WarmUp] 建立连接失败, err: %w", err)
+		}
+	}
+	return nil
+}
+
+// warmUpConn 向可用的接口地址发送一次HEAD请求，只为建立连接，不关心返回的状态码
+func (c *client) warmUpConn(ctx context.Context) error {
+	var err error
+	for _, i := range c.hosts.order(time.Now()) {
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, http.MethodHead, c.hosts.urls[i], nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("User-Agent", c.userAgent())
+
+		var rsp *http.Response
+		rsp, err = c.httpClient().Do(req)
+		if !failover(ctx, err) {
+			if err != nil {
+				return err
+			}
+			c.hosts.markUp(i)
+			io.Copy(io.Discard, rsp.Body)
+			return rsp.Body.Close()
+		}
+		c.hosts.markDown(i, time.Now())
+	}
+	return err
+}
@@ -0,0 +1,43 @@
+// Package zaplog 将 getui.Logger 对接到 zap
+//
+//	client, err := getui.New(getui.InitParams{
+//		...
+//		Logger: zaplog.New(logger),
+//	})
+package zaplog
+
+import (
+	"context"
+
+	"github.com/printfcoder/getui"
+	"go.uber.org/zap"
+)
+
+// Logger zap日志适配器
+type Logger struct {
+	l *zap.Logger
+}
+
+// New 创建zap日志适配器，日志字段（endpoint、requestid、taskid、result 等）转为 zap.Field
+func New(l *zap.Logger) *Logger {
+	return &Logger{l: l}
+}
+
+// Log 实现 getui.Logger
+func (z *Logger) Log(ctx context.Context, level getui.LogLevel, msg string, fields ...getui.LogField) {
+	zf := make([]zap.Field, 0, len(fields))
+	for _, f := range fields {
+		zf = append(zf, zap.Any(f.Key, f.Value))
+	}
+
+	switch level {
+	case getui.LogDebug:
+		z.l.Debug(msg, zf...)
+	case getui.LogInfo:
+		z.l.Info(msg, zf...)
+	case getui.LogWarn:
+		z.l.Warn(msg, zf...)
+	default:
+		z.l.Error(msg, zf...)
+	}
+}